@@ -1,6 +1,43 @@
 db.createCollection("users");
 db.createCollection("chats");
+db.createCollection("folders");
+db.createCollection("shares");
+db.createCollection("templates");
+db.createCollection("template_versions");
+db.createCollection("assistants");
+db.createCollection("memories");
+db.createCollection("events");
+db.createCollection("tool_runs");
+db.createCollection("knowledge_bases");
+db.createCollection("knowledge_documents");
+db.createCollection("knowledge_chunks");
+db.createCollection("prompt_caches");
 
 db.users.createIndex({ email: 1 }, { unique: true });
 db.users.createIndex({ id: 1 }, { unique: true });
 db.chats.createIndex({ id: 1 }, { unique: true });
+db.chats.createIndex({ user: 1, pinned: -1, updated_at: -1 });
+db.folders.createIndex({ user: 1, name: 1 });
+db.chats.createIndex({ deleted_at: 1 }, { sparse: true });
+db.shares.createIndex({ token: 1 }, { unique: true });
+db.shares.createIndex({ chat_id: 1 });
+db.shares.createIndex({ expires_at: 1 }, { expireAfterSeconds: 0 });
+db.templates.createIndex({ user: 1, name: 1 });
+db.templates.createIndex({ shared: 1 });
+db.template_versions.createIndex({ template_id: 1, version: -1 }, { unique: true });
+db.assistants.createIndex({ user: 1, name: 1 });
+db.assistants.createIndex({ shared: 1 });
+db.memories.createIndex({ user: 1, updated_at: -1 });
+db.events.createIndex({ at: 1 }, { expireAfterSeconds: 86400 });
+db.chats.createIndex({ assistant_id: 1 }, { sparse: true });
+db.chats.createIndex({ "members.user_id": 1 }, { sparse: true });
+db.tool_runs.createIndex({ user: 1, started_at: -1 });
+db.tool_runs.createIndex({ user: 1, chat_id: 1, started_at: -1 });
+db.knowledge_bases.createIndex({ user: 1, name: 1 });
+db.knowledge_documents.createIndex({ user: 1, knowledge_base_id: 1, created_at: -1 });
+db.knowledge_documents.createIndex({ knowledge_base_id: 1 });
+db.knowledge_chunks.createIndex({ knowledge_base_id: 1 });
+db.knowledge_chunks.createIndex({ document_id: 1 });
+db.prompt_caches.createIndex({ scope: 1, platform: 1, model: 1 }, { unique: true });
+db.prompt_caches.createIndex({ expires_at: 1 });
+db.attachments.files.createIndex({ "metadata.chat_id": 1, "metadata.created_at": 1 });
@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+type ModelInfo struct {
+	Platform        string `json:"platform"`
+	Name            string `json:"name"`
+	ContextWindow   int    `json:"context_window"`
+	MaxOutputTokens int    `json:"max_output_tokens"`
+	Vision          bool   `json:"vision"`
+	// CacheMinTokens is the smallest prompt prefix the router creates a
+	// provider-side cache for. It is zero on platforms that cache prompts by
+	// themselves.
+	CacheMinTokens int `json:"-"`
+	// CharsPerToken and MessageOverhead drive the token estimate. They are
+	// rough averages for English text, not exact tokenizer output.
+	CharsPerToken   float64 `json:"-"`
+	MessageOverhead int     `json:"-"`
+}
+
+// ImageTokens is a flat per-image estimate. Providers bill images by size and
+// detail, so this only keeps the context budget honest.
+const ImageTokens = 800
+
+var defaults = map[string]ModelInfo{
+	"openai":   {ContextWindow: 128000, MaxOutputTokens: 4096, CharsPerToken: 4, MessageOverhead: 4},
+	"deepseek": {ContextWindow: 64000, MaxOutputTokens: 8192, CharsPerToken: 3.5, MessageOverhead: 4},
+	"gemini":   {ContextWindow: 1048576, MaxOutputTokens: 8192, CharsPerToken: 4, MessageOverhead: 3, Vision: true, CacheMinTokens: 4096},
+}
+
+var known = []ModelInfo{
+	{Platform: "openai", Name: "gpt-3.5-turbo", ContextWindow: 16385, MaxOutputTokens: 4096},
+	{Platform: "openai", Name: "gpt-4", ContextWindow: 8192, MaxOutputTokens: 4096},
+	{Platform: "openai", Name: "gpt-4-turbo", ContextWindow: 128000, MaxOutputTokens: 4096, Vision: true},
+	{Platform: "openai", Name: "gpt-4o", ContextWindow: 128000, MaxOutputTokens: 16384, Vision: true},
+	{Platform: "openai", Name: "gpt-4o-mini", ContextWindow: 128000, MaxOutputTokens: 16384, Vision: true},
+	{Platform: "openai", Name: "gpt-4.1", ContextWindow: 1047576, MaxOutputTokens: 32768, Vision: true},
+	{Platform: "openai", Name: "o1", ContextWindow: 200000, MaxOutputTokens: 100000, Vision: true},
+	{Platform: "openai", Name: "o3-mini", ContextWindow: 200000, MaxOutputTokens: 100000},
+	{Platform: "deepseek", Name: "deepseek-chat", ContextWindow: 64000, MaxOutputTokens: 8192},
+	{Platform: "deepseek", Name: "deepseek-reasoner", ContextWindow: 64000, MaxOutputTokens: 8192},
+	{Platform: "gemini", Name: "gemini-1.5-flash", ContextWindow: 1048576, MaxOutputTokens: 8192, Vision: true},
+	{Platform: "gemini", Name: "gemini-1.5-pro", ContextWindow: 2097152, MaxOutputTokens: 8192, Vision: true},
+	{Platform: "gemini", Name: "gemini-2.0-flash", ContextWindow: 1048576, MaxOutputTokens: 8192, Vision: true},
+	{Platform: "gemini", Name: "gemini-2.5-flash", ContextWindow: 1048576, MaxOutputTokens: 65536, Vision: true,
+		CacheMinTokens: 1024},
+	{Platform: "gemini", Name: "gemini-2.5-pro", ContextWindow: 1048576, MaxOutputTokens: 65536, Vision: true},
+	{Platform: "gemini", Name: "gemini-pro", ContextWindow: 32760, MaxOutputTokens: 8192},
+}
+
+func KnownPlatform(platform string) bool {
+	_, ok := defaults[platform]
+	return ok
+}
+
+// Lookup returns the metadata for a model. Dated or suffixed model names such
+// as "gpt-4o-2024-08-06" resolve to the longest known prefix, and unknown
+// models fall back to the platform defaults.
+func Lookup(platform string, model string) ModelInfo {
+	info := ModelInfo{Platform: platform, Name: model}
+	base, ok := defaults[platform]
+	if !ok {
+		base = ModelInfo{ContextWindow: 8192, MaxOutputTokens: 2048, CharsPerToken: 4, MessageOverhead: 4}
+	}
+	info.ContextWindow = base.ContextWindow
+	info.MaxOutputTokens = base.MaxOutputTokens
+	info.CharsPerToken = base.CharsPerToken
+	info.MessageOverhead = base.MessageOverhead
+	info.Vision = base.Vision
+	info.CacheMinTokens = base.CacheMinTokens
+
+	var match *ModelInfo
+	for i := range known {
+		candidate := &known[i]
+		if candidate.Platform != platform || !strings.HasPrefix(model, candidate.Name) {
+			continue
+		}
+		if match == nil || len(candidate.Name) > len(match.Name) {
+			match = candidate
+		}
+	}
+	if match != nil {
+		info.ContextWindow = match.ContextWindow
+		info.MaxOutputTokens = match.MaxOutputTokens
+		info.Vision = match.Vision
+		if match.CacheMinTokens > 0 {
+			info.CacheMinTokens = match.CacheMinTokens
+		}
+	}
+	return info
+}
+
+func (m ModelInfo) EstimateTokens(text string) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / m.CharsPerToken))
+}
+
+func (m ModelInfo) EstimateMessageTokens(text string) int {
+	return m.EstimateTokens(text) + m.MessageOverhead
+}
+
+// InputBudget is the number of tokens available for the prompt once room for
+// the model's reply has been reserved.
+func (m ModelInfo) InputBudget() int {
+	return m.ContextWindow - m.MaxOutputTokens
+}
+
+// DocumentBudget is the number of tokens attached documents may take up in a
+// prompt. Half of the input budget is left for the conversation itself.
+func (m ModelInfo) DocumentBudget() int {
+	return m.InputBudget() / 2
+}
@@ -0,0 +1,113 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name              string
+		platform          string
+		model             string
+		wantContextWindow int
+	}{
+		{name: "exact match", platform: "openai", model: "gpt-4o", wantContextWindow: 128000},
+		{name: "dated model", platform: "openai", model: "gpt-4o-2024-08-06", wantContextWindow: 128000},
+		{name: "longest prefix wins", platform: "openai", model: "gpt-4-turbo-preview", wantContextWindow: 128000},
+		{name: "short prefix", platform: "openai", model: "gpt-4-0613", wantContextWindow: 8192},
+		{name: "unknown model uses platform default", platform: "deepseek", model: "deepseek-v9", wantContextWindow: 64000},
+		{name: "unknown platform", platform: "other", model: "x", wantContextWindow: 8192},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := catalog.Lookup(tt.platform, tt.model)
+			assert.Equal(t, tt.wantContextWindow, info.ContextWindow)
+			assert.Equal(t, tt.model, info.Name)
+			assert.Positive(t, info.InputBudget())
+		})
+	}
+}
+
+func TestModelInfo_EstimateTokens(t *testing.T) {
+	info := catalog.Lookup("openai", "gpt-4o")
+
+	assert.Equal(t, 0, info.EstimateTokens(""))
+	assert.Equal(t, 3, info.EstimateTokens("hello world"))
+	assert.Equal(t, 7, info.EstimateMessageTokens("hello world"))
+}
+
+func TestLookup_Vision(t *testing.T) {
+	tests := []struct {
+		platform string
+		model    string
+		want     bool
+	}{
+		{platform: "openai", model: "gpt-4o-2024-08-06", want: true},
+		{platform: "openai", model: "gpt-4", want: false},
+		{platform: "openai", model: "gpt-3.5-turbo", want: false},
+		{platform: "deepseek", model: "deepseek-chat", want: false},
+		{platform: "gemini", model: "gemini-2.0-flash", want: true},
+		{platform: "gemini", model: "gemini-exp-1206", want: true},
+		{platform: "other", model: "x", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform+"/"+tt.model, func(t *testing.T) {
+			assert.Equal(t, tt.want, catalog.Lookup(tt.platform, tt.model).Vision)
+		})
+	}
+}
+
+func TestModelInfo_DocumentBudget(t *testing.T) {
+	small := catalog.Lookup("openai", "gpt-4")
+	large := catalog.Lookup("gemini", "gemini-1.5-pro")
+
+	assert.Equal(t, small.InputBudget()/2, small.DocumentBudget())
+	assert.Greater(t, large.DocumentBudget(), small.DocumentBudget())
+}
+
+func TestLookupEmbedding(t *testing.T) {
+	info, ok := catalog.LookupEmbedding("openai", "text-embedding-3-large")
+	assert.True(t, ok)
+	assert.Equal(t, 3072, info.Dimensions)
+	assert.True(t, info.Shortenable)
+
+	info, ok = catalog.LookupEmbedding("gemini", "embedding-001")
+	assert.True(t, ok)
+	assert.Equal(t, 768, info.Dimensions)
+	assert.False(t, info.Shortenable)
+
+	info, ok = catalog.LookupEmbedding("gemini", "text-embedding-005")
+	assert.True(t, ok, "unknown models use the platform defaults")
+	assert.Equal(t, 2048, info.MaxInputTokens)
+
+	_, ok = catalog.LookupEmbedding("deepseek", "deepseek-chat")
+	assert.False(t, ok)
+}
+
+func TestLookupImage(t *testing.T) {
+	info, ok := catalog.LookupImage("openai", "dall-e-3")
+	assert.True(t, ok)
+	assert.Equal(t, 1, info.MaxCount)
+	assert.Equal(t, []string{"vivid", "natural"}, info.Styles)
+
+	info, ok = catalog.LookupImage("gemini", "imagen-3.0-generate-002")
+	assert.True(t, ok)
+	assert.Equal(t, 4, info.MaxCount)
+	assert.Contains(t, info.Sizes, "16:9")
+	assert.True(t, info.PromptStyle)
+
+	_, ok = catalog.LookupImage("deepseek", "deepseek-chat")
+	assert.False(t, ok)
+}
+
+func TestLookup_CacheMinTokens(t *testing.T) {
+	assert.Equal(t, 4096, catalog.Lookup("gemini", "gemini-2.0-flash").CacheMinTokens)
+	assert.Equal(t, 1024, catalog.Lookup("gemini", "gemini-2.5-flash-preview-05-20").CacheMinTokens)
+	assert.Equal(t, 4096, catalog.Lookup("gemini", "gemini-2.5-pro").CacheMinTokens)
+	assert.Zero(t, catalog.Lookup("openai", "gpt-4o").CacheMinTokens)
+}
@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+type EmbeddingModelInfo struct {
+	Platform       string `json:"platform"`
+	Name           string `json:"name"`
+	Dimensions     int    `json:"dimensions"`
+	MaxInputTokens int    `json:"max_input_tokens"`
+	// Shortenable models can return fewer dimensions than their default.
+	Shortenable bool `json:"shortenable"`
+	// MaxBatch and MaxBatchTokens limit a single provider request. Zero
+	// means no token limit.
+	MaxBatch       int     `json:"-"`
+	MaxBatchTokens int     `json:"-"`
+	CharsPerToken  float64 `json:"-"`
+}
+
+var embeddingDefaults = map[string]EmbeddingModelInfo{
+	"openai": {Dimensions: 1536, MaxInputTokens: 8191, Shortenable: true, MaxBatch: 2048, MaxBatchTokens: 300000, CharsPerToken: 4},
+	"gemini": {Dimensions: 768, MaxInputTokens: 2048, Shortenable: true, MaxBatch: 100, CharsPerToken: 4},
+}
+
+var knownEmbeddings = []EmbeddingModelInfo{
+	{Platform: "openai", Name: "text-embedding-3-small", Dimensions: 1536, MaxInputTokens: 8191, Shortenable: true},
+	{Platform: "openai", Name: "text-embedding-3-large", Dimensions: 3072, MaxInputTokens: 8191, Shortenable: true},
+	{Platform: "openai", Name: "text-embedding-ada-002", Dimensions: 1536, MaxInputTokens: 8191},
+	{Platform: "gemini", Name: "text-embedding-004", Dimensions: 768, MaxInputTokens: 2048, Shortenable: true},
+	{Platform: "gemini", Name: "gemini-embedding-001", Dimensions: 3072, MaxInputTokens: 2048, Shortenable: true},
+	{Platform: "gemini", Name: "embedding-001", Dimensions: 768, MaxInputTokens: 2048},
+}
+
+// LookupEmbedding returns the metadata for an embedding model, resolving
+// names the same way as Lookup. It reports false for platforms without
+// embeddings.
+func LookupEmbedding(platform string, model string) (EmbeddingModelInfo, bool) {
+	info, ok := embeddingDefaults[platform]
+	if !ok {
+		return EmbeddingModelInfo{}, false
+	}
+	info.Platform = platform
+	info.Name = model
+
+	var match *EmbeddingModelInfo
+	for i := range knownEmbeddings {
+		candidate := &knownEmbeddings[i]
+		if candidate.Platform != platform || !strings.HasPrefix(model, candidate.Name) {
+			continue
+		}
+		if match == nil || len(candidate.Name) > len(match.Name) {
+			match = candidate
+		}
+	}
+	if match != nil {
+		info.Dimensions = match.Dimensions
+		info.MaxInputTokens = match.MaxInputTokens
+		info.Shortenable = match.Shortenable
+	}
+	return info, true
+}
+
+func (m EmbeddingModelInfo) EstimateTokens(text string) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / m.CharsPerToken))
+}
@@ -0,0 +1,61 @@
+package catalog
+
+import "strings"
+
+type ImageModelInfo struct {
+	Platform string `json:"platform"`
+	Name     string `json:"name"`
+	// Sizes are the accepted sizes, the first being the default. Imagen
+	// models take aspect ratios instead of pixel sizes.
+	Sizes    []string `json:"sizes"`
+	MaxCount int      `json:"max_count"`
+	// Styles are the styles the provider accepts. Models with PromptStyle
+	// take any style, which is added to the prompt instead.
+	Styles      []string `json:"styles,omitempty"`
+	PromptStyle bool     `json:"prompt_style,omitempty"`
+}
+
+var imagenAspectRatios = []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+
+var imageDefaults = map[string]ImageModelInfo{
+	"openai": {Sizes: []string{"1024x1024"}, MaxCount: 1},
+	"gemini": {Sizes: imagenAspectRatios, MaxCount: 4, PromptStyle: true},
+}
+
+var knownImageModels = []ImageModelInfo{
+	{Platform: "openai", Name: "dall-e-2", Sizes: []string{"1024x1024", "512x512", "256x256"}, MaxCount: 10},
+	{Platform: "openai", Name: "dall-e-3", Sizes: []string{"1024x1024", "1792x1024", "1024x1792"}, MaxCount: 1,
+		Styles: []string{"vivid", "natural"}},
+	{Platform: "openai", Name: "gpt-image-1", Sizes: []string{"1024x1024", "1536x1024", "1024x1536"}, MaxCount: 10},
+	{Platform: "gemini", Name: "imagen-3.0", Sizes: imagenAspectRatios, MaxCount: 4, PromptStyle: true},
+	{Platform: "gemini", Name: "imagen-4.0", Sizes: imagenAspectRatios, MaxCount: 4, PromptStyle: true},
+}
+
+// LookupImage returns the metadata for an image model, resolving names the
+// same way as Lookup. It reports false for platforms without image models.
+func LookupImage(platform string, model string) (ImageModelInfo, bool) {
+	info, ok := imageDefaults[platform]
+	if !ok {
+		return ImageModelInfo{}, false
+	}
+	info.Platform = platform
+	info.Name = model
+
+	var match *ImageModelInfo
+	for i := range knownImageModels {
+		candidate := &knownImageModels[i]
+		if candidate.Platform != platform || !strings.HasPrefix(model, candidate.Name) {
+			continue
+		}
+		if match == nil || len(candidate.Name) > len(match.Name) {
+			match = candidate
+		}
+	}
+	if match != nil {
+		info.Sizes = match.Sizes
+		info.MaxCount = match.MaxCount
+		info.Styles = match.Styles
+		info.PromptStyle = match.PromptStyle
+	}
+	return info, true
+}
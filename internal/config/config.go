@@ -5,24 +5,42 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	ServerPort         int
-	OPENAI_SK          string
-	DEEPSEEK_SK        string
-	GEMINI_SK          string
-	MongoDBURI         string
-	MongoDBDatabase    string
-	GoogleClientID     string
-	GoogleClientSecret string
-	JWTSecret          string
-	ClientURL          string
-	AuthRedirectURL    string
-	AndroidClientID    string
-	WorkerID           int64
+	ServerPort            int
+	OPENAI_SK             string
+	DEEPSEEK_SK           string
+	GEMINI_SK             string
+	MongoDBURI            string
+	MongoDBDatabase       string
+	GoogleClientID        string
+	GoogleClientSecret    string
+	JWTSecret             string
+	ClientURL             string
+	AuthRedirectURL       string
+	AndroidClientID       string
+	WorkerID              int64
+	TitlePlatform         string
+	TitleModel            string
+	TrashRetention        time.Duration
+	SummaryPlatform       string
+	SummaryModel          string
+	ContextStrategy       string
+	ContextLastTurns      int
+	SummaryEvery          int
+	MemoryTokenBudget     int
+	MemoryIdle            time.Duration
+	EventsBackend         string
+	ToolMaxSteps          int
+	OutputRetries         int
+	RAGTopK               int
+	TranscriptionPlatform string
+	TranscriptionModel    string
+	PromptCacheTTL        time.Duration
 }
 
 func LoadConfig(skipEnvFile ...bool) (*Config, error) {
@@ -100,5 +118,139 @@ func LoadConfig(skipEnvFile ...bool) (*Config, error) {
 	if config.AndroidClientID == "" {
 		return nil, fmt.Errorf("ANDROID_CLIENT_ID environment variable is not set")
 	}
+
+	config.TitlePlatform = os.Getenv("TITLE_PLATFORM")
+	if config.TitlePlatform == "" {
+		config.TitlePlatform = "openai"
+	}
+
+	config.TitleModel = os.Getenv("TITLE_MODEL")
+	if config.TitleModel == "" {
+		config.TitleModel = "gpt-4o-mini"
+	}
+
+	retentionDays := 30
+	if retention := os.Getenv("TRASH_RETENTION_DAYS"); retention != "" {
+		retentionDays, err = strconv.Atoi(retention)
+		if err != nil || retentionDays < 1 {
+			return nil, fmt.Errorf("TRASH_RETENTION_DAYS must be a positive number")
+		}
+	}
+	config.TrashRetention = time.Duration(retentionDays) * 24 * time.Hour
+
+	config.SummaryPlatform = os.Getenv("SUMMARY_PLATFORM")
+	if config.SummaryPlatform == "" {
+		config.SummaryPlatform = config.TitlePlatform
+	}
+
+	config.SummaryModel = os.Getenv("SUMMARY_MODEL")
+	if config.SummaryModel == "" {
+		config.SummaryModel = config.TitleModel
+	}
+
+	config.ContextStrategy = os.Getenv("CONTEXT_STRATEGY")
+	switch config.ContextStrategy {
+	case "":
+		config.ContextStrategy = "drop_oldest"
+	case "drop_oldest", "last_turns", "summarize":
+	default:
+		return nil, fmt.Errorf("CONTEXT_STRATEGY must be one of drop_oldest, last_turns or summarize")
+	}
+
+	config.ContextLastTurns, err = strconv.Atoi(os.Getenv("CONTEXT_LAST_TURNS"))
+	if err != nil || config.ContextLastTurns < 1 {
+		config.ContextLastTurns = 10
+	}
+
+	summaryEvery := os.Getenv("SUMMARY_EVERY")
+	if summaryEvery == "" {
+		config.SummaryEvery = 20
+	} else {
+		config.SummaryEvery, err = strconv.Atoi(summaryEvery)
+		if err != nil || config.SummaryEvery < 0 {
+			return nil, fmt.Errorf("SUMMARY_EVERY must be a non-negative number")
+		}
+	}
+
+	memoryBudget := os.Getenv("MEMORY_TOKEN_BUDGET")
+	if memoryBudget == "" {
+		config.MemoryTokenBudget = 500
+	} else {
+		config.MemoryTokenBudget, err = strconv.Atoi(memoryBudget)
+		if err != nil || config.MemoryTokenBudget < 0 {
+			return nil, fmt.Errorf("MEMORY_TOKEN_BUDGET must be a non-negative number")
+		}
+	}
+
+	memoryIdle := os.Getenv("MEMORY_EXTRACTION_IDLE_MINUTES")
+	if memoryIdle == "" {
+		config.MemoryIdle = 30 * time.Minute
+	} else {
+		idleMinutes, err := strconv.Atoi(memoryIdle)
+		if err != nil || idleMinutes < 0 {
+			return nil, fmt.Errorf("MEMORY_EXTRACTION_IDLE_MINUTES must be a non-negative number")
+		}
+		config.MemoryIdle = time.Duration(idleMinutes) * time.Minute
+	}
+
+	config.EventsBackend = os.Getenv("EVENTS_BACKEND")
+	switch config.EventsBackend {
+	case "":
+		config.EventsBackend = "memory"
+	case "memory", "mongo":
+	default:
+		return nil, fmt.Errorf("EVENTS_BACKEND must be memory or mongo")
+	}
+
+	toolMaxSteps := os.Getenv("TOOL_MAX_STEPS")
+	if toolMaxSteps == "" {
+		config.ToolMaxSteps = 5
+	} else {
+		config.ToolMaxSteps, err = strconv.Atoi(toolMaxSteps)
+		if err != nil || config.ToolMaxSteps < 1 {
+			return nil, fmt.Errorf("TOOL_MAX_STEPS must be a positive number")
+		}
+	}
+
+	outputRetries := os.Getenv("STRUCTURED_OUTPUT_RETRIES")
+	if outputRetries == "" {
+		config.OutputRetries = 2
+	} else {
+		config.OutputRetries, err = strconv.Atoi(outputRetries)
+		if err != nil || config.OutputRetries < 0 {
+			return nil, fmt.Errorf("STRUCTURED_OUTPUT_RETRIES must be a non-negative number")
+		}
+	}
+
+	ragTopK := os.Getenv("RAG_TOP_K")
+	if ragTopK == "" {
+		config.RAGTopK = 5
+	} else {
+		config.RAGTopK, err = strconv.Atoi(ragTopK)
+		if err != nil || config.RAGTopK < 1 || config.RAGTopK > 50 {
+			return nil, fmt.Errorf("RAG_TOP_K must be a number between 1 and 50")
+		}
+	}
+
+	config.TranscriptionPlatform = os.Getenv("TRANSCRIPTION_PLATFORM")
+	if config.TranscriptionPlatform == "" {
+		config.TranscriptionPlatform = "openai"
+	}
+
+	config.TranscriptionModel = os.Getenv("TRANSCRIPTION_MODEL")
+	if config.TranscriptionModel == "" {
+		config.TranscriptionModel = "whisper-1"
+	}
+
+	promptCacheTTL := os.Getenv("PROMPT_CACHE_TTL_MINUTES")
+	if promptCacheTTL == "" {
+		config.PromptCacheTTL = time.Hour
+	} else {
+		ttlMinutes, err := strconv.Atoi(promptCacheTTL)
+		if err != nil || ttlMinutes < 0 {
+			return nil, fmt.Errorf("PROMPT_CACHE_TTL_MINUTES must be a non-negative number")
+		}
+		config.PromptCacheTTL = time.Duration(ttlMinutes) * time.Minute
+	}
 	return config, nil
 }
@@ -132,6 +132,25 @@ func TestLoadConfig(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "invalid TRASH_RETENTION_DAYS",
+			envVars: map[string]string{
+				"SERVER_PORT":          "8080",
+				"OPENAI_SK":            "sk-123",
+				"DEEPSEEK_SK":          "sk-456",
+				"GEMINI_SK":            "sk-789",
+				"MONGODB_URI":          "mongodb://localhost:27017",
+				"MONGODB_DATABASE":     "ai_router",
+				"GOOGLE_CLIENT_ID":     "client-123",
+				"GOOGLE_CLIENT_SECRET": "secret-456",
+				"JWT_SECRET":           "jwt-secret-789",
+				"CLIENT_URL":           "http://localhost:3000",
+				"AUTH_REDIRECT_URL":    "http://localhost:8080/callback",
+				"ANDROID_CLIENT_ID":    "client-123",
+				"TRASH_RETENTION_DAYS": "0",
+			},
+			expectError: true,
+		},
 		{
 			name: "missing mobile client ID",
 			envVars: map[string]string{
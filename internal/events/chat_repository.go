@@ -0,0 +1,185 @@
+package events
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event *models.ChatEvent) error
+}
+
+// ChatRepository publishes an event for every successful write to the chat
+// repository it wraps, so changes made by any service reach the event feed.
+// Events are built from the chats the writes return. Clearing a folder or an
+// assistant from many chats at once and memory extraction bookkeeping are not
+// reported.
+type ChatRepository struct {
+	repository.ChatRepositoryInterface
+	publisher Publisher
+}
+
+func NewChatRepository(chatRepo repository.ChatRepositoryInterface, publisher Publisher) *ChatRepository {
+	return &ChatRepository{
+		ChatRepositoryInterface: chatRepo,
+		publisher:               publisher,
+	}
+}
+
+func (r *ChatRepository) CreateChat(ctx context.Context, chat *models.Chat) error {
+	if err := r.ChatRepositoryInterface.CreateChat(ctx, chat); err != nil {
+		return err
+	}
+	r.publishChat(ctx, models.EventChatCreated, chat, audience(chat))
+	return nil
+}
+
+// UpdateChat reports the messages that were not in the stored chat before the
+// update, followed by the updated chat.
+func (r *ChatRepository) UpdateChat(ctx context.Context, chat *models.Chat) (*models.Chat, error) {
+	previous, err := r.ChatRepositoryInterface.UpdateChat(ctx, chat)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(previous.Messages))
+	for _, message := range previous.Messages {
+		known[message.ID] = true
+	}
+	for _, message := range chat.Messages {
+		if !known[message.ID] {
+			r.publish(ctx, &models.ChatEvent{
+				Type:    models.EventMessageAdded,
+				ChatID:  chat.ID,
+				Message: &message,
+				UserIDs: audience(chat),
+			})
+		}
+	}
+	r.publishChat(ctx, models.EventChatUpdated, chat, audience(chat))
+	return previous, nil
+}
+
+func (r *ChatRepository) UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) ([]*models.Chat, error) {
+	chats, err := r.ChatRepositoryInterface.UpdateChats(ctx, userID, chatIDs, update)
+	for _, chat := range chats {
+		r.publishChat(ctx, models.EventChatUpdated, chat, audience(chat))
+	}
+	return chats, err
+}
+
+func (r *ChatRepository) DeleteChat(ctx context.Context, chatID string, version int64) (*models.Chat, error) {
+	chat, err := r.ChatRepositoryInterface.DeleteChat(ctx, chatID, version)
+	if err != nil {
+		return nil, err
+	}
+	r.publishDeleted(ctx, chat, audience(chat))
+	return chat, nil
+}
+
+func (r *ChatRepository) DeleteChats(ctx context.Context, userID string, chatIDs []string) ([]*models.Chat, error) {
+	chats, err := r.ChatRepositoryInterface.DeleteChats(ctx, userID, chatIDs)
+	for _, chat := range chats {
+		r.publishDeleted(ctx, chat, audience(chat))
+	}
+	return chats, err
+}
+
+// RestoreChat reports the chat as created again, as it comes back into the
+// chat lists of its members.
+func (r *ChatRepository) RestoreChat(ctx context.Context, userID string, chatID string) (*models.Chat, error) {
+	chat, err := r.ChatRepositoryInterface.RestoreChat(ctx, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+	r.publishChat(ctx, models.EventChatCreated, chat, audience(chat))
+	return chat, nil
+}
+
+func (r *ChatRepository) SetSummary(ctx context.Context, chatID string, summary *models.Summary) (*models.Chat, error) {
+	chat, err := r.ChatRepositoryInterface.SetSummary(ctx, chatID, summary)
+	if err != nil {
+		return nil, err
+	}
+	r.publishChat(ctx, models.EventChatUpdated, chat, audience(chat))
+	return chat, nil
+}
+
+// SetMembers reports the chat as created for new members and deleted for
+// removed ones.
+func (r *ChatRepository) SetMembers(ctx context.Context, chatID string, version int64,
+	members []models.ChatMember) (*models.Chat, error) {
+	previous, err := r.ChatRepositoryInterface.SetMembers(ctx, chatID, version, members)
+	if err != nil {
+		return nil, err
+	}
+
+	chat := *previous
+	chat.Members = members
+	chat.Version++
+
+	var added, removed, kept []string
+	for _, userID := range audience(&chat) {
+		if slices.Contains(audience(previous), userID) {
+			kept = append(kept, userID)
+		} else {
+			added = append(added, userID)
+		}
+	}
+	for _, userID := range audience(previous) {
+		if !slices.Contains(kept, userID) {
+			removed = append(removed, userID)
+		}
+	}
+
+	r.publishChat(ctx, models.EventChatUpdated, &chat, kept)
+	r.publishChat(ctx, models.EventChatCreated, &chat, added)
+	r.publishDeleted(ctx, &chat, removed)
+	return previous, nil
+}
+
+// publishChat sends the chat without its messages, which clients receive
+// one by one as message.added events.
+func (r *ChatRepository) publishChat(ctx context.Context, eventType string, chat *models.Chat, userIDs []string) {
+	snapshot := *chat
+	snapshot.Messages = nil
+	r.publish(ctx, &models.ChatEvent{
+		Type:    eventType,
+		ChatID:  chat.ID,
+		Chat:    &snapshot,
+		UserIDs: userIDs,
+	})
+}
+
+func (r *ChatRepository) publishDeleted(ctx context.Context, chat *models.Chat, userIDs []string) {
+	r.publish(ctx, &models.ChatEvent{
+		Type:    models.EventChatDeleted,
+		ChatID:  chat.ID,
+		UserIDs: userIDs,
+	})
+}
+
+// publish never fails the write that caused the event; a lost event is
+// recovered by clients reloading after a reset.
+func (r *ChatRepository) publish(ctx context.Context, event *models.ChatEvent) {
+	if len(event.UserIDs) == 0 {
+		return
+	}
+	event.At = time.Now()
+	if err := r.publisher.Publish(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("Error publishing %s event for chat %s: %v", event.Type, event.ChatID, err)
+	}
+}
+
+func audience(chat *models.Chat) []string {
+	userIDs := []string{chat.User}
+	for _, member := range chat.Members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	return userIDs
+}
@@ -0,0 +1,143 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/events"
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type recordingPublisher struct {
+	events []*models.ChatEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *models.ChatEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) types() []string {
+	var types []string
+	for _, event := range p.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestChatRepository_UpdateChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	publisher := &recordingPublisher{}
+	repo := events.NewChatRepository(mockRepo, publisher)
+
+	stored := &models.Chat{
+		ID:       "chat-1",
+		User:     "owner-1",
+		Members:  []models.ChatMember{{UserID: "editor-1", Role: models.ChatRoleEditor}},
+		Messages: []models.Message{{ID: "msg-1", Role: "user", Text: "Hello"}},
+	}
+	updated := *stored
+	updated.Messages = append(append([]models.Message{}, stored.Messages...),
+		models.Message{ID: "msg-2", Role: "user", Text: "Again"},
+		models.Message{ID: "msg-3", Role: "assistant", Text: "Hi"},
+	)
+
+	mockRepo.EXPECT().UpdateChat(gomock.Any(), &updated).Return(stored, nil)
+
+	previous, err := repo.UpdateChat(context.Background(), &updated)
+	require.NoError(t, err)
+	assert.Equal(t, stored, previous)
+	assert.Equal(t, []string{models.EventMessageAdded, models.EventMessageAdded, models.EventChatUpdated}, publisher.types())
+	assert.Equal(t, "msg-2", publisher.events[0].Message.ID)
+	assert.Equal(t, []string{"owner-1", "editor-1"}, publisher.events[0].UserIDs)
+	assert.Nil(t, publisher.events[2].Chat.Messages)
+	assert.Len(t, updated.Messages, 3)
+}
+
+func TestChatRepository_SetMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	publisher := &recordingPublisher{}
+	repo := events.NewChatRepository(mockRepo, publisher)
+
+	before := &models.Chat{ID: "chat-1", User: "owner-1", Version: 3, Members: []models.ChatMember{{UserID: "old-1"}}}
+	members := []models.ChatMember{{UserID: "new-1"}}
+	mockRepo.EXPECT().SetMembers(gomock.Any(), "chat-1", int64(3), members).Return(before, nil)
+
+	_, err := repo.SetMembers(context.Background(), "chat-1", 3, members)
+	require.NoError(t, err)
+
+	audiences := map[string][]string{}
+	for _, event := range publisher.events {
+		audiences[event.Type] = event.UserIDs
+		if event.Chat != nil {
+			assert.Equal(t, members, event.Chat.Members)
+			assert.Equal(t, int64(4), event.Chat.Version)
+		}
+	}
+	assert.Equal(t, []string{"owner-1"}, audiences[models.EventChatUpdated])
+	assert.Equal(t, []string{"new-1"}, audiences[models.EventChatCreated])
+	assert.Equal(t, []string{"old-1"}, audiences[models.EventChatDeleted])
+}
+
+func TestChatRepository_FailedWritesPublishNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	publisher := &recordingPublisher{}
+	repo := events.NewChatRepository(mockRepo, publisher)
+
+	mockRepo.EXPECT().DeleteChat(gomock.Any(), "chat-1", int64(0)).Return(nil, assert.AnError)
+
+	_, err := repo.DeleteChat(context.Background(), "chat-1", 0)
+	assert.Error(t, err)
+	assert.Empty(t, publisher.events)
+}
+
+func TestChatRepository_UpdateChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	publisher := &recordingPublisher{}
+	repo := events.NewChatRepository(mockRepo, publisher)
+
+	archived := true
+	update := models.ChatUpdate{Archived: &archived}
+	mockRepo.EXPECT().UpdateChats(gomock.Any(), "owner-1", []string{"chat-1", "chat-2"}, update).
+		Return([]*models.Chat{{ID: "chat-1", User: "owner-1", Archived: true}}, nil)
+
+	chats, err := repo.UpdateChats(context.Background(), "owner-1", []string{"chat-1", "chat-2"}, update)
+	require.NoError(t, err)
+	assert.Len(t, chats, 1)
+	require.Equal(t, []string{models.EventChatUpdated}, publisher.types(), "only the updated chats are reported")
+	assert.True(t, publisher.events[0].Chat.Archived)
+}
+
+func TestChatRepository_MemoryBookkeepingPublishesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	publisher := &recordingPublisher{}
+	repo := events.NewChatRepository(mockRepo, publisher)
+
+	now := time.Now()
+	mockRepo.EXPECT().SetMemoryExtractedAt(gomock.Any(), "chat-1", now).Return(nil)
+	mockRepo.EXPECT().RecordMemoryFailure(gomock.Any(), "chat-1", now).Return(nil)
+
+	require.NoError(t, repo.SetMemoryExtractedAt(context.Background(), "chat-1", now))
+	require.NoError(t, repo.RecordMemoryFailure(context.Background(), "chat-1", now))
+	assert.Empty(t, publisher.events)
+}
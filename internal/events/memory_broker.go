@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const subscriberBuffer = 64
+
+type subscriber struct {
+	userID string
+	ch     chan *models.ChatEvent
+}
+
+// MemoryBroker delivers events to subscribers of the same process. It keeps
+// the latest events so that a client reconnecting with the token of the last
+// event it saw receives what it missed.
+type MemoryBroker struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	history     []*models.ChatEvent
+	historySize int
+	subscribers map[*subscriber]struct{}
+}
+
+func NewMemoryBroker(historySize int) *MemoryBroker {
+	return &MemoryBroker{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, event *models.ChatEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.ID = fmt.Sprintf("%s-%d", b.epoch, b.seq)
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !slices.Contains(event.UserIDs, sub.userID) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// The client is not keeping up. Closing the stream makes it
+			// reconnect and resume from the last event it received.
+			b.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe streams the user's events until ctx is done. With a resume token
+// the events published after it are replayed first, or a reset event is sent
+// when they are no longer available.
+func (b *MemoryBroker) Subscribe(ctx context.Context, userID string, resumeToken string) (<-chan *models.ChatEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []*models.ChatEvent
+	if resumeToken != "" {
+		missed, ok := b.since(resumeToken)
+		if !ok {
+			backlog = append(backlog, &models.ChatEvent{Type: models.EventReset, ID: b.lastID(), At: time.Now()})
+		}
+		for _, event := range missed {
+			if slices.Contains(event.UserIDs, userID) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	sub := &subscriber{userID: userID, ch: make(chan *models.ChatEvent, subscriberBuffer+len(backlog))}
+	for _, event := range backlog {
+		sub.ch <- event
+	}
+	b.subscribers[sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	}()
+
+	return sub.ch, nil
+}
+
+// since returns the events published after the token. It reports false when
+// the token is from another process or older than the kept history.
+func (b *MemoryBroker) since(token string) ([]*models.ChatEvent, bool) {
+	epoch, seqStr, found := strings.Cut(token, "-")
+	if !found || epoch != b.epoch {
+		return nil, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil || seq > b.seq {
+		return nil, false
+	}
+
+	missed := b.seq - seq
+	if missed > uint64(len(b.history)) {
+		return nil, false
+	}
+	return b.history[uint64(len(b.history))-missed:], true
+}
+
+func (b *MemoryBroker) lastID() string {
+	return fmt.Sprintf("%s-%d", b.epoch, b.seq)
+}
+
+func (b *MemoryBroker) remove(sub *subscriber) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
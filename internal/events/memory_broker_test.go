@@ -0,0 +1,108 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/events"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, ch <-chan *models.ChatEvent) *models.ChatEvent {
+	t.Helper()
+	select {
+	case event := <-ch:
+		require.NotNil(t, event)
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func assertNoEvent(t *testing.T, ch <-chan *models.ChatEvent) {
+	t.Helper()
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected %s event", event.Type)
+	default:
+	}
+}
+
+func TestMemoryBroker_Subscribe(t *testing.T) {
+	broker := events.NewMemoryBroker(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alice, err := broker.Subscribe(ctx, "alice", "")
+	require.NoError(t, err)
+	bob, err := broker.Subscribe(ctx, "bob", "")
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish(ctx, &models.ChatEvent{Type: models.EventChatCreated, ChatID: "chat-1", UserIDs: []string{"alice"}}))
+	require.NoError(t, broker.Publish(ctx, &models.ChatEvent{Type: models.EventChatUpdated, ChatID: "chat-2", UserIDs: []string{"alice", "bob"}}))
+
+	assert.Equal(t, "chat-1", receive(t, alice).ChatID)
+	assert.Equal(t, "chat-2", receive(t, alice).ChatID)
+	assert.Equal(t, "chat-2", receive(t, bob).ChatID)
+	assertNoEvent(t, bob)
+
+	cancel()
+	select {
+	case _, ok := <-alice:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed")
+	}
+}
+
+func TestMemoryBroker_Resume(t *testing.T) {
+	broker := events.NewMemoryBroker(2)
+	ctx := context.Background()
+
+	first := &models.ChatEvent{Type: models.EventChatCreated, ChatID: "chat-1", UserIDs: []string{"alice"}}
+	require.NoError(t, broker.Publish(ctx, first))
+	second := &models.ChatEvent{Type: models.EventChatUpdated, ChatID: "chat-1", UserIDs: []string{"alice"}}
+	require.NoError(t, broker.Publish(ctx, second))
+	require.NoError(t, broker.Publish(ctx, &models.ChatEvent{Type: models.EventChatUpdated, ChatID: "chat-9", UserIDs: []string{"bob"}}))
+	require.NoError(t, broker.Publish(ctx, &models.ChatEvent{Type: models.EventMessageAdded, ChatID: "chat-1", UserIDs: []string{"alice"}}))
+
+	tests := []struct {
+		name      string
+		token     string
+		wantTypes []string
+	}{
+		{
+			name:      "replays the user's events after the token",
+			token:     second.ID,
+			wantTypes: []string{models.EventMessageAdded},
+		},
+		{
+			name:      "token older than the history",
+			token:     first.ID,
+			wantTypes: []string{models.EventReset},
+		},
+		{
+			name:      "token from another process",
+			token:     "other-1",
+			wantTypes: []string{models.EventReset},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch, err := broker.Subscribe(ctx, "alice", tt.token)
+			require.NoError(t, err)
+			for _, eventType := range tt.wantTypes {
+				assert.Equal(t, eventType, receive(t, ch).Type)
+			}
+			assertNoEvent(t, ch)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoBroker shares events between replicas through the "events"
+// collection: every event is inserted there and each subscriber follows the
+// collection with a change stream, so it needs MongoDB running as a replica
+// set. Event IDs are change stream resume tokens.
+type MongoBroker struct {
+	db *mongo.Database
+}
+
+func NewMongoBroker(db *mongo.Database) *MongoBroker {
+	return &MongoBroker{db: db}
+}
+
+func (b *MongoBroker) Publish(ctx context.Context, event *models.ChatEvent) error {
+	_, err := b.db.Collection("events").InsertOne(ctx, event)
+	return err
+}
+
+func (b *MongoBroker) Subscribe(ctx context.Context, userID string, resumeToken string) (<-chan *models.ChatEvent, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType":         "insert",
+			"fullDocument.user_ids": userID,
+		}}},
+	}
+
+	reset := false
+	opts := options.ChangeStream()
+	if resumeToken != "" {
+		opts.SetResumeAfter(bson.M{"_data": resumeToken})
+	}
+	stream, err := b.db.Collection("events").Watch(ctx, pipeline, opts)
+	if err != nil && resumeToken != "" {
+		// The token is invalid or has fallen out of the oplog.
+		reset = true
+		stream, err = b.db.Collection("events").Watch(ctx, pipeline)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *models.ChatEvent, subscriberBuffer)
+	go func() {
+		defer close(ch)
+		defer stream.Close(context.Background())
+
+		if reset {
+			select {
+			case ch <- &models.ChatEvent{Type: models.EventReset, ID: resumeTokenData(stream.ResumeToken()), At: time.Now()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for stream.Next(ctx) {
+			var change struct {
+				FullDocument models.ChatEvent `bson:"fullDocument"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				log.Printf("Error decoding chat event: %v", err)
+				continue
+			}
+			event := change.FullDocument
+			event.ID = resumeTokenData(stream.ResumeToken())
+
+			select {
+			case ch <- &event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			log.Printf("Chat event stream for user %s stopped: %v", userID, err)
+		}
+	}()
+
+	return ch, nil
+}
+
+func resumeTokenData(token bson.Raw) string {
+	data, ok := token.Lookup("_data").StringValueOK()
+	if !ok {
+		return ""
+	}
+	return data
+}
@@ -1,22 +1,80 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/jsonschema"
 	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/service"
 	"github.com/lutefd/ai-router-go/internal/strategy"
 )
 
 type AIHandler struct {
-	aiStrategy strategy.AIStrategyInterface
+	aiStrategy        strategy.AIStrategyInterface
+	chatService       service.ChatServiceInterface
+	titleService      service.TitleServiceInterface
+	contextBuilder    service.ContextBuilderInterface
+	summaryService    service.SummaryServiceInterface
+	templateService   service.TemplateServiceInterface
+	assistantService  service.AssistantServiceInterface
+	memoryService     service.MemoryServiceInterface
+	attachmentService service.AttachmentServiceInterface
+	toolRunner        service.ToolRunnerInterface
+	structuredOutput  service.StructuredOutputServiceInterface
+	knowledgeService  service.KnowledgeServiceInterface
+	audioService      service.AudioServiceInterface
+	promptCache       service.PromptCacheManagerInterface
+	// transcriptionPlatform and transcriptionModel transcribe audio prompts.
+	transcriptionPlatform string
+	transcriptionModel    string
 }
 
-func NewAIHandler(aiStrategy strategy.AIStrategyInterface) *AIHandler {
-	return &AIHandler{aiStrategy: aiStrategy}
+func NewAIHandler(aiStrategy strategy.AIStrategyInterface,
+	chatService service.ChatServiceInterface,
+	titleService service.TitleServiceInterface,
+	contextBuilder service.ContextBuilderInterface,
+	summaryService service.SummaryServiceInterface,
+	templateService service.TemplateServiceInterface,
+	assistantService service.AssistantServiceInterface,
+	memoryService service.MemoryServiceInterface,
+	attachmentService service.AttachmentServiceInterface,
+	toolRunner service.ToolRunnerInterface,
+	structuredOutput service.StructuredOutputServiceInterface,
+	knowledgeService service.KnowledgeServiceInterface,
+	audioService service.AudioServiceInterface,
+	promptCache service.PromptCacheManagerInterface,
+	transcriptionPlatform string,
+	transcriptionModel string) *AIHandler {
+	return &AIHandler{
+		aiStrategy:            aiStrategy,
+		chatService:           chatService,
+		titleService:          titleService,
+		contextBuilder:        contextBuilder,
+		summaryService:        summaryService,
+		templateService:       templateService,
+		assistantService:      assistantService,
+		memoryService:         memoryService,
+		attachmentService:     attachmentService,
+		toolRunner:            toolRunner,
+		structuredOutput:      structuredOutput,
+		knowledgeService:      knowledgeService,
+		audioService:          audioService,
+		promptCache:           promptCache,
+		transcriptionPlatform: transcriptionPlatform,
+		transcriptionModel:    transcriptionModel,
+	}
 }
 
 func (h *AIHandler) ProxyRequest(w http.ResponseWriter, r *http.Request) {
@@ -26,24 +84,163 @@ func (h *AIHandler) ProxyRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	platform := r.Header.Get("Platform")
+	sampling, err := samplingFromHeaders(r.Header)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	input, ok := h.readRequest(w, r, claims)
+	if !ok {
+		return
+	}
+	attachments := input.attachments
+	serverTools, err := h.toolRunner.ServerTools(input.serverTools)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := service.ValidateTools(append(append([]models.ToolDefinition(nil), input.tools...), serverTools...)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.responseSchema != nil {
+		if len(input.tools) > 0 || len(serverTools) > 0 {
+			http.Error(w, "A response schema cannot be combined with tools", http.StatusBadRequest)
+			return
+		}
+		if err := service.ValidateResponseSchema(input.responseSchema); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	prompt := string(input.body)
+	if templateID := r.Header.Get("Template-ID"); templateID != "" {
+		var ok bool
+		prompt, ok = h.renderTemplate(w, r, claims, templateID, input.body)
+		if !ok {
+			return
+		}
+	}
+
+	var chat *models.Chat
+	if chatID := r.Header.Get("Chat-ID"); chatID != "" {
+		chat, err = h.chatService.GetChat(r.Context(), chatID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if !service.CanReadChat(chat, claims.UserID) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !service.CanPostToChat(chat, claims.UserID) {
+			http.Error(w, "Viewers cannot post to the chat", http.StatusForbidden)
+			return
+		}
+		if _, ok := checkIfMatch(w, r, chat); !ok {
+			return
+		}
+	}
+
+	// A turn either starts with the user's prompt or continues with the
+	// results of the tools the model called.
+	turn := []models.Message{{Text: prompt, Role: "user", UserID: claims.UserID, Attachments: attachments}}
+	if len(input.toolResults) > 0 {
+		if chat == nil {
+			http.Error(w, "Tool results require a Chat-ID header", http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(prompt) != "" || len(attachments) > 0 {
+			http.Error(w, "Send either a prompt or tool results", http.StatusBadRequest)
+			return
+		}
+		turn, err = service.ToolResultMessages(service.PendingToolCalls(chat), input.toolResults, claims.UserID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	settings := models.ChatSettings{
+		Platform: r.Header.Get("Platform"),
+		Model:    r.Header.Get("Model"),
+		Sampling: sampling,
+	}
+	var assistantID string
+	var cacheScope string
+	if chat != nil {
+		defaults := chat.Settings
+		assistant := h.chatAssistant(r, chat)
+		if assistant != nil {
+			defaults = service.ResolveSettings(assistant.Settings, chat.Settings)
+			assistantID = assistant.ID
+		}
+		settings = service.ResolveSettings(defaults, settings)
+		cacheScope = service.PromptCacheScope(chat, assistant)
+	}
+	platform, model := settings.Platform, settings.Model
+
 	if platform == "" {
 		http.Error(w, "Platform header is required", http.StatusBadRequest)
 		return
 	}
 
-	model := r.Header.Get("Model")
 	if model == "" {
 		http.Error(w, "Model header is required", http.StatusBadRequest)
 		return
 	}
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusBadRequest)
+	info := catalog.Lookup(platform, model)
+	if hasImages(attachments) && !info.Vision {
+		http.Error(w, fmt.Sprintf("Model %s does not support image inputs", model), http.StatusBadRequest)
 		return
 	}
-	defer r.Body.Close()
+
+	var history []models.Message
+	var report *service.ContextReport
+	var citations []models.Citation
+	if chat != nil {
+		var messages []models.Message
+		if settings.SystemPrompt != "" {
+			// The system prompt is the same on every turn, unlike what
+			// follows it.
+			messages = append(messages, models.Message{Text: settings.SystemPrompt, Role: "system", CacheControl: true})
+		}
+		// Replies in a shared chat are read by all its members, so memories
+		// are only used in the owner's private chats.
+		if service.IsPrivateChat(chat, claims.UserID) {
+			memory, err := h.memoryService.MemoryPrompt(r.Context(), claims.UserID, platform, model)
+			if err != nil {
+				log.Printf("Error loading memories for user %s: %v", claims.UserID, err)
+			} else if memory != "" {
+				messages = append(messages, models.Message{Text: memory, Role: "system"})
+			}
+		}
+		// Knowledge bases belong to the chat's owner, whoever posts.
+		if len(chat.KnowledgeBaseIDs) > 0 && strings.TrimSpace(prompt) != "" {
+			citations, err = h.knowledgeService.Retrieve(r.Context(), chat.User, chat.KnowledgeBaseIDs, prompt)
+			if err != nil {
+				log.Printf("Error retrieving knowledge for chat %s: %v", chat.ID, err)
+			} else if len(citations) > 0 {
+				messages = append(messages, models.Message{Text: service.KnowledgePrompt(citations), Role: "system"})
+			}
+		}
+		chatHistory, err := h.attachmentService.LoadAttachmentData(r.Context(), chat, service.ChatHistory(chat), info.Vision)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		messages = append(messages, chatHistory...)
+		messages = append(messages, turn...)
+		messages = service.InlineDocuments(info, service.PairToolCalls(messages))
+		history, report, err = h.contextBuilder.BuildContext(r.Context(), platform, model, messages)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -58,20 +255,471 @@ func (h *AIHandler) ProxyRequest(w http.ResponseWriter, r *http.Request) {
 	log.Printf("User %s (%s) requesting AI generation with platform: %s, model: %s",
 		claims.Name, claims.UserID, platform, model)
 
-	err = h.aiStrategy.GenerateResponse(r.Context(), platform, model,
-		string(body), func(chunk string) {
-			fmt.Fprintf(w, "data: %s\n\n", chunk)
+	if input.transcription != nil {
+		if data, err := json.Marshal(input.transcription); err == nil {
+			fmt.Fprintf(w, "event: transcription\ndata: %s\n\n", data)
 			flusher.Flush()
-		})
+		}
+	}
+
+	if report != nil && report.Trimmed {
+		if data, err := json.Marshal(report); err == nil {
+			fmt.Fprintf(w, "event: context\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+
+	var tracker *service.CitationTracker
+	if len(citations) > 0 {
+		tracker = service.NewCitationTracker(citations)
+	}
+	onChunk := func(chunk string) {
+		fmt.Fprintf(w, "data: %s\n\n", chunk)
+		if tracker != nil {
+			for _, citation := range tracker.Scan(chunk) {
+				if data, err := json.Marshal(citation); err == nil {
+					fmt.Fprintf(w, "event: citation\ndata: %s\n\n", data)
+				}
+			}
+		}
+		flusher.Flush()
+	}
+	onToolCall := func(call models.ToolCall) {
+		if data, err := json.Marshal(call); err == nil {
+			fmt.Fprintf(w, "event: tool_call\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+	onToolRun := func(run *models.ToolRun) {
+		if data, err := json.Marshal(run); err == nil {
+			fmt.Fprintf(w, "event: tool_result\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+	// Tool loops and structured output retries call the model several times.
+	var usage *models.Usage
+	onUsage := func(step models.Usage) {
+		if usage == nil {
+			usage = &models.Usage{}
+		}
+		usage.Add(step)
+	}
+	prepareCache := func(req *models.GenerationRequest) {
+		if chat == nil || len(serverTools) > 0 {
+			return
+		}
+		if err := h.promptCache.Prepare(r.Context(), platform, cacheScope, req); err != nil {
+			log.Printf("Error preparing prompt cache for chat %s: %v", chat.ID, err)
+		}
+	}
+
+	var replies []models.Message
+	if input.responseSchema != nil {
+		// Structured output is validated as a whole before it is sent.
+		if chat == nil {
+			history = service.InlineDocuments(info, turn)
+		}
+		req := &models.GenerationRequest{
+			Model:          model,
+			Messages:       history,
+			Sampling:       settings.Sampling,
+			ResponseSchema: input.responseSchema,
+			OnUsage:        onUsage,
+		}
+		prepareCache(req)
+		var output string
+		output, err = h.structuredOutput.Generate(r.Context(), platform, req)
+		if err == nil {
+			onChunk(output)
+			replies = []models.Message{{Text: output, Role: "assistant"}}
+		}
+	} else if chat != nil || len(attachments) > 0 || len(input.tools) > 0 || len(serverTools) > 0 {
+		scope := service.ToolScope{UserID: claims.UserID, ServerTools: serverTools}
+		if chat != nil {
+			scope.ChatID = chat.ID
+			scope.Shared = !service.IsPrivateChat(chat, claims.UserID)
+		} else {
+			history = service.InlineDocuments(info, turn)
+		}
+		req := &models.GenerationRequest{
+			Model:      model,
+			Messages:   history,
+			Sampling:   settings.Sampling,
+			Tools:      input.tools,
+			OnToolCall: onToolCall,
+			OnUsage:    onUsage,
+		}
+		prepareCache(req)
+		replies, err = h.toolRunner.Generate(r.Context(), platform, req, scope, onChunk, onToolRun)
+	} else {
+		err = h.aiStrategy.GenerateResponse(r.Context(), platform, model,
+			prompt, onChunk)
+	}
 
 	if err != nil {
 		log.Printf("Error generating response for user %s: %v", claims.UserID, err)
+		var validationErr *jsonschema.ValidationError
+		if errors.As(err, &validationErr) {
+			if data, err := json.Marshal(validationErr); err == nil {
+				fmt.Fprintf(w, "event: schema_error\ndata: %s\n\n", data)
+			}
+		}
 		fmt.Fprintf(w, "data: ERROR: %s\n\n", err.Error())
 		flusher.Flush()
 		return
 	}
 
+	for i := len(replies) - 1; i >= 0; i-- {
+		if replies[i].Role == "assistant" {
+			if tracker != nil {
+				replies[i].Citations = tracker.Cited()
+			}
+			replies[i].Usage = usage
+			break
+		}
+	}
+	if usage != nil {
+		if data, err := json.Marshal(usage); err == nil {
+			fmt.Fprintf(w, "event: usage\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+
+	if chat != nil {
+		h.saveExchange(w, r, flusher, chat, settings, assistantID, turn, replies)
+	}
+
 	fmt.Fprint(w, "data: [DONE]\n\n")
 	flusher.Flush()
 	log.Printf("Stream completed for user %s", claims.UserID)
 }
+
+func (h *AIHandler) saveExchange(w http.ResponseWriter, r *http.Request,
+	flusher http.Flusher, chat *models.Chat, settings models.ChatSettings, assistantID string,
+	turn []models.Message, replies []models.Message) {
+	generation := &models.GenerationInfo{
+		Platform:     settings.Platform,
+		Model:        settings.Model,
+		AssistantID:  assistantID,
+		SystemPrompt: settings.SystemPrompt,
+		Sampling:     settings.Sampling,
+	}
+	for _, message := range turn {
+		if err := h.attachmentService.SaveAttachments(r.Context(), chat.ID, message.Attachments); err != nil {
+			log.Printf("Error saving attachments to chat: %v", err)
+			fmt.Fprintf(w, "data: ERROR: %s\n\n", err.Error())
+			flusher.Flush()
+			return
+		}
+	}
+	for i := range replies {
+		if replies[i].Role == "assistant" {
+			replies[i].AI = settings.Model
+			replies[i].Generation = generation
+		}
+	}
+	chat, err := h.chatService.AddMessages(r.Context(), chat.ID, append(turn, replies...)...)
+	if err != nil {
+		log.Printf("Error saving messages to chat: %v", err)
+		fmt.Fprintf(w, "data: ERROR: %s\n\n", err.Error())
+		flusher.Flush()
+		return
+	}
+
+	// The summary is refreshed after the response has been delivered so the
+	// client never waits on it.
+	go func(chat *models.Chat) {
+		ctx := context.WithoutCancel(r.Context())
+		if err := h.summaryService.UpdateSummary(ctx, chat); err != nil {
+			log.Printf("Error updating summary for chat %s: %v", chat.ID, err)
+		}
+	}(chat)
+
+	if chat.Title != "" {
+		return
+	}
+
+	title, err := h.titleService.GenerateTitle(r.Context(), chat)
+	if err != nil {
+		log.Printf("Error generating title for chat %s: %v", chat.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "event: title\ndata: %s\n\n", title)
+	flusher.Flush()
+}
+
+// generateRequest is the JSON form of a generation request. File data is
+// base64 encoded.
+type generateRequest struct {
+	Prompt         string                  `json:"prompt"`
+	Images         []attachmentFile        `json:"images"`
+	Files          []attachmentFile        `json:"files"`
+	Tools          []models.ToolDefinition `json:"tools"`
+	ServerTools    []string                `json:"server_tools"`
+	ToolResults    []models.ToolResult     `json:"tool_results"`
+	ResponseSchema *models.ResponseSchema  `json:"response_schema"`
+}
+
+type generateInput struct {
+	body           []byte
+	attachments    []models.Attachment
+	tools          []models.ToolDefinition
+	serverTools    []string
+	toolResults    []models.ToolResult
+	responseSchema *models.ResponseSchema
+	transcription  *models.Transcription
+}
+
+type attachmentFile struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// readRequest reads the prompt and any attached files, tools, tool results
+// and response schema. Files arrive either as "image" or "file" parts of a
+// multipart form next to a "prompt" field and an optional "audio" recording
+// of the prompt, or base64 encoded in a JSON body,
+// which is also where the other options are sent. A JSON body always carries
+// the prompt in its "prompt" field. Any other body is the prompt itself.
+// With a Template-ID the body holds the template variables instead.
+func (h *AIHandler) readRequest(w http.ResponseWriter, r *http.Request,
+	claims *service.Claims) (*generateInput, bool) {
+	defer r.Body.Close()
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxAttachmentsPerMessage*service.MaxImageSize+1<<20)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		return h.readMultipartRequest(w, r, claims)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if mediaType != "application/json" || r.Header.Get("Template-ID") != "" {
+		return &generateInput{body: body}, true
+	}
+
+	var req generateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	files := append(req.Images, req.Files...)
+	if !checkAttachmentCount(w, len(files)) {
+		return nil, false
+	}
+
+	attachments := make([]models.Attachment, 0, len(files))
+	for _, file := range files {
+		attachment, err := h.attachmentService.NewAttachment(claims.UserID, file.Name, file.Data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return &generateInput{
+		body:           []byte(req.Prompt),
+		attachments:    attachments,
+		tools:          req.Tools,
+		serverTools:    req.ServerTools,
+		toolResults:    req.ToolResults,
+		responseSchema: req.ResponseSchema,
+	}, true
+}
+
+func (h *AIHandler) readMultipartRequest(w http.ResponseWriter, r *http.Request,
+	claims *service.Claims) (*generateInput, bool) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return nil, false
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := append(r.MultipartForm.File["image"], r.MultipartForm.File["file"]...)
+	if !checkAttachmentCount(w, len(files)) {
+		return nil, false
+	}
+
+	attachments := make([]models.Attachment, 0, len(files))
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "Error reading file", http.StatusBadRequest)
+			return nil, false
+		}
+		data, err := io.ReadAll(io.LimitReader(file, service.MaxImageSize+1))
+		file.Close()
+		if err != nil {
+			http.Error(w, "Error reading file", http.StatusBadRequest)
+			return nil, false
+		}
+
+		attachment, err := h.attachmentService.NewAttachment(claims.UserID, header.Filename, data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		attachments = append(attachments, *attachment)
+	}
+	prompt := r.FormValue("prompt")
+	var transcription *models.Transcription
+	if audio := r.MultipartForm.File["audio"]; len(audio) > 0 {
+		if len(audio) > 1 {
+			http.Error(w, "Send one audio recording per message", http.StatusBadRequest)
+			return nil, false
+		}
+		var ok bool
+		if transcription, ok = h.transcribe(w, r, audio[0]); !ok {
+			return nil, false
+		}
+		prompt = strings.TrimSpace(prompt + "\n\n" + transcription.Text)
+	}
+	return &generateInput{body: []byte(prompt), attachments: attachments, transcription: transcription}, true
+}
+
+// transcribe turns a recorded prompt into text with the configured
+// transcription model.
+func (h *AIHandler) transcribe(w http.ResponseWriter, r *http.Request,
+	header *multipart.FileHeader) (*models.Transcription, bool) {
+	if header.Size > service.MaxAudioSize {
+		http.Error(w, fmt.Sprintf("Audio is larger than %d MB", service.MaxAudioSize>>20), http.StatusBadRequest)
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		http.Error(w, "Error reading audio", http.StatusBadRequest)
+		return nil, false
+	}
+	defer file.Close()
+
+	req := &models.TranscriptionRequest{
+		Model:    h.transcriptionModel,
+		FileName: header.Filename,
+		Audio:    file,
+		Language: r.FormValue("language"),
+	}
+	if err := service.ValidateTranscriptionRequest(h.transcriptionPlatform, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	transcription, err := h.audioService.Transcribe(r.Context(), h.transcriptionPlatform, req)
+	if err != nil {
+		log.Printf("Error transcribing audio prompt: %v", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return nil, false
+	}
+	if transcription.Text == "" {
+		http.Error(w, "No speech was recognised in the audio", http.StatusBadRequest)
+		return nil, false
+	}
+	return transcription, true
+}
+
+func checkAttachmentCount(w http.ResponseWriter, count int) bool {
+	if count > service.MaxAttachmentsPerMessage {
+		http.Error(w, fmt.Sprintf("At most %d files can be sent with a message", service.MaxAttachmentsPerMessage),
+			http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func hasImages(attachments []models.Attachment) bool {
+	for _, attachment := range attachments {
+		if attachment.Kind == models.AttachmentKindImage {
+			return true
+		}
+	}
+	return false
+}
+
+// chatAssistant returns the assistant the chat uses, if any. An assistant that
+// was deleted or is no longer shared is skipped so the chat keeps working with
+// its own settings.
+func (h *AIHandler) chatAssistant(r *http.Request, chat *models.Chat) *models.Assistant {
+	if chat.AssistantID == "" {
+		return nil
+	}
+
+	assistant, err := h.assistantService.GetAssistant(r.Context(), chat.AssistantID)
+	if err != nil {
+		log.Printf("Error loading assistant %s for chat %s: %v", chat.AssistantID, chat.ID, err)
+		return nil
+	}
+	if !service.CanUseAssistant(assistant, chat.User) {
+		log.Printf("Assistant %s is no longer available to chat %s", assistant.ID, chat.ID)
+		return nil
+	}
+	return assistant
+}
+
+// renderTemplate renders the prompt template named by the Template-ID header.
+// The request body holds the variable values as a JSON object, and the
+// optional Template-Version header pins an earlier version.
+func (h *AIHandler) renderTemplate(w http.ResponseWriter, r *http.Request, claims *service.Claims,
+	templateID string, body []byte) (string, bool) {
+	tmpl, err := h.templateService.GetTemplate(r.Context(), templateID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return "", false
+	}
+	if tmpl.User != claims.UserID && !tmpl.Shared {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+
+	version := 0
+	if value := r.Header.Get("Template-Version"); value != "" {
+		version, err = strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "invalid Template-Version header", http.StatusBadRequest)
+			return "", false
+		}
+	}
+
+	variables := map[string]any{}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &variables); err != nil {
+			http.Error(w, "Template variables must be a JSON object", http.StatusBadRequest)
+			return "", false
+		}
+	}
+
+	prompt, err := h.templateService.RenderTemplate(r.Context(), tmpl, version, variables)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return prompt, true
+}
+
+// samplingFromHeaders reads the optional Temperature, Top-P and Max-Tokens
+// headers that override the chat's sampling defaults.
+func samplingFromHeaders(header http.Header) (models.SamplingParams, error) {
+	var sampling models.SamplingParams
+	if value := header.Get("Temperature"); value != "" {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return sampling, fmt.Errorf("invalid Temperature header")
+		}
+		sampling.Temperature = &temperature
+	}
+	if value := header.Get("Top-P"); value != "" {
+		topP, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return sampling, fmt.Errorf("invalid Top-P header")
+		}
+		sampling.TopP = &topP
+	}
+	if value := header.Get("Max-Tokens"); value != "" {
+		maxTokens, err := strconv.Atoi(value)
+		if err != nil {
+			return sampling, fmt.Errorf("invalid Max-Tokens header")
+		}
+		sampling.MaxTokens = &maxTokens
+	}
+	return sampling, service.ValidateSampling(sampling)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIHandler_ReadRequest(t *testing.T) {
+	handler := &AIHandler{}
+
+	tests := []struct {
+		name        string
+		contentType string
+		templateID  string
+		body        string
+		wantPrompt  string
+		wantStatus  int
+	}{
+		{
+			name:        "JSON body without options",
+			contentType: "application/json",
+			body:        `{"prompt": "Hello"}`,
+			wantPrompt:  "Hello",
+		},
+		{
+			name:        "JSON body with options",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"prompt": "What time is it?", "server_tools": ["current_datetime"]}`,
+			wantPrompt:  "What time is it?",
+		},
+		{
+			name:        "invalid JSON body",
+			contentType: "application/json",
+			body:        `{"prompt": "Hello"`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "plain text body",
+			contentType: "text/plain",
+			body:        `{"prompt": "Hello"}`,
+			wantPrompt:  `{"prompt": "Hello"}`,
+		},
+		{
+			name:        "template variables",
+			contentType: "application/json",
+			templateID:  "tmpl-1",
+			body:        `{"diff": "..."}`,
+			wantPrompt:  `{"diff": "..."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/generate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.templateID != "" {
+				req.Header.Set("Template-ID", tt.templateID)
+			}
+			w := httptest.NewRecorder()
+
+			input, ok := handler.readRequest(w, req, &service.Claims{UserID: "user-123"})
+			if tt.wantStatus != 0 {
+				assert.False(t, ok)
+				assert.Equal(t, tt.wantStatus, w.Code)
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, tt.wantPrompt, string(input.body))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type AssistantHandler struct {
+	assistantService service.AssistantServiceInterface
+}
+
+func NewAssistantHandler(assistantService service.AssistantServiceInterface) *AssistantHandler {
+	return &AssistantHandler{
+		assistantService: assistantService,
+	}
+}
+
+func (h *AssistantHandler) ListAssistants(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	assistants, err := h.assistantService.ListAssistants(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assistants)
+}
+
+func (h *AssistantHandler) CreateAssistant(w http.ResponseWriter, r *http.Request) {
+	var assistant models.Assistant
+	if err := json.NewDecoder(r.Body).Decode(&assistant); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	assistant.User = claims.UserID
+
+	if err := h.assistantService.CreateAssistant(r.Context(), &assistant); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(assistant)
+}
+
+func (h *AssistantHandler) GetAssistant(w http.ResponseWriter, r *http.Request) {
+	assistantID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	assistant, err := h.assistantService.GetAssistant(r.Context(), assistantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanUseAssistant(assistant, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	json.NewEncoder(w).Encode(assistant)
+}
+
+func (h *AssistantHandler) UpdateAssistant(w http.ResponseWriter, r *http.Request) {
+	assistantID := chi.URLParam(r, "id")
+	var update models.AssistantUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	assistant, err := h.assistantService.GetAssistant(r.Context(), assistantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if assistant.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	assistant, err = h.assistantService.UpdateAssistant(r.Context(), assistantID, update)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(assistant)
+}
+
+func (h *AssistantHandler) DeleteAssistant(w http.ResponseWriter, r *http.Request) {
+	assistantID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	assistant, err := h.assistantService.GetAssistant(r.Context(), assistantID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if assistant.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.assistantService.DeleteAssistant(r.Context(), assistantID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type AttachmentHandler struct {
+	chatService       service.ChatServiceInterface
+	attachmentService service.AttachmentServiceInterface
+}
+
+func NewAttachmentHandler(chatService service.ChatServiceInterface,
+	attachmentService service.AttachmentServiceInterface) *AttachmentHandler {
+	return &AttachmentHandler{
+		chatService:       chatService,
+		attachmentService: attachmentService,
+	}
+}
+
+func (h *AttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// DownloadAttachment serves the original file as it was uploaded.
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(r.Context(), attachmentID)
+	if err != nil || attachment.ChatID != chatID {
+		http.Error(w, "attachment not found", http.StatusNotFound)
+		return
+	}
+
+	data, err := h.attachmentService.ReadAttachment(r.Context(), attachmentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", attachment.MimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.Name,
+	}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(data)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type AudioHandler struct {
+	audioService service.AudioServiceInterface
+}
+
+func NewAudioHandler(audioService service.AudioServiceInterface) *AudioHandler {
+	return &AudioHandler{
+		audioService: audioService,
+	}
+}
+
+// Transcribe returns the text of the recording in the "file" part of a
+// multipart form. The platform and model come from form fields or from the
+// Platform and Model headers used for generation.
+func (h *AudioHandler) Transcribe(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if claims == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxAudioSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Audio file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	if header.Size > service.MaxAudioSize {
+		http.Error(w, fmt.Sprintf("Audio is larger than %d MB", service.MaxAudioSize>>20), http.StatusBadRequest)
+		return
+	}
+
+	platform := formOrHeader(r, "platform", "Platform")
+	if platform == "" {
+		http.Error(w, "Platform is required", http.StatusBadRequest)
+		return
+	}
+	req := &models.TranscriptionRequest{
+		Model:    formOrHeader(r, "model", "Model"),
+		FileName: header.Filename,
+		Audio:    file,
+		Language: r.FormValue("language"),
+		Prompt:   r.FormValue("prompt"),
+	}
+	if err := service.ValidateTranscriptionRequest(platform, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transcription, err := h.audioService.Transcribe(r.Context(), platform, req)
+	if err != nil {
+		log.Printf("Error transcribing audio for user %s: %v", claims.UserID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transcription)
+}
+
+type speechRequest struct {
+	Platform string  `json:"platform"`
+	Model    string  `json:"model"`
+	Input    string  `json:"input"`
+	Voice    string  `json:"voice"`
+	Format   string  `json:"format"`
+	Speed    float64 `json:"speed"`
+}
+
+// Speak streams the synthesized audio to the client as the provider produces
+// it.
+func (h *AudioHandler) Speak(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if claims == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body speechRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if body.Platform == "" {
+		body.Platform = r.Header.Get("Platform")
+	}
+	if body.Model == "" {
+		body.Model = r.Header.Get("Model")
+	}
+	if body.Platform == "" {
+		http.Error(w, "Platform is required", http.StatusBadRequest)
+		return
+	}
+
+	req := &models.SpeechRequest{
+		Model:  body.Model,
+		Input:  body.Input,
+		Voice:  body.Voice,
+		Format: body.Format,
+		Speed:  body.Speed,
+	}
+	if err := service.ValidateSpeechRequest(body.Platform, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audio, err := h.audioService.Speak(r.Context(), body.Platform, req)
+	if err != nil {
+		log.Printf("Error synthesizing speech for user %s: %v", claims.UserID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer audio.Close()
+
+	w.Header().Set("Content-Type", service.SpeechContentType(req.Format))
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := audio.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// The status has been sent, so the client sees a truncated body.
+			log.Printf("Error streaming speech for user %s: %v", claims.UserID, err)
+			return
+		}
+	}
+}
+
+func formOrHeader(r *http.Request, field string, header string) string {
+	if value := r.FormValue(field); value != "" {
+		return value
+	}
+	return r.Header.Get(header)
+}
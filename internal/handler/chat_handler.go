@@ -2,7 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/lutefd/ai-router-go/internal/middleware"
@@ -11,30 +15,67 @@ import (
 )
 
 type ChatHandler struct {
-	chatService service.ChatServiceInterface
+	chatService      service.ChatServiceInterface
+	folderService    service.FolderServiceInterface
+	assistantService service.AssistantServiceInterface
+	knowledgeService service.KnowledgeServiceInterface
 }
 
-func NewChatHandler(chatService service.ChatServiceInterface) *ChatHandler {
+func NewChatHandler(chatService service.ChatServiceInterface,
+	folderService service.FolderServiceInterface,
+	assistantService service.AssistantServiceInterface,
+	knowledgeService service.KnowledgeServiceInterface) *ChatHandler {
 	return &ChatHandler{
-		chatService: chatService,
+		chatService:      chatService,
+		folderService:    folderService,
+		assistantService: assistantService,
+		knowledgeService: knowledgeService,
 	}
 }
 
 func (h *ChatHandler) CreateChat(w http.ResponseWriter, r *http.Request) {
-	var chat models.Chat
-	if err := json.NewDecoder(r.Body).Decode(&chat); err != nil {
+	var body struct {
+		Title       string              `json:"title"`
+		Settings    models.ChatSettings `json:"settings"`
+		AssistantID string              `json:"assistant_id"`
+		FolderID    string              `json:"folder_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
-	chat.User = claims.UserID
+	chat := models.Chat{
+		User:        claims.UserID,
+		Title:       body.Title,
+		Settings:    body.Settings,
+		AssistantID: body.AssistantID,
+		FolderID:    body.FolderID,
+	}
+
+	if chat.FolderID != "" {
+		folder, err := h.folderService.GetFolder(r.Context(), chat.FolderID)
+		if err != nil || folder.User != claims.UserID {
+			http.Error(w, "folder not found", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if chat.AssistantID != "" {
+		assistant, err := h.assistantService.GetAssistant(r.Context(), chat.AssistantID)
+		if err != nil || !service.CanUseAssistant(assistant, claims.UserID) {
+			http.Error(w, "assistant not found", http.StatusBadRequest)
+			return
+		}
+	}
 
 	if err := h.chatService.CreateChat(r.Context(), &chat); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	setChatETag(w, &chat)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(chat)
 }
@@ -49,11 +90,12 @@ func (h *ChatHandler) GetChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if chat.User != claims.UserID {
+	if !service.CanReadChat(chat, claims.UserID) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
+	setChatETag(w, chat)
 	json.NewEncoder(w).Encode(chat)
 }
 
@@ -81,15 +123,91 @@ func (h *ChatHandler) UpdateChatTitle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chat.Title = update.Title
-	if err := h.chatService.UpdateChat(r.Context(), chat); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	version, ok := checkIfMatch(w, r, chat)
+	if !ok {
+		return
+	}
+
+	renamed := &models.Chat{ID: chat.ID, Title: update.Title, Version: version}
+	if err := h.chatService.UpdateChat(r.Context(), renamed); err != nil {
+		writeChatError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	setChatETag(w, renamed)
+	json.NewEncoder(w).Encode(renamed)
+}
+
+func (h *ChatHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var settings models.ChatSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if chat.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	version, ok := checkIfMatch(w, r, chat)
+	if !ok {
+		return
+	}
+
+	chat, err = h.chatService.UpdateSettings(r.Context(), chatID, version, settings)
+	if err != nil {
+		writeChatError(w, err, http.StatusBadRequest)
 		return
 	}
 
+	setChatETag(w, chat)
 	json.NewEncoder(w).Encode(chat)
 }
 
+func (h *ChatHandler) ForkChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var body struct {
+		MessageID string `json:"message_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	fork, err := h.chatService.ForkChat(r.Context(), claims.UserID, chatID, body.MessageID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	setChatETag(w, fork)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(fork)
+}
+
 func (h *ChatHandler) DeleteChat(w http.ResponseWriter, r *http.Request) {
 	chatID := chi.URLParam(r, "id")
 	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
@@ -105,10 +223,361 @@ func (h *ChatHandler) DeleteChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.chatService.DeleteChat(r.Context(), chatID); err != nil {
+	version, ok := checkIfMatch(w, r, chat)
+	if !ok {
+		return
+	}
+
+	if err := h.chatService.DeleteChat(r.Context(), chatID, version); err != nil {
+		writeChatError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ChatHandler) SetPinned(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Pinned bool `json:"pinned"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.applyUpdate(w, r, models.ChatUpdate{Pinned: &body.Pinned})
+}
+
+func (h *ChatHandler) SetArchived(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Archived bool `json:"archived"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.applyUpdate(w, r, models.ChatUpdate{Archived: &body.Archived})
+}
+
+func (h *ChatHandler) SetTags(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if body.Tags == nil {
+		body.Tags = []string{}
+	}
+
+	h.applyUpdate(w, r, models.ChatUpdate{Tags: body.Tags})
+}
+
+func (h *ChatHandler) MoveToFolder(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var body struct {
+		FolderID string `json:"folder_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		http.Error(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	affected, err := h.folderService.MoveChats(r.Context(), claims.UserID, body.FolderID, []string{chatID}, version)
+	h.writeUpdated(w, r, chatID, version, affected, err)
+}
+
+func (h *ChatHandler) SetAssistant(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var body struct {
+		AssistantID string `json:"assistant_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		http.Error(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	affected, err := h.assistantService.SetChatAssistant(r.Context(), claims.UserID, body.AssistantID, []string{chatID}, version)
+	h.writeUpdated(w, r, chatID, version, affected, err)
+}
+
+func (h *ChatHandler) SetKnowledgeBases(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var body struct {
+		KnowledgeBaseIDs []string `json:"knowledge_base_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		http.Error(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	affected, err := h.knowledgeService.SetChatKnowledgeBases(r.Context(), claims.UserID, chatID, body.KnowledgeBaseIDs, version)
+	h.writeUpdated(w, r, chatID, version, affected, err)
+}
+
+func (h *ChatHandler) BulkUpdate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ChatIDs  []string `json:"chat_ids"`
+		Action   string   `json:"action"`
+		Tags     []string `json:"tags"`
+		FolderID string   `json:"folder_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	ctx := r.Context()
+
+	var affected int64
+	var err error
+	archived, unarchived := true, false
+	switch body.Action {
+	case "add_tags":
+		affected, err = h.chatService.UpdateChats(ctx, claims.UserID, body.ChatIDs, models.ChatUpdate{AddTags: body.Tags})
+	case "remove_tags":
+		affected, err = h.chatService.UpdateChats(ctx, claims.UserID, body.ChatIDs, models.ChatUpdate{RemoveTags: body.Tags})
+	case "archive":
+		affected, err = h.chatService.UpdateChats(ctx, claims.UserID, body.ChatIDs, models.ChatUpdate{Archived: &archived})
+	case "unarchive":
+		affected, err = h.chatService.UpdateChats(ctx, claims.UserID, body.ChatIDs, models.ChatUpdate{Archived: &unarchived})
+	case "move":
+		affected, err = h.folderService.MoveChats(ctx, claims.UserID, body.FolderID, body.ChatIDs, 0)
+	case "delete":
+		affected, err = h.chatService.DeleteChats(ctx, claims.UserID, body.ChatIDs)
+	default:
+		http.Error(w, "Unsupported action", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"affected": affected})
+}
+
+func (h *ChatHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chats, err := h.chatService.ListTrash(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chats)
+}
+
+func (h *ChatHandler) RestoreChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if err := h.chatService.RestoreChat(r.Context(), claims.UserID, chatID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	h.writeChat(w, r, chatID, claims.UserID)
+}
+
+func (h *ChatHandler) PurgeChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if err := h.chatService.PurgeChat(r.Context(), claims.UserID, chatID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ChatHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if chat.Summary == nil {
+		http.Error(w, "chat has no summary", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(chat.Summary)
+}
+
+func (h *ChatHandler) ResetSummary(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !service.CanPostToChat(chat, claims.UserID) {
+		http.Error(w, "Viewers cannot change the chat", http.StatusForbidden)
+		return
+	}
+
+	if _, ok := checkIfMatch(w, r, chat); !ok {
+		return
+	}
+
+	if err := h.chatService.ResetSummary(r.Context(), chatID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *ChatHandler) applyUpdate(w http.ResponseWriter, r *http.Request, update models.ChatUpdate) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		http.Error(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+	update.Version = version
+
+	affected, err := h.chatService.UpdateChats(r.Context(), claims.UserID, []string{chatID}, update)
+	h.writeUpdated(w, r, chatID, version, affected, err)
+}
+
+// writeUpdated answers a conditional update of a single chat with the chat,
+// or with why it was left untouched.
+func (h *ChatHandler) writeUpdated(w http.ResponseWriter, r *http.Request, chatID string, version int64,
+	affected int64, err error) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if affected == 0 {
+		// A version mismatch also leaves the chat untouched; tell the two apart.
+		if version != 0 {
+			if chat, err := h.chatService.GetChat(r.Context(), chatID); err == nil && chat.User == claims.UserID {
+				http.Error(w, chatModifiedMessage, http.StatusPreconditionFailed)
+				return
+			}
+		}
+		http.Error(w, "chat not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeChat(w, r, chatID, claims.UserID)
+}
+
+func (h *ChatHandler) writeChat(w http.ResponseWriter, r *http.Request, chatID string, userID string) {
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if chat.User != userID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	setChatETag(w, chat)
+	json.NewEncoder(w).Encode(chat)
+}
+
+const chatModifiedMessage = "Chat has been modified, reload it and try again"
+
+func chatETag(chat *models.Chat) string {
+	return fmt.Sprintf(`"%d"`, chat.Version)
+}
+
+func setChatETag(w http.ResponseWriter, chat *models.Chat) {
+	w.Header().Set("ETag", chatETag(chat))
+}
+
+// ifMatchVersion returns the chat version required by the If-Match header,
+// or 0 when the header is missing or "*". It reports false for values that
+// are not one of our ETags.
+func ifMatchVersion(r *http.Request) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// checkIfMatch answers 412 when the If-Match header names another version
+// than the chat's, and returns the version later writes must be conditional
+// on.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, chat *models.Chat) (int64, bool) {
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		http.Error(w, "Invalid If-Match header", http.StatusBadRequest)
+		return 0, false
+	}
+	if version != 0 && version != chat.Version {
+		http.Error(w, chatModifiedMessage, http.StatusPreconditionFailed)
+		return 0, false
+	}
+	return version, true
+}
+
+func writeChatError(w http.ResponseWriter, err error, status int) {
+	if errors.Is(err, service.ErrVersionConflict) {
+		http.Error(w, chatModifiedMessage, http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
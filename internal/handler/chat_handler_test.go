@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChatHandler_CreateChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatService := mocks.NewMockChatServiceInterface(ctrl)
+	mockFolderService := mocks.NewMockFolderServiceInterface(ctrl)
+	handler := NewChatHandler(mockChatService, mockFolderService, nil, nil)
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "fields the client cannot set are dropped",
+			body: `{"title": "Plans", "folder_id": "folder-1",
+				"members": [{"user_id": "victim-1", "role": "owner"}],
+				"knowledge_base_ids": ["kb-of-someone-else"],
+				"summary": {"text": "forged"}, "deleted_at": "2024-01-01T00:00:00Z"}`,
+			setup: func() {
+				mockFolderService.EXPECT().GetFolder(gomock.Any(), "folder-1").
+					Return(&models.Folder{ID: "folder-1", User: "user-123"}, nil)
+				mockChatService.EXPECT().CreateChat(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, chat *models.Chat) error {
+						assert.Equal(t, "user-123", chat.User)
+						assert.Equal(t, "Plans", chat.Title)
+						assert.Equal(t, "folder-1", chat.FolderID)
+						assert.Empty(t, chat.Members)
+						assert.Empty(t, chat.KnowledgeBaseIDs)
+						assert.Nil(t, chat.Summary)
+						assert.Nil(t, chat.DeletedAt)
+						return nil
+					})
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "folder of another user",
+			body: `{"title": "Plans", "folder_id": "folder-9"}`,
+			setup: func() {
+				mockFolderService.EXPECT().GetFolder(gomock.Any(), "folder-9").
+					Return(&models.Folder{ID: "folder-9", User: "user-456"}, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest("POST", "/api/v1/chats", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey,
+				&service.Claims{UserID: "user-123"}))
+			rr := httptest.NewRecorder()
+			handler.CreateChat(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type ChatMemberHandler struct {
+	chatService   service.ChatServiceInterface
+	memberService service.ChatMemberServiceInterface
+}
+
+func NewChatMemberHandler(chatService service.ChatServiceInterface,
+	memberService service.ChatMemberServiceInterface) *ChatMemberHandler {
+	return &ChatMemberHandler{
+		chatService:   chatService,
+		memberService: memberService,
+	}
+}
+
+func (h *ChatMemberHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	members, err := h.memberService.ListMembers(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+func (h *ChatMemberHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var body struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if !h.requireOwner(w, r, chatID, claims.UserID) {
+		return
+	}
+
+	member, err := h.memberService.AddMember(r.Context(), chatID, body.Email, body.Role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+func (h *ChatMemberHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+	var body struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if !h.requireOwner(w, r, chatID, claims.UserID) {
+		return
+	}
+
+	member, err := h.memberService.UpdateMemberRole(r.Context(), chatID, userID, body.Role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(member)
+}
+
+// RemoveMember lets the owner remove anyone and members leave on their own.
+func (h *ChatMemberHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if userID != claims.UserID && !h.requireOwner(w, r, chatID, claims.UserID) {
+		return
+	}
+
+	if err := h.memberService.RemoveMember(r.Context(), chatID, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ChatMemberHandler) requireOwner(w http.ResponseWriter, r *http.Request, chatID string, userID string) bool {
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return false
+	}
+
+	switch service.ChatRole(chat, userID) {
+	case models.ChatRoleOwner:
+		_, ok := checkIfMatch(w, r, chat)
+		return ok
+	case "":
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	default:
+		http.Error(w, "Only the chat owner can manage members", http.StatusForbidden)
+	}
+	return false
+}
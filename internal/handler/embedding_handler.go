@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type EmbeddingHandler struct {
+	embeddingService service.EmbeddingServiceInterface
+}
+
+func NewEmbeddingHandler(embeddingService service.EmbeddingServiceInterface) *EmbeddingHandler {
+	return &EmbeddingHandler{
+		embeddingService: embeddingService,
+	}
+}
+
+type embeddingRequest struct {
+	Platform   string         `json:"platform"`
+	Model      string         `json:"model"`
+	Input      embeddingInput `json:"input"`
+	Dimensions int            `json:"dimensions"`
+}
+
+// embeddingInput accepts a single string as well as a list of them.
+type embeddingInput []string
+
+func (e *embeddingInput) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*e = []string{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*e = list
+	return nil
+}
+
+// CreateEmbeddings embeds the inputs with the platform and model from the
+// body, or from the Platform and Model headers used for generation.
+func (h *EmbeddingHandler) CreateEmbeddings(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if claims == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body embeddingRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<20)
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if body.Platform == "" {
+		body.Platform = r.Header.Get("Platform")
+	}
+	if body.Model == "" {
+		body.Model = r.Header.Get("Model")
+	}
+	if body.Platform == "" {
+		http.Error(w, "Platform is required", http.StatusBadRequest)
+		return
+	}
+
+	req := &models.EmbeddingRequest{Model: body.Model, Input: body.Input, Dimensions: body.Dimensions}
+	if err := service.ValidateEmbeddingRequest(body.Platform, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.embeddingService.CreateEmbeddings(r.Context(), body.Platform, req)
+	if err != nil {
+		log.Printf("Error creating embeddings for user %s: %v", claims.UserID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+const eventKeepAlive = 30 * time.Second
+
+type EventHandler struct {
+	broker service.EventBrokerInterface
+}
+
+func NewEventHandler(broker service.EventBrokerInterface) *EventHandler {
+	return &EventHandler{
+		broker: broker,
+	}
+}
+
+// StreamEvents pushes changes to the caller's chats as server-sent events.
+// Clients resume after a reconnect with the Last-Event-ID header, which
+// EventSource sends on its own, or the "after" query parameter.
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
+		return
+	}
+
+	resumeToken := r.Header.Get("Last-Event-ID")
+	if resumeToken == "" {
+		resumeToken = r.URL.Query().Get("after")
+	}
+
+	events, err := h.broker.Subscribe(r.Context(), claims.UserID, resumeToken)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	// The feed outlives the server's write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Could not lift the write deadline of the event stream: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(eventKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Error encoding %s event: %v", event.Type, err)
+				continue
+			}
+			if event.ID != "" {
+				fmt.Fprintf(w, "id: %s\n", event.ID)
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+const maxImportSize = 50 << 20
+
+type ExportHandler struct {
+	chatService   service.ChatServiceInterface
+	exportService service.ExportServiceInterface
+	importService service.ImportServiceInterface
+}
+
+func NewExportHandler(chatService service.ChatServiceInterface,
+	exportService service.ExportServiceInterface,
+	importService service.ImportServiceInterface) *ExportHandler {
+	return &ExportHandler{
+		chatService:   chatService,
+		exportService: exportService,
+		importService: importService,
+	}
+}
+
+func (h *ExportHandler) ExportChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !service.CanReadChat(chat, claims.UserID) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	setExportHeaders(w, format, chat.ID)
+	if err := h.exportService.ExportChat(r.Context(), w, chat, format); err != nil {
+		log.Printf("Error exporting chat %s: %v", chat.ID, err)
+	}
+}
+
+func (h *ExportHandler) ExportUserChats(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	setExportHeaders(w, format, "chats")
+	if err := h.exportService.ExportUserChats(r.Context(), w, claims.UserID, format); err != nil {
+		log.Printf("Error exporting chats for user %s: %v", claims.UserID, err)
+	}
+}
+
+func (h *ExportHandler) ImportChats(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	body, err := importBody(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chats, err := h.importService.ImportChats(r.Context(), claims.UserID, format, body)
+	if err != nil {
+		log.Printf("Import for user %s failed: %v", claims.UserID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	type importedChat struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	imported := make([]importedChat, 0, len(chats))
+	for _, chat := range chats {
+		imported = append(imported, importedChat{ID: chat.ID, Title: chat.Title})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"imported": len(imported),
+		"chats":    imported,
+	})
+}
+
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if _, ok := service.ExportFormats[format]; !ok {
+		http.Error(w, fmt.Sprintf("Unsupported export format: %s", format), http.StatusBadRequest)
+		return "", false
+	}
+	return format, true
+}
+
+func setExportHeaders(w http.ResponseWriter, format string, name string) {
+	exportFormat := service.ExportFormats[format]
+	w.Header().Set("Content-Type", exportFormat.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("%s.%s", name, exportFormat.Extension),
+	}))
+}
+
+// importBody returns the uploaded file, accepting either a raw request body
+// or a multipart form with a "file" field.
+func importBody(w http.ResponseWriter, r *http.Request) (io.Reader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("invalid multipart body: %w", err)
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("multipart body has no file field")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart body: %w", err)
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type FolderHandler struct {
+	folderService service.FolderServiceInterface
+}
+
+func NewFolderHandler(folderService service.FolderServiceInterface) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+func (h *FolderHandler) ListFolders(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	folders, err := h.folderService.ListFolders(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(folders)
+}
+
+func (h *FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	var folder models.Folder
+	if err := json.NewDecoder(r.Body).Decode(&folder); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	folder.User = claims.UserID
+
+	if err := h.folderService.CreateFolder(r.Context(), &folder); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(folder)
+}
+
+func (h *FolderHandler) RenameFolder(w http.ResponseWriter, r *http.Request) {
+	folderID := chi.URLParam(r, "id")
+	var update struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	folder, err := h.folderService.GetFolder(r.Context(), folderID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if folder.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	folder, err = h.folderService.RenameFolder(r.Context(), folderID, update.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(folder)
+}
+
+func (h *FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	folderID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	folder, err := h.folderService.GetFolder(r.Context(), folderID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if folder.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.folderService.DeleteFolder(r.Context(), claims.UserID, folderID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type ImageHandler struct {
+	imageService service.ImageServiceInterface
+	chatService  service.ChatServiceInterface
+}
+
+func NewImageHandler(imageService service.ImageServiceInterface, chatService service.ChatServiceInterface) *ImageHandler {
+	return &ImageHandler{
+		imageService: imageService,
+		chatService:  chatService,
+	}
+}
+
+type imageRequest struct {
+	Platform string `json:"platform"`
+	Model    string `json:"model"`
+	Prompt   string `json:"prompt"`
+	Size     string `json:"size"`
+	Count    int    `json:"count"`
+	Style    string `json:"style"`
+}
+
+// GenerateImages generates images with the platform and model from the body,
+// or from the Platform and Model headers used for generation. The prompt and
+// the images are added to the chat in the Chat-ID header, or to a new chat.
+func (h *ImageHandler) GenerateImages(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if claims == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body imageRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if body.Platform == "" {
+		body.Platform = r.Header.Get("Platform")
+	}
+	if body.Model == "" {
+		body.Model = r.Header.Get("Model")
+	}
+	if body.Platform == "" {
+		http.Error(w, "Platform is required", http.StatusBadRequest)
+		return
+	}
+
+	req := &models.ImageRequest{
+		Model:  body.Model,
+		Prompt: body.Prompt,
+		Size:   body.Size,
+		Count:  body.Count,
+		Style:  body.Style,
+	}
+	if err := service.ValidateImageRequest(body.Platform, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var chat *models.Chat
+	if chatID := r.Header.Get("Chat-ID"); chatID != "" {
+		var err error
+		chat, err = h.chatService.GetChat(r.Context(), chatID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if !service.CanReadChat(chat, claims.UserID) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !service.CanPostToChat(chat, claims.UserID) {
+			http.Error(w, "Viewers cannot post to the chat", http.StatusForbidden)
+			return
+		}
+		if _, ok := checkIfMatch(w, r, chat); !ok {
+			return
+		}
+	}
+
+	log.Printf("User %s (%s) requesting image generation with platform: %s, model: %s",
+		claims.Name, claims.UserID, body.Platform, body.Model)
+
+	images, err := h.imageService.GenerateImages(r.Context(), body.Platform, req)
+	if err != nil {
+		log.Printf("Error generating images for user %s: %v", claims.UserID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	chat, response, err := h.imageService.SaveImages(r.Context(), chat, claims.UserID, body.Platform, req, images)
+	if err != nil {
+		log.Printf("Error saving images for user %s: %v", claims.UserID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setChatETag(w, chat)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type KnowledgeHandler struct {
+	knowledgeService  service.KnowledgeServiceInterface
+	attachmentService service.AttachmentServiceInterface
+}
+
+func NewKnowledgeHandler(knowledgeService service.KnowledgeServiceInterface,
+	attachmentService service.AttachmentServiceInterface) *KnowledgeHandler {
+	return &KnowledgeHandler{
+		knowledgeService:  knowledgeService,
+		attachmentService: attachmentService,
+	}
+}
+
+func (h *KnowledgeHandler) ListKnowledgeBases(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	knowledgeBases, err := h.knowledgeService.ListKnowledgeBases(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(knowledgeBases)
+}
+
+func (h *KnowledgeHandler) CreateKnowledgeBase(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Platform    string `json:"platform"`
+		Model       string `json:"model"`
+		Dimensions  int    `json:"dimensions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	knowledgeBase := models.KnowledgeBase{
+		User:        claims.UserID,
+		Name:        body.Name,
+		Description: body.Description,
+		Platform:    body.Platform,
+		Model:       body.Model,
+		Dimensions:  body.Dimensions,
+	}
+
+	if err := h.knowledgeService.CreateKnowledgeBase(r.Context(), &knowledgeBase); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(knowledgeBase)
+}
+
+func (h *KnowledgeHandler) GetKnowledgeBase(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	knowledgeBase, err := h.knowledgeService.GetKnowledgeBase(r.Context(), claims.UserID, knowledgeBaseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(knowledgeBase)
+}
+
+func (h *KnowledgeHandler) UpdateKnowledgeBase(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if _, err := h.knowledgeService.GetKnowledgeBase(r.Context(), claims.UserID, knowledgeBaseID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	knowledgeBase, err := h.knowledgeService.UpdateKnowledgeBase(r.Context(), claims.UserID, knowledgeBaseID,
+		body.Name, body.Description)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(knowledgeBase)
+}
+
+func (h *KnowledgeHandler) DeleteKnowledgeBase(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if err := h.knowledgeService.DeleteKnowledgeBase(r.Context(), claims.UserID, knowledgeBaseID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *KnowledgeHandler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	documents, err := h.knowledgeService.ListDocuments(r.Context(), claims.UserID, knowledgeBaseID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(documents)
+}
+
+// UploadDocuments adds the "file" parts of a multipart form to the knowledge
+// base. Documents are indexed in the background; poll them until their status
+// is ready or failed.
+func (h *KnowledgeHandler) UploadDocuments(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if _, err := h.knowledgeService.GetKnowledgeBase(r.Context(), claims.UserID, knowledgeBaseID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxAttachmentsPerMessage*service.MaxDocumentSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["file"]
+	if len(files) == 0 {
+		http.Error(w, "No files to upload", http.StatusBadRequest)
+		return
+	}
+	if len(files) > service.MaxAttachmentsPerMessage {
+		http.Error(w, fmt.Sprintf("At most %d files can be uploaded at once", service.MaxAttachmentsPerMessage),
+			http.StatusBadRequest)
+		return
+	}
+
+	attachments := make([]*models.Attachment, 0, len(files))
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "Error reading file", http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, service.MaxDocumentSize+1))
+		file.Close()
+		if err != nil {
+			http.Error(w, "Error reading file", http.StatusBadRequest)
+			return
+		}
+
+		attachment, err := h.attachmentService.NewAttachment(claims.UserID, header.Filename, data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	documents := make([]*models.KnowledgeDocument, 0, len(attachments))
+	for _, attachment := range attachments {
+		document, err := h.knowledgeService.AddDocument(r.Context(), claims.UserID, knowledgeBaseID, attachment)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		documents = append(documents, document)
+	}
+	for _, document := range documents {
+		h.index(r.Context(), document)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(documents)
+}
+
+func (h *KnowledgeHandler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	documentID := chi.URLParam(r, "documentID")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	document, err := h.knowledgeService.GetDocument(r.Context(), claims.UserID, knowledgeBaseID, documentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(document)
+}
+
+func (h *KnowledgeHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	documentID := chi.URLParam(r, "documentID")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if err := h.knowledgeService.DeleteDocument(r.Context(), claims.UserID, knowledgeBaseID, documentID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReindexDocument chunks and embeds a document again, for example after a
+// failed attempt.
+func (h *KnowledgeHandler) ReindexDocument(w http.ResponseWriter, r *http.Request) {
+	knowledgeBaseID := chi.URLParam(r, "id")
+	documentID := chi.URLParam(r, "documentID")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if _, err := h.knowledgeService.GetDocument(r.Context(), claims.UserID, knowledgeBaseID, documentID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	document, err := h.knowledgeService.ReindexDocument(r.Context(), claims.UserID, knowledgeBaseID, documentID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrDocumentIndexing):
+			status = http.StatusConflict
+		case errors.Is(err, service.ErrDocumentNotFound):
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	h.index(r.Context(), document)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(document)
+}
+
+func (h *KnowledgeHandler) index(ctx context.Context, document *models.KnowledgeDocument) {
+	indexed := *document
+	go func() {
+		if err := h.knowledgeService.IndexDocument(context.WithoutCancel(ctx), &indexed); err != nil {
+			log.Printf("Error indexing document %s: %v", indexed.ID, err)
+		}
+	}()
+}
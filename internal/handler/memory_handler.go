@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type MemoryHandler struct {
+	memoryService service.MemoryServiceInterface
+}
+
+func NewMemoryHandler(memoryService service.MemoryServiceInterface) *MemoryHandler {
+	return &MemoryHandler{
+		memoryService: memoryService,
+	}
+}
+
+func (h *MemoryHandler) ListMemories(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	memories, err := h.memoryService.ListMemories(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(memories)
+}
+
+func (h *MemoryHandler) CreateMemory(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	memory := models.Memory{
+		User:   claims.UserID,
+		Text:   body.Text,
+		Source: models.MemorySourceUser,
+	}
+
+	if err := h.memoryService.AddMemory(r.Context(), &memory); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(memory)
+}
+
+func (h *MemoryHandler) UpdateMemory(w http.ResponseWriter, r *http.Request) {
+	memoryID := chi.URLParam(r, "id")
+	var update models.MemoryUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if _, err := h.memoryService.GetMemory(r.Context(), claims.UserID, memoryID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	memory, err := h.memoryService.UpdateMemory(r.Context(), claims.UserID, memoryID, update)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(memory)
+}
+
+func (h *MemoryHandler) DeleteMemory(w http.ResponseWriter, r *http.Request) {
+	memoryID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	if err := h.memoryService.DeleteMemory(r.Context(), claims.UserID, memoryID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type ShareHandler struct {
+	chatService  service.ChatServiceInterface
+	shareService service.ShareServiceInterface
+}
+
+func NewShareHandler(chatService service.ChatServiceInterface,
+	shareService service.ShareServiceInterface) *ShareHandler {
+	return &ShareHandler{
+		chatService:  chatService,
+		shareService: shareService,
+	}
+}
+
+func (h *ShareHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	var body struct {
+		MessageID      string `json:"message_id"`
+		ExpiresInHours int    `json:"expires_in_hours"`
+		HideModels     bool   `json:"hide_models"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if chat.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	share, err := h.shareService.CreateShare(r.Context(), chat, service.ShareOptions{
+		MessageID:  body.MessageID,
+		ExpiresIn:  time.Duration(body.ExpiresInHours) * time.Hour,
+		HideModels: body.HideModels,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	share.Messages = nil
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(share)
+}
+
+func (h *ShareHandler) ListShares(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	chat, err := h.chatService.GetChat(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if chat.User != claims.UserID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	shares, err := h.shareService.ListShares(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shares)
+}
+
+func (h *ShareHandler) RevokeShare(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "id")
+	shareID := chi.URLParam(r, "shareID")
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	share, err := h.shareService.GetShare(r.Context(), shareID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if share.User != claims.UserID || share.ChatID != chatID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.shareService.RevokeShare(r.Context(), shareID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ShareHandler) GetSharedChat(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	shared, err := h.shareService.GetSharedChat(r.Context(), token)
+	if err != nil {
+		http.Error(w, "Share not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(shared)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type TemplateHandler struct {
+	templateService service.TemplateServiceInterface
+}
+
+func NewTemplateHandler(templateService service.TemplateServiceInterface) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	templates, err := h.templateService.ListTemplates(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var tmpl models.PromptTemplate
+	if err := json.NewDecoder(r.Body).Decode(&tmpl); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if tmpl.Shared && claims.Role != models.RoleAdmin {
+		http.Error(w, "Only admins can share templates", http.StatusForbidden)
+		return
+	}
+	tmpl.User = claims.UserID
+
+	if err := h.templateService.CreateTemplate(r.Context(), &tmpl); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tmpl)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	tmpl, ok := h.readableTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	json.NewEncoder(w).Encode(tmpl)
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	var update models.PromptTemplateUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+	if update.Shared != nil && claims.Role != models.RoleAdmin {
+		http.Error(w, "Only admins can share templates", http.StatusForbidden)
+		return
+	}
+
+	tmpl, ok := h.editableTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	tmpl, err := h.templateService.UpdateTemplate(r.Context(), tmpl.ID, update)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(tmpl)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	tmpl, ok := h.editableTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(r.Context(), tmpl.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TemplateHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	tmpl, ok := h.readableTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	versions, err := h.templateService.ListVersions(r.Context(), tmpl.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+func (h *TemplateHandler) RenderTemplate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Version   int            `json:"version"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tmpl, ok := h.readableTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	text, err := h.templateService.RenderTemplate(r.Context(), tmpl, body.Version, body.Variables)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"text": text})
+}
+
+// readableTemplate loads the template from the URL and checks that the user
+// owns it or that it is shared.
+func (h *TemplateHandler) readableTemplate(w http.ResponseWriter, r *http.Request) (*models.PromptTemplate, bool) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	tmpl, err := h.templateService.GetTemplate(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+
+	if tmpl.User != claims.UserID && !tmpl.Shared {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return tmpl, true
+}
+
+// editableTemplate loads the template from the URL and checks that the user
+// owns it or is an admin editing a shared template.
+func (h *TemplateHandler) editableTemplate(w http.ResponseWriter, r *http.Request) (*models.PromptTemplate, bool) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	tmpl, err := h.templateService.GetTemplate(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+
+	if tmpl.User != claims.UserID && !(tmpl.Shared && claims.Role == models.RoleAdmin) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return tmpl, true
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type ToolHandler struct {
+	toolRunner service.ToolRunnerInterface
+}
+
+func NewToolHandler(toolRunner service.ToolRunnerInterface) *ToolHandler {
+	return &ToolHandler{
+		toolRunner: toolRunner,
+	}
+}
+
+// ListTools returns the server-side tools a generation can enable.
+func (h *ToolHandler) ListTools(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.toolRunner.Tools())
+}
+
+// ListToolRuns returns the caller's latest server-side tool runs, optionally
+// limited to one chat with the chat_id query parameter.
+func (h *ToolHandler) ListToolRuns(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
+
+	runs, err := h.toolRunner.ListToolRuns(r.Context(), claims.UserID, r.URL.Query().Get("chat_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
@@ -3,8 +3,10 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/lutefd/ai-router-go/internal/middleware"
+	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/service"
 )
 
@@ -21,7 +23,29 @@ func NewUserHandler(userService service.UserServiceInterface) *UserHandler {
 func (h *UserHandler) GetUserChats(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value(middleware.UserContextKey).(*service.Claims)
 
-	chats, err := h.userService.GetUsersChatList(r.Context(), claims.UserID)
+	query := r.URL.Query()
+	filter := models.ChatListFilter{
+		FolderID: query.Get("folder"),
+		Tags:     query["tag"],
+	}
+	if archived := query.Get("archived"); archived != "" {
+		value, err := strconv.ParseBool(archived)
+		if err != nil {
+			http.Error(w, "archived must be a boolean", http.StatusBadRequest)
+			return
+		}
+		filter.Archived = value
+	}
+	if pinned := query.Get("pinned"); pinned != "" {
+		value, err := strconv.ParseBool(pinned)
+		if err != nil {
+			http.Error(w, "pinned must be a boolean", http.StatusBadRequest)
+			return
+		}
+		filter.Pinned = &value
+	}
+
+	chats, err := h.userService.GetUsersChatList(r.Context(), claims.UserID, filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
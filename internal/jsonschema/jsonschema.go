@@ -0,0 +1,489 @@
+// Package jsonschema validates JSON documents against the subset of JSON
+// Schema that model providers accept for structured output: types, enums,
+// object properties, array items, string, number and size bounds, the anyOf,
+// oneOf and allOf combinators, and local $ref into $defs. Keywords outside
+// that subset, such as description or format, are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxDepth = 128
+
+var knownTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// ValidationError reports the first place a document does not match its
+// schema. Path is a JSONPath expression such as $.items[2].price.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+type Schema struct {
+	root *node
+	defs map[string]*node
+}
+
+type node struct {
+	types            []string
+	nullable         bool
+	enum             []any
+	constant         any
+	hasConst         bool
+	properties       map[string]*node
+	required         []string
+	noAdditional     bool
+	additional       *node
+	items            *node
+	minItems         *int
+	maxItems         *int
+	minLength        *int
+	maxLength        *int
+	pattern          *regexp.Regexp
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	anyOf            []*node
+	oneOf            []*node
+	allOf            []*node
+	ref              string
+}
+
+// Compile parses a schema, reporting keywords with invalid values and
+// references that cannot be resolved.
+func Compile(raw json.RawMessage) (*Schema, error) {
+	var document map[string]any
+	if err := json.Unmarshal(raw, &document); err != nil || document == nil {
+		return nil, fmt.Errorf("schema must be a JSON object")
+	}
+
+	s := &Schema{defs: make(map[string]*node)}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		defs, ok := document[keyword].(map[string]any)
+		if !ok {
+			continue
+		}
+		for name, def := range defs {
+			n, err := parse(def, "#/"+keyword+"/"+name)
+			if err != nil {
+				return nil, err
+			}
+			s.defs["#/"+keyword+"/"+name] = n
+		}
+	}
+	root, err := parse(document, "#")
+	if err != nil {
+		return nil, err
+	}
+	s.root = root
+	s.defs["#"] = root
+
+	for location, n := range s.defs {
+		if err := s.checkRefs(n, location); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *Schema) checkRefs(n *node, location string) error {
+	if n.ref != "" {
+		if _, ok := s.defs[n.ref]; !ok {
+			return fmt.Errorf("invalid schema at %s: unresolved $ref %q", location, n.ref)
+		}
+	}
+	for name, child := range n.properties {
+		if err := s.checkRefs(child, location+"/properties/"+name); err != nil {
+			return err
+		}
+	}
+	children := append(append(append([]*node{}, n.anyOf...), n.oneOf...), n.allOf...)
+	if n.items != nil {
+		children = append(children, n.items)
+	}
+	if n.additional != nil {
+		children = append(children, n.additional)
+	}
+	for _, child := range children {
+		if err := s.checkRefs(child, location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parse(value any, location string) (*node, error) {
+	schema, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid schema at %s: must be an object", location)
+	}
+	invalid := func(keyword string) error {
+		return fmt.Errorf("invalid schema at %s: invalid %s", location, keyword)
+	}
+
+	n := &node{}
+	for keyword, value := range schema {
+		var err error
+		switch keyword {
+		case "type":
+			switch t := value.(type) {
+			case string:
+				n.types = []string{t}
+			case []any:
+				for _, item := range t {
+					name, ok := item.(string)
+					if !ok {
+						return nil, invalid(keyword)
+					}
+					n.types = append(n.types, name)
+				}
+			default:
+				return nil, invalid(keyword)
+			}
+			for _, name := range n.types {
+				if !knownTypes[name] {
+					return nil, fmt.Errorf("invalid schema at %s: unknown type %q", location, name)
+				}
+			}
+		case "nullable":
+			n.nullable, ok = value.(bool)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+		case "enum":
+			n.enum, ok = value.([]any)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+		case "const":
+			n.constant, n.hasConst = value, true
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+			n.properties = make(map[string]*node, len(properties))
+			for name, property := range properties {
+				if n.properties[name], err = parse(property, location+"/properties/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			names, ok := value.([]any)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+			for _, item := range names {
+				name, ok := item.(string)
+				if !ok {
+					return nil, invalid(keyword)
+				}
+				n.required = append(n.required, name)
+			}
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				n.noAdditional = !allowed
+			} else if n.additional, err = parse(value, location+"/additionalProperties"); err != nil {
+				return nil, err
+			}
+		case "items":
+			if n.items, err = parse(value, location+"/items"); err != nil {
+				return nil, err
+			}
+		case "minItems", "maxItems", "minLength", "maxLength":
+			number, ok := value.(float64)
+			if !ok || number < 0 || number != math.Trunc(number) {
+				return nil, invalid(keyword)
+			}
+			size := int(number)
+			switch keyword {
+			case "minItems":
+				n.minItems = &size
+			case "maxItems":
+				n.maxItems = &size
+			case "minLength":
+				n.minLength = &size
+			case "maxLength":
+				n.maxLength = &size
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			number, ok := value.(float64)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+			switch keyword {
+			case "minimum":
+				n.minimum = &number
+			case "maximum":
+				n.maximum = &number
+			case "exclusiveMinimum":
+				n.exclusiveMinimum = &number
+			case "exclusiveMaximum":
+				n.exclusiveMaximum = &number
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+			if n.pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, invalid(keyword)
+			}
+		case "anyOf", "oneOf", "allOf":
+			options, ok := value.([]any)
+			if !ok || len(options) == 0 {
+				return nil, invalid(keyword)
+			}
+			nodes := make([]*node, 0, len(options))
+			for i, option := range options {
+				child, err := parse(option, fmt.Sprintf("%s/%s/%d", location, keyword, i))
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, child)
+			}
+			switch keyword {
+			case "anyOf":
+				n.anyOf = nodes
+			case "oneOf":
+				n.oneOf = nodes
+			case "allOf":
+				n.allOf = nodes
+			}
+		case "$ref":
+			n.ref, ok = value.(string)
+			if !ok {
+				return nil, invalid(keyword)
+			}
+		}
+	}
+	return n, nil
+}
+
+// Validate checks that data is a single JSON document matching the schema.
+// The error is a *ValidationError.
+func (s *Schema) Validate(data []byte) error {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return &ValidationError{Path: "$", Message: "invalid JSON: " + err.Error()}
+	}
+	if err := s.validate(s.root, document, "$", 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Schema) validate(n *node, value any, path string, depth int) *ValidationError {
+	fail := func(format string, args ...any) *ValidationError {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+	if depth > maxDepth {
+		return fail("schema nested too deeply")
+	}
+	if n.ref != "" {
+		return s.validate(s.defs[n.ref], value, path, depth+1)
+	}
+	if value == nil && n.nullable {
+		return nil
+	}
+
+	if len(n.types) > 0 && !matchesType(n.types, value) {
+		return fail("expected %s, got %s", strings.Join(n.types, " or "), typeOf(value))
+	}
+	if n.hasConst && !reflect.DeepEqual(n.constant, value) {
+		return fail("must be %s", encode(n.constant))
+	}
+	if n.enum != nil && !contains(n.enum, value) {
+		allowed := make([]string, 0, len(n.enum))
+		for _, option := range n.enum {
+			allowed = append(allowed, encode(option))
+		}
+		return fail("must be one of %s", strings.Join(allowed, ", "))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if err := s.validateObject(n, v, path, depth); err != nil {
+			return err
+		}
+	case []any:
+		if n.minItems != nil && len(v) < *n.minItems {
+			return fail("must have at least %d items", *n.minItems)
+		}
+		if n.maxItems != nil && len(v) > *n.maxItems {
+			return fail("must have at most %d items", *n.maxItems)
+		}
+		if n.items != nil {
+			for i, item := range v {
+				if err := s.validate(n.items, item, fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := len([]rune(v))
+		if n.minLength != nil && length < *n.minLength {
+			return fail("must be at least %d characters long", *n.minLength)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			return fail("must be at most %d characters long", *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(v) {
+			return fail("must match the pattern %q", n.pattern.String())
+		}
+	case float64:
+		if n.minimum != nil && v < *n.minimum {
+			return fail("must be at least %s", formatNumber(*n.minimum))
+		}
+		if n.maximum != nil && v > *n.maximum {
+			return fail("must be at most %s", formatNumber(*n.maximum))
+		}
+		if n.exclusiveMinimum != nil && v <= *n.exclusiveMinimum {
+			return fail("must be greater than %s", formatNumber(*n.exclusiveMinimum))
+		}
+		if n.exclusiveMaximum != nil && v >= *n.exclusiveMaximum {
+			return fail("must be less than %s", formatNumber(*n.exclusiveMaximum))
+		}
+	}
+
+	for _, option := range n.allOf {
+		if err := s.validate(option, value, path, depth+1); err != nil {
+			return err
+		}
+	}
+	if len(n.anyOf) > 0 {
+		matched := false
+		for _, option := range n.anyOf {
+			if s.validate(option, value, path, depth+1) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail("does not match any of the allowed schemas")
+		}
+	}
+	if len(n.oneOf) > 0 {
+		matches := 0
+		for _, option := range n.oneOf {
+			if s.validate(option, value, path, depth+1) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fail("must match exactly one of the allowed schemas, matches %d", matches)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(n *node, object map[string]any, path string, depth int) *ValidationError {
+	for _, name := range n.required {
+		if _, ok := object[name]; !ok {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childPath := propertyPath(path, name)
+		if property, ok := n.properties[name]; ok {
+			if err := s.validate(property, object[name], childPath, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		if n.noAdditional {
+			return &ValidationError{Path: childPath, Message: "property is not allowed"}
+		}
+		if n.additional != nil {
+			if err := s.validate(n.additional, object[name], childPath, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func propertyPath(path string, name string) string {
+	if identifier.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+func matchesType(types []string, value any) bool {
+	for _, name := range types {
+		switch name {
+		case "integer":
+			if number, ok := value.(float64); ok && number == math.Trunc(number) {
+				return true
+			}
+		default:
+			if typeOf(value) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func contains(options []any, value any) bool {
+	for _, option := range options {
+		if reflect.DeepEqual(option, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'g', -1, 64)
+}
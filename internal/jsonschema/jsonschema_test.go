@@ -0,0 +1,168 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const invoiceSchema = `{
+	"type": "object",
+	"properties": {
+		"number": {"type": "string", "pattern": "^INV-[0-9]+$"},
+		"status": {"enum": ["draft", "paid"]},
+		"customer": {"$ref": "#/$defs/customer"},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"quantity": {"type": "integer", "exclusiveMinimum": 0},
+					"price": {"type": "number", "minimum": 0}
+				},
+				"required": ["name", "quantity", "price"],
+				"additionalProperties": false
+			}
+		},
+		"notes": {"type": ["string", "null"]},
+		"discount": {"anyOf": [{"type": "number", "maximum": 1}, {"type": "string", "enum": ["none"]}]},
+		"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+	},
+	"required": ["number", "items"],
+	"$defs": {
+		"customer": {
+			"type": "object",
+			"properties": {"email": {"type": "string"}},
+			"required": ["email"]
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	schema, err := jsonschema.Compile(json.RawMessage(invoiceSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		document string
+		wantPath string
+	}{
+		{
+			name:     "valid",
+			document: `{"number":"INV-1","status":"paid","items":[{"name":"Pen","quantity":2,"price":1.5}],"notes":null,"discount":0.1,"labels":{"a b":"c"}}`,
+		},
+		{
+			name:     "integers may be written with a fraction",
+			document: `{"number":"INV-1","items":[{"name":"Pen","quantity":2.0,"price":0}],"discount":"none"}`,
+		},
+		{name: "not JSON", document: `{"number":`, wantPath: "$"},
+		{name: "trailing data", document: `{"number":"INV-1","items":[]} {}`, wantPath: "$"},
+		{name: "wrong root type", document: `[]`, wantPath: "$"},
+		{name: "missing property", document: `{"number":"INV-1"}`, wantPath: "$"},
+		{name: "pattern", document: `{"number":"1","items":[{"name":"Pen","quantity":1,"price":1}]}`, wantPath: "$.number"},
+		{name: "min items", document: `{"number":"INV-1","items":[]}`, wantPath: "$.items"},
+		{
+			name:     "nested type",
+			document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1,"price":1},{"name":"Ink","quantity":1,"price":"2"}]}`,
+			wantPath: "$.items[1].price",
+		},
+		{
+			name:     "not an integer",
+			document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1.5,"price":1}]}`,
+			wantPath: "$.items[0].quantity",
+		},
+		{
+			name:     "exclusive minimum",
+			document: `{"number":"INV-1","items":[{"name":"Pen","quantity":0,"price":1}]}`,
+			wantPath: "$.items[0].quantity",
+		},
+		{
+			name:     "additional property",
+			document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1,"price":1,"tax":1}]}`,
+			wantPath: "$.items[0].tax",
+		},
+		{name: "enum", document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1,"price":1}],"status":"void"}`, wantPath: "$.status"},
+		{name: "ref", document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1,"price":1}],"customer":{}}`, wantPath: "$.customer"},
+		{name: "anyOf", document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1,"price":1}],"discount":2}`, wantPath: "$.discount"},
+		{
+			name:     "additional properties schema",
+			document: `{"number":"INV-1","items":[{"name":"Pen","quantity":1,"price":1}],"labels":{"a b":1}}`,
+			wantPath: `$.labels["a b"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.document))
+			if tt.wantPath == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *jsonschema.ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.wantPath, validationErr.Path)
+			assert.NotEmpty(t, validationErr.Message)
+		})
+	}
+}
+
+func TestValidate_OneOf(t *testing.T) {
+	schema, err := jsonschema.Compile(json.RawMessage(`{"oneOf":[{"type":"integer"},{"type":"number","minimum":1}]}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate([]byte(`0`)))
+	assert.NoError(t, schema.Validate([]byte(`1.5`)))
+	assert.Error(t, schema.Validate([]byte(`2`)), "matches both")
+	assert.Error(t, schema.Validate([]byte(`"2"`)), "matches neither")
+}
+
+func TestValidate_Nullable(t *testing.T) {
+	schema, err := jsonschema.Compile(json.RawMessage(`{"type":"string","nullable":true}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate([]byte(`null`)))
+	assert.Error(t, schema.Validate([]byte(`1`)))
+}
+
+func TestValidate_RecursiveRef(t *testing.T) {
+	schema, err := jsonschema.Compile(json.RawMessage(`{
+		"type": "object",
+		"properties": {"children": {"type": "array", "items": {"$ref": "#"}}}
+	}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate([]byte(`{"children":[{"children":[]}]}`)))
+	err = schema.Validate([]byte(`{"children":[{"children":[1]}]}`))
+	var validationErr *jsonschema.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "$.children[0].children[0]", validationErr.Path)
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "not an object", schema: `[]`},
+		{name: "unknown type", schema: `{"type":"date"}`},
+		{name: "invalid required", schema: `{"required":"name"}`},
+		{name: "invalid pattern", schema: `{"pattern":"("}`},
+		{name: "negative size", schema: `{"minItems":-1}`},
+		{name: "empty anyOf", schema: `{"anyOf":[]}`},
+		{name: "invalid property", schema: `{"properties":{"name":"string"}}`},
+		{name: "unresolved ref", schema: `{"properties":{"name":{"$ref":"#/$defs/name"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jsonschema.Compile(json.RawMessage(tt.schema))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -11,7 +11,9 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/lutefd/ai-router-go/internal/models"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +43,20 @@ func (m *MockAIRepositoryInterface) EXPECT() *MockAIRepositoryInterfaceMockRecor
 	return m.recorder
 }
 
+// GenerateChatStream mocks base method.
+func (m *MockAIRepositoryInterface) GenerateChatStream(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateChatStream", ctx, req, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateChatStream indicates an expected call of GenerateChatStream.
+func (mr *MockAIRepositoryInterfaceMockRecorder) GenerateChatStream(ctx, req, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChatStream", reflect.TypeOf((*MockAIRepositoryInterface)(nil).GenerateChatStream), ctx, req, callback)
+}
+
 // GenerateContentStream mocks base method.
 func (m *MockAIRepositoryInterface) GenerateContentStream(ctx context.Context, model, prompt string, callback func(string)) error {
 	m.ctrl.T.Helper()
@@ -55,6 +71,207 @@ func (mr *MockAIRepositoryInterfaceMockRecorder) GenerateContentStream(ctx, mode
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateContentStream", reflect.TypeOf((*MockAIRepositoryInterface)(nil).GenerateContentStream), ctx, model, prompt, callback)
 }
 
+// MockEmbeddingRepositoryInterface is a mock of EmbeddingRepositoryInterface interface.
+type MockEmbeddingRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmbeddingRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockEmbeddingRepositoryInterfaceMockRecorder is the mock recorder for MockEmbeddingRepositoryInterface.
+type MockEmbeddingRepositoryInterfaceMockRecorder struct {
+	mock *MockEmbeddingRepositoryInterface
+}
+
+// NewMockEmbeddingRepositoryInterface creates a new mock instance.
+func NewMockEmbeddingRepositoryInterface(ctrl *gomock.Controller) *MockEmbeddingRepositoryInterface {
+	mock := &MockEmbeddingRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockEmbeddingRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmbeddingRepositoryInterface) EXPECT() *MockEmbeddingRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateEmbeddings mocks base method.
+func (m *MockEmbeddingRepositoryInterface) CreateEmbeddings(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmbeddings", ctx, req)
+	ret0, _ := ret[0].(*models.EmbeddingResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmbeddings indicates an expected call of CreateEmbeddings.
+func (mr *MockEmbeddingRepositoryInterfaceMockRecorder) CreateEmbeddings(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmbeddings", reflect.TypeOf((*MockEmbeddingRepositoryInterface)(nil).CreateEmbeddings), ctx, req)
+}
+
+// MockImageRepositoryInterface is a mock of ImageRepositoryInterface interface.
+type MockImageRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockImageRepositoryInterfaceMockRecorder is the mock recorder for MockImageRepositoryInterface.
+type MockImageRepositoryInterfaceMockRecorder struct {
+	mock *MockImageRepositoryInterface
+}
+
+// NewMockImageRepositoryInterface creates a new mock instance.
+func NewMockImageRepositoryInterface(ctrl *gomock.Controller) *MockImageRepositoryInterface {
+	mock := &MockImageRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockImageRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageRepositoryInterface) EXPECT() *MockImageRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GenerateImages mocks base method.
+func (m *MockImageRepositoryInterface) GenerateImages(ctx context.Context, req *models.ImageRequest) ([]models.GeneratedImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateImages", ctx, req)
+	ret0, _ := ret[0].([]models.GeneratedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateImages indicates an expected call of GenerateImages.
+func (mr *MockImageRepositoryInterfaceMockRecorder) GenerateImages(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateImages", reflect.TypeOf((*MockImageRepositoryInterface)(nil).GenerateImages), ctx, req)
+}
+
+// MockAudioRepositoryInterface is a mock of AudioRepositoryInterface interface.
+type MockAudioRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAudioRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAudioRepositoryInterfaceMockRecorder is the mock recorder for MockAudioRepositoryInterface.
+type MockAudioRepositoryInterfaceMockRecorder struct {
+	mock *MockAudioRepositoryInterface
+}
+
+// NewMockAudioRepositoryInterface creates a new mock instance.
+func NewMockAudioRepositoryInterface(ctrl *gomock.Controller) *MockAudioRepositoryInterface {
+	mock := &MockAudioRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAudioRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudioRepositoryInterface) EXPECT() *MockAudioRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Speak mocks base method.
+func (m *MockAudioRepositoryInterface) Speak(ctx context.Context, req *models.SpeechRequest) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Speak", ctx, req)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Speak indicates an expected call of Speak.
+func (mr *MockAudioRepositoryInterfaceMockRecorder) Speak(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Speak", reflect.TypeOf((*MockAudioRepositoryInterface)(nil).Speak), ctx, req)
+}
+
+// Transcribe mocks base method.
+func (m *MockAudioRepositoryInterface) Transcribe(ctx context.Context, req *models.TranscriptionRequest) (*models.Transcription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transcribe", ctx, req)
+	ret0, _ := ret[0].(*models.Transcription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transcribe indicates an expected call of Transcribe.
+func (mr *MockAudioRepositoryInterfaceMockRecorder) Transcribe(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transcribe", reflect.TypeOf((*MockAudioRepositoryInterface)(nil).Transcribe), ctx, req)
+}
+
+// MockCachedContentRepositoryInterface is a mock of CachedContentRepositoryInterface interface.
+type MockCachedContentRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCachedContentRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCachedContentRepositoryInterfaceMockRecorder is the mock recorder for MockCachedContentRepositoryInterface.
+type MockCachedContentRepositoryInterfaceMockRecorder struct {
+	mock *MockCachedContentRepositoryInterface
+}
+
+// NewMockCachedContentRepositoryInterface creates a new mock instance.
+func NewMockCachedContentRepositoryInterface(ctrl *gomock.Controller) *MockCachedContentRepositoryInterface {
+	mock := &MockCachedContentRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockCachedContentRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCachedContentRepositoryInterface) EXPECT() *MockCachedContentRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateCachedContent mocks base method.
+func (m *MockCachedContentRepositoryInterface) CreateCachedContent(ctx context.Context, model string, messages []models.Message, ttl time.Duration) (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCachedContent", ctx, model, messages, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateCachedContent indicates an expected call of CreateCachedContent.
+func (mr *MockCachedContentRepositoryInterfaceMockRecorder) CreateCachedContent(ctx, model, messages, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCachedContent", reflect.TypeOf((*MockCachedContentRepositoryInterface)(nil).CreateCachedContent), ctx, model, messages, ttl)
+}
+
+// DeleteCachedContent mocks base method.
+func (m *MockCachedContentRepositoryInterface) DeleteCachedContent(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCachedContent", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCachedContent indicates an expected call of DeleteCachedContent.
+func (mr *MockCachedContentRepositoryInterfaceMockRecorder) DeleteCachedContent(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCachedContent", reflect.TypeOf((*MockCachedContentRepositoryInterface)(nil).DeleteCachedContent), ctx, name)
+}
+
+// RefreshCachedContent mocks base method.
+func (m *MockCachedContentRepositoryInterface) RefreshCachedContent(ctx context.Context, name string, ttl time.Duration) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCachedContent", ctx, name, ttl)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshCachedContent indicates an expected call of RefreshCachedContent.
+func (mr *MockCachedContentRepositoryInterfaceMockRecorder) RefreshCachedContent(ctx, name, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCachedContent", reflect.TypeOf((*MockCachedContentRepositoryInterface)(nil).RefreshCachedContent), ctx, name, ttl)
+}
+
 // MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
 type MockUserRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -138,18 +355,18 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) GetUserByEmail(ctx, email any
 }
 
 // GetUsersChatList mocks base method.
-func (m *MockUserRepositoryInterface) GetUsersChatList(ctx context.Context, userID string) ([]*models.UserChat, error) {
+func (m *MockUserRepositoryInterface) GetUsersChatList(ctx context.Context, userID string, filter models.ChatListFilter) ([]*models.UserChat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsersChatList", ctx, userID)
+	ret := m.ctrl.Call(m, "GetUsersChatList", ctx, userID, filter)
 	ret0, _ := ret[0].([]*models.UserChat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUsersChatList indicates an expected call of GetUsersChatList.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetUsersChatList(ctx, userID any) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetUsersChatList(ctx, userID, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersChatList", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUsersChatList), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersChatList", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUsersChatList), ctx, userID, filter)
 }
 
 // ListUsers mocks base method.
@@ -205,6 +422,34 @@ func (m *MockChatRepositoryInterface) EXPECT() *MockChatRepositoryInterfaceMockR
 	return m.recorder
 }
 
+// ClearAssistant mocks base method.
+func (m *MockChatRepositoryInterface) ClearAssistant(ctx context.Context, assistantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearAssistant", ctx, assistantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearAssistant indicates an expected call of ClearAssistant.
+func (mr *MockChatRepositoryInterfaceMockRecorder) ClearAssistant(ctx, assistantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAssistant", reflect.TypeOf((*MockChatRepositoryInterface)(nil).ClearAssistant), ctx, assistantID)
+}
+
+// ClearFolder mocks base method.
+func (m *MockChatRepositoryInterface) ClearFolder(ctx context.Context, userID, folderID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearFolder", ctx, userID, folderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearFolder indicates an expected call of ClearFolder.
+func (mr *MockChatRepositoryInterfaceMockRecorder) ClearFolder(ctx, userID, folderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearFolder", reflect.TypeOf((*MockChatRepositoryInterface)(nil).ClearFolder), ctx, userID, folderID)
+}
+
 // CreateChat mocks base method.
 func (m *MockChatRepositoryInterface) CreateChat(ctx context.Context, chat *models.Chat) error {
 	m.ctrl.T.Helper()
@@ -220,17 +465,47 @@ func (mr *MockChatRepositoryInterfaceMockRecorder) CreateChat(ctx, chat any) *go
 }
 
 // DeleteChat mocks base method.
-func (m *MockChatRepositoryInterface) DeleteChat(ctx context.Context, chatID string) error {
+func (m *MockChatRepositoryInterface) DeleteChat(ctx context.Context, chatID string, version int64) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChat", ctx, chatID, version)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChat indicates an expected call of DeleteChat.
+func (mr *MockChatRepositoryInterfaceMockRecorder) DeleteChat(ctx, chatID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).DeleteChat), ctx, chatID, version)
+}
+
+// DeleteChats mocks base method.
+func (m *MockChatRepositoryInterface) DeleteChats(ctx context.Context, userID string, chatIDs []string) ([]*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChats", ctx, userID, chatIDs)
+	ret0, _ := ret[0].([]*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChats indicates an expected call of DeleteChats.
+func (mr *MockChatRepositoryInterfaceMockRecorder) DeleteChats(ctx, userID, chatIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChats", reflect.TypeOf((*MockChatRepositoryInterface)(nil).DeleteChats), ctx, userID, chatIDs)
+}
+
+// ForEachUserChat mocks base method.
+func (m *MockChatRepositoryInterface) ForEachUserChat(ctx context.Context, userID string, fn func(*models.Chat) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteChat", ctx, chatID)
+	ret := m.ctrl.Call(m, "ForEachUserChat", ctx, userID, fn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteChat indicates an expected call of DeleteChat.
-func (mr *MockChatRepositoryInterfaceMockRecorder) DeleteChat(ctx, chatID any) *gomock.Call {
+// ForEachUserChat indicates an expected call of ForEachUserChat.
+func (mr *MockChatRepositoryInterfaceMockRecorder) ForEachUserChat(ctx, userID, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).DeleteChat), ctx, chatID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachUserChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).ForEachUserChat), ctx, userID, fn)
 }
 
 // GetChat mocks base method.
@@ -248,16 +523,1131 @@ func (mr *MockChatRepositoryInterfaceMockRecorder) GetChat(ctx, chatID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).GetChat), ctx, chatID)
 }
 
-// UpdateChat mocks base method.
-func (m *MockChatRepositoryInterface) UpdateChat(ctx context.Context, chat *models.Chat) error {
+// ListChatsPendingMemory mocks base method.
+func (m *MockChatRepositoryInterface) ListChatsPendingMemory(ctx context.Context, idleSince time.Time, limit int) ([]*models.Chat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateChat", ctx, chat)
+	ret := m.ctrl.Call(m, "ListChatsPendingMemory", ctx, idleSince, limit)
+	ret0, _ := ret[0].([]*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChatsPendingMemory indicates an expected call of ListChatsPendingMemory.
+func (mr *MockChatRepositoryInterfaceMockRecorder) ListChatsPendingMemory(ctx, idleSince, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChatsPendingMemory", reflect.TypeOf((*MockChatRepositoryInterface)(nil).ListChatsPendingMemory), ctx, idleSince, limit)
+}
+
+// ListDeletedChats mocks base method.
+func (m *MockChatRepositoryInterface) ListDeletedChats(ctx context.Context, userID string) ([]*models.UserChat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedChats", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserChat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedChats indicates an expected call of ListDeletedChats.
+func (mr *MockChatRepositoryInterfaceMockRecorder) ListDeletedChats(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedChats", reflect.TypeOf((*MockChatRepositoryInterface)(nil).ListDeletedChats), ctx, userID)
+}
+
+// PurgeChat mocks base method.
+func (m *MockChatRepositoryInterface) PurgeChat(ctx context.Context, userID, chatID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeChat", ctx, userID, chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeChat indicates an expected call of PurgeChat.
+func (mr *MockChatRepositoryInterfaceMockRecorder) PurgeChat(ctx, userID, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).PurgeChat), ctx, userID, chatID)
+}
+
+// PurgeDeletedChats mocks base method.
+func (m *MockChatRepositoryInterface) PurgeDeletedChats(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedChats", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedChats indicates an expected call of PurgeDeletedChats.
+func (mr *MockChatRepositoryInterfaceMockRecorder) PurgeDeletedChats(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedChats", reflect.TypeOf((*MockChatRepositoryInterface)(nil).PurgeDeletedChats), ctx, before)
+}
+
+// RecordMemoryFailure mocks base method.
+func (m *MockChatRepositoryInterface) RecordMemoryFailure(ctx context.Context, chatID string, retryAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMemoryFailure", ctx, chatID, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMemoryFailure indicates an expected call of RecordMemoryFailure.
+func (mr *MockChatRepositoryInterfaceMockRecorder) RecordMemoryFailure(ctx, chatID, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMemoryFailure", reflect.TypeOf((*MockChatRepositoryInterface)(nil).RecordMemoryFailure), ctx, chatID, retryAt)
+}
+
+// RestoreChat mocks base method.
+func (m *MockChatRepositoryInterface) RestoreChat(ctx context.Context, userID, chatID string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreChat", ctx, userID, chatID)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreChat indicates an expected call of RestoreChat.
+func (mr *MockChatRepositoryInterfaceMockRecorder) RestoreChat(ctx, userID, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).RestoreChat), ctx, userID, chatID)
+}
+
+// SetMembers mocks base method.
+func (m *MockChatRepositoryInterface) SetMembers(ctx context.Context, chatID string, version int64, members []models.ChatMember) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMembers", ctx, chatID, version, members)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMembers indicates an expected call of SetMembers.
+func (mr *MockChatRepositoryInterfaceMockRecorder) SetMembers(ctx, chatID, version, members any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMembers", reflect.TypeOf((*MockChatRepositoryInterface)(nil).SetMembers), ctx, chatID, version, members)
+}
+
+// SetMemoryExtractedAt mocks base method.
+func (m *MockChatRepositoryInterface) SetMemoryExtractedAt(ctx context.Context, chatID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMemoryExtractedAt", ctx, chatID, at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// SetMemoryExtractedAt indicates an expected call of SetMemoryExtractedAt.
+func (mr *MockChatRepositoryInterfaceMockRecorder) SetMemoryExtractedAt(ctx, chatID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMemoryExtractedAt", reflect.TypeOf((*MockChatRepositoryInterface)(nil).SetMemoryExtractedAt), ctx, chatID, at)
+}
+
+// SetSummary mocks base method.
+func (m *MockChatRepositoryInterface) SetSummary(ctx context.Context, chatID string, summary *models.Summary) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSummary", ctx, chatID, summary)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSummary indicates an expected call of SetSummary.
+func (mr *MockChatRepositoryInterfaceMockRecorder) SetSummary(ctx, chatID, summary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSummary", reflect.TypeOf((*MockChatRepositoryInterface)(nil).SetSummary), ctx, chatID, summary)
+}
+
+// UpdateChat mocks base method.
+func (m *MockChatRepositoryInterface) UpdateChat(ctx context.Context, chat *models.Chat) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChat", ctx, chat)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateChat indicates an expected call of UpdateChat.
 func (mr *MockChatRepositoryInterfaceMockRecorder) UpdateChat(ctx, chat any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChat", reflect.TypeOf((*MockChatRepositoryInterface)(nil).UpdateChat), ctx, chat)
 }
+
+// UpdateChats mocks base method.
+func (m *MockChatRepositoryInterface) UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) ([]*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChats", ctx, userID, chatIDs, update)
+	ret0, _ := ret[0].([]*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChats indicates an expected call of UpdateChats.
+func (mr *MockChatRepositoryInterfaceMockRecorder) UpdateChats(ctx, userID, chatIDs, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChats", reflect.TypeOf((*MockChatRepositoryInterface)(nil).UpdateChats), ctx, userID, chatIDs, update)
+}
+
+// MockFolderRepositoryInterface is a mock of FolderRepositoryInterface interface.
+type MockFolderRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockFolderRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockFolderRepositoryInterfaceMockRecorder is the mock recorder for MockFolderRepositoryInterface.
+type MockFolderRepositoryInterfaceMockRecorder struct {
+	mock *MockFolderRepositoryInterface
+}
+
+// NewMockFolderRepositoryInterface creates a new mock instance.
+func NewMockFolderRepositoryInterface(ctrl *gomock.Controller) *MockFolderRepositoryInterface {
+	mock := &MockFolderRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockFolderRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFolderRepositoryInterface) EXPECT() *MockFolderRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateFolder mocks base method.
+func (m *MockFolderRepositoryInterface) CreateFolder(ctx context.Context, folder *models.Folder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", ctx, folder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockFolderRepositoryInterfaceMockRecorder) CreateFolder(ctx, folder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockFolderRepositoryInterface)(nil).CreateFolder), ctx, folder)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockFolderRepositoryInterface) DeleteFolder(ctx context.Context, folderID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", ctx, folderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockFolderRepositoryInterfaceMockRecorder) DeleteFolder(ctx, folderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockFolderRepositoryInterface)(nil).DeleteFolder), ctx, folderID)
+}
+
+// GetFolder mocks base method.
+func (m *MockFolderRepositoryInterface) GetFolder(ctx context.Context, folderID string) (*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolder", ctx, folderID)
+	ret0, _ := ret[0].(*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFolder indicates an expected call of GetFolder.
+func (mr *MockFolderRepositoryInterfaceMockRecorder) GetFolder(ctx, folderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolder", reflect.TypeOf((*MockFolderRepositoryInterface)(nil).GetFolder), ctx, folderID)
+}
+
+// ListFolders mocks base method.
+func (m *MockFolderRepositoryInterface) ListFolders(ctx context.Context, userID string) ([]*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolders", ctx, userID)
+	ret0, _ := ret[0].([]*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolders indicates an expected call of ListFolders.
+func (mr *MockFolderRepositoryInterfaceMockRecorder) ListFolders(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolders", reflect.TypeOf((*MockFolderRepositoryInterface)(nil).ListFolders), ctx, userID)
+}
+
+// UpdateFolder mocks base method.
+func (m *MockFolderRepositoryInterface) UpdateFolder(ctx context.Context, folder *models.Folder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFolder", ctx, folder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFolder indicates an expected call of UpdateFolder.
+func (mr *MockFolderRepositoryInterfaceMockRecorder) UpdateFolder(ctx, folder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFolder", reflect.TypeOf((*MockFolderRepositoryInterface)(nil).UpdateFolder), ctx, folder)
+}
+
+// MockShareRepositoryInterface is a mock of ShareRepositoryInterface interface.
+type MockShareRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockShareRepositoryInterfaceMockRecorder is the mock recorder for MockShareRepositoryInterface.
+type MockShareRepositoryInterfaceMockRecorder struct {
+	mock *MockShareRepositoryInterface
+}
+
+// NewMockShareRepositoryInterface creates a new mock instance.
+func NewMockShareRepositoryInterface(ctrl *gomock.Controller) *MockShareRepositoryInterface {
+	mock := &MockShareRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockShareRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareRepositoryInterface) EXPECT() *MockShareRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateShare mocks base method.
+func (m *MockShareRepositoryInterface) CreateShare(ctx context.Context, share *models.Share) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShare", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateShare indicates an expected call of CreateShare.
+func (mr *MockShareRepositoryInterfaceMockRecorder) CreateShare(ctx, share any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShare", reflect.TypeOf((*MockShareRepositoryInterface)(nil).CreateShare), ctx, share)
+}
+
+// DeleteShare mocks base method.
+func (m *MockShareRepositoryInterface) DeleteShare(ctx context.Context, shareID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShare", ctx, shareID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockShareRepositoryInterfaceMockRecorder) DeleteShare(ctx, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockShareRepositoryInterface)(nil).DeleteShare), ctx, shareID)
+}
+
+// GetShare mocks base method.
+func (m *MockShareRepositoryInterface) GetShare(ctx context.Context, shareID string) (*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShare", ctx, shareID)
+	ret0, _ := ret[0].(*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShare indicates an expected call of GetShare.
+func (mr *MockShareRepositoryInterfaceMockRecorder) GetShare(ctx, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShare", reflect.TypeOf((*MockShareRepositoryInterface)(nil).GetShare), ctx, shareID)
+}
+
+// GetShareByToken mocks base method.
+func (m *MockShareRepositoryInterface) GetShareByToken(ctx context.Context, token string) (*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareByToken", ctx, token)
+	ret0, _ := ret[0].(*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareByToken indicates an expected call of GetShareByToken.
+func (mr *MockShareRepositoryInterfaceMockRecorder) GetShareByToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareByToken", reflect.TypeOf((*MockShareRepositoryInterface)(nil).GetShareByToken), ctx, token)
+}
+
+// ListShares mocks base method.
+func (m *MockShareRepositoryInterface) ListShares(ctx context.Context, chatID string) ([]*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, chatID)
+	ret0, _ := ret[0].([]*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockShareRepositoryInterfaceMockRecorder) ListShares(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockShareRepositoryInterface)(nil).ListShares), ctx, chatID)
+}
+
+// MockTemplateRepositoryInterface is a mock of TemplateRepositoryInterface interface.
+type MockTemplateRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTemplateRepositoryInterfaceMockRecorder is the mock recorder for MockTemplateRepositoryInterface.
+type MockTemplateRepositoryInterfaceMockRecorder struct {
+	mock *MockTemplateRepositoryInterface
+}
+
+// NewMockTemplateRepositoryInterface creates a new mock instance.
+func NewMockTemplateRepositoryInterface(ctrl *gomock.Controller) *MockTemplateRepositoryInterface {
+	mock := &MockTemplateRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTemplateRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateRepositoryInterface) EXPECT() *MockTemplateRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplate mocks base method.
+func (m *MockTemplateRepositoryInterface) CreateTemplate(ctx context.Context, template *models.PromptTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) CreateTemplate(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).CreateTemplate), ctx, template)
+}
+
+// CreateTemplateVersion mocks base method.
+func (m *MockTemplateRepositoryInterface) CreateTemplateVersion(ctx context.Context, version *models.PromptTemplateVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplateVersion", ctx, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplateVersion indicates an expected call of CreateTemplateVersion.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) CreateTemplateVersion(ctx, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplateVersion", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).CreateTemplateVersion), ctx, version)
+}
+
+// DeleteTemplate mocks base method.
+func (m *MockTemplateRepositoryInterface) DeleteTemplate(ctx context.Context, templateID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", ctx, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) DeleteTemplate(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).DeleteTemplate), ctx, templateID)
+}
+
+// GetTemplate mocks base method.
+func (m *MockTemplateRepositoryInterface) GetTemplate(ctx context.Context, templateID string) (*models.PromptTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, templateID)
+	ret0, _ := ret[0].(*models.PromptTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) GetTemplate(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).GetTemplate), ctx, templateID)
+}
+
+// GetTemplateVersion mocks base method.
+func (m *MockTemplateRepositoryInterface) GetTemplateVersion(ctx context.Context, templateID string, version int) (*models.PromptTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersion", ctx, templateID, version)
+	ret0, _ := ret[0].(*models.PromptTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersion indicates an expected call of GetTemplateVersion.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) GetTemplateVersion(ctx, templateID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersion", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).GetTemplateVersion), ctx, templateID, version)
+}
+
+// ListTemplateVersions mocks base method.
+func (m *MockTemplateRepositoryInterface) ListTemplateVersions(ctx context.Context, templateID string) ([]*models.PromptTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplateVersions", ctx, templateID)
+	ret0, _ := ret[0].([]*models.PromptTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplateVersions indicates an expected call of ListTemplateVersions.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) ListTemplateVersions(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplateVersions", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).ListTemplateVersions), ctx, templateID)
+}
+
+// ListTemplates mocks base method.
+func (m *MockTemplateRepositoryInterface) ListTemplates(ctx context.Context, userID string) ([]*models.PromptTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx, userID)
+	ret0, _ := ret[0].([]*models.PromptTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) ListTemplates(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).ListTemplates), ctx, userID)
+}
+
+// UpdateTemplate mocks base method.
+func (m *MockTemplateRepositoryInterface) UpdateTemplate(ctx context.Context, template *models.PromptTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplate indicates an expected call of UpdateTemplate.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) UpdateTemplate(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplate", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).UpdateTemplate), ctx, template)
+}
+
+// MockAssistantRepositoryInterface is a mock of AssistantRepositoryInterface interface.
+type MockAssistantRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssistantRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAssistantRepositoryInterfaceMockRecorder is the mock recorder for MockAssistantRepositoryInterface.
+type MockAssistantRepositoryInterfaceMockRecorder struct {
+	mock *MockAssistantRepositoryInterface
+}
+
+// NewMockAssistantRepositoryInterface creates a new mock instance.
+func NewMockAssistantRepositoryInterface(ctrl *gomock.Controller) *MockAssistantRepositoryInterface {
+	mock := &MockAssistantRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAssistantRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssistantRepositoryInterface) EXPECT() *MockAssistantRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateAssistant mocks base method.
+func (m *MockAssistantRepositoryInterface) CreateAssistant(ctx context.Context, assistant *models.Assistant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAssistant", ctx, assistant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAssistant indicates an expected call of CreateAssistant.
+func (mr *MockAssistantRepositoryInterfaceMockRecorder) CreateAssistant(ctx, assistant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAssistant", reflect.TypeOf((*MockAssistantRepositoryInterface)(nil).CreateAssistant), ctx, assistant)
+}
+
+// DeleteAssistant mocks base method.
+func (m *MockAssistantRepositoryInterface) DeleteAssistant(ctx context.Context, assistantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAssistant", ctx, assistantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAssistant indicates an expected call of DeleteAssistant.
+func (mr *MockAssistantRepositoryInterfaceMockRecorder) DeleteAssistant(ctx, assistantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAssistant", reflect.TypeOf((*MockAssistantRepositoryInterface)(nil).DeleteAssistant), ctx, assistantID)
+}
+
+// GetAssistant mocks base method.
+func (m *MockAssistantRepositoryInterface) GetAssistant(ctx context.Context, assistantID string) (*models.Assistant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssistant", ctx, assistantID)
+	ret0, _ := ret[0].(*models.Assistant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssistant indicates an expected call of GetAssistant.
+func (mr *MockAssistantRepositoryInterfaceMockRecorder) GetAssistant(ctx, assistantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssistant", reflect.TypeOf((*MockAssistantRepositoryInterface)(nil).GetAssistant), ctx, assistantID)
+}
+
+// ListAssistants mocks base method.
+func (m *MockAssistantRepositoryInterface) ListAssistants(ctx context.Context, userID string) ([]*models.Assistant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssistants", ctx, userID)
+	ret0, _ := ret[0].([]*models.Assistant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssistants indicates an expected call of ListAssistants.
+func (mr *MockAssistantRepositoryInterfaceMockRecorder) ListAssistants(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssistants", reflect.TypeOf((*MockAssistantRepositoryInterface)(nil).ListAssistants), ctx, userID)
+}
+
+// UpdateAssistant mocks base method.
+func (m *MockAssistantRepositoryInterface) UpdateAssistant(ctx context.Context, assistant *models.Assistant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAssistant", ctx, assistant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAssistant indicates an expected call of UpdateAssistant.
+func (mr *MockAssistantRepositoryInterfaceMockRecorder) UpdateAssistant(ctx, assistant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAssistant", reflect.TypeOf((*MockAssistantRepositoryInterface)(nil).UpdateAssistant), ctx, assistant)
+}
+
+// MockMemoryRepositoryInterface is a mock of MemoryRepositoryInterface interface.
+type MockMemoryRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMemoryRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockMemoryRepositoryInterfaceMockRecorder is the mock recorder for MockMemoryRepositoryInterface.
+type MockMemoryRepositoryInterfaceMockRecorder struct {
+	mock *MockMemoryRepositoryInterface
+}
+
+// NewMockMemoryRepositoryInterface creates a new mock instance.
+func NewMockMemoryRepositoryInterface(ctrl *gomock.Controller) *MockMemoryRepositoryInterface {
+	mock := &MockMemoryRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockMemoryRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMemoryRepositoryInterface) EXPECT() *MockMemoryRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateMemory mocks base method.
+func (m *MockMemoryRepositoryInterface) CreateMemory(ctx context.Context, memory *models.Memory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMemory", ctx, memory)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMemory indicates an expected call of CreateMemory.
+func (mr *MockMemoryRepositoryInterfaceMockRecorder) CreateMemory(ctx, memory any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMemory", reflect.TypeOf((*MockMemoryRepositoryInterface)(nil).CreateMemory), ctx, memory)
+}
+
+// DeleteMemory mocks base method.
+func (m *MockMemoryRepositoryInterface) DeleteMemory(ctx context.Context, userID, memoryID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMemory", ctx, userID, memoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMemory indicates an expected call of DeleteMemory.
+func (mr *MockMemoryRepositoryInterfaceMockRecorder) DeleteMemory(ctx, userID, memoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMemory", reflect.TypeOf((*MockMemoryRepositoryInterface)(nil).DeleteMemory), ctx, userID, memoryID)
+}
+
+// GetMemory mocks base method.
+func (m *MockMemoryRepositoryInterface) GetMemory(ctx context.Context, userID, memoryID string) (*models.Memory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemory", ctx, userID, memoryID)
+	ret0, _ := ret[0].(*models.Memory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemory indicates an expected call of GetMemory.
+func (mr *MockMemoryRepositoryInterfaceMockRecorder) GetMemory(ctx, userID, memoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemory", reflect.TypeOf((*MockMemoryRepositoryInterface)(nil).GetMemory), ctx, userID, memoryID)
+}
+
+// ListMemories mocks base method.
+func (m *MockMemoryRepositoryInterface) ListMemories(ctx context.Context, userID string, enabledOnly bool) ([]*models.Memory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemories", ctx, userID, enabledOnly)
+	ret0, _ := ret[0].([]*models.Memory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemories indicates an expected call of ListMemories.
+func (mr *MockMemoryRepositoryInterfaceMockRecorder) ListMemories(ctx, userID, enabledOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemories", reflect.TypeOf((*MockMemoryRepositoryInterface)(nil).ListMemories), ctx, userID, enabledOnly)
+}
+
+// UpdateMemory mocks base method.
+func (m *MockMemoryRepositoryInterface) UpdateMemory(ctx context.Context, memory *models.Memory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemory", ctx, memory)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemory indicates an expected call of UpdateMemory.
+func (mr *MockMemoryRepositoryInterfaceMockRecorder) UpdateMemory(ctx, memory any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemory", reflect.TypeOf((*MockMemoryRepositoryInterface)(nil).UpdateMemory), ctx, memory)
+}
+
+// MockAttachmentRepositoryInterface is a mock of AttachmentRepositoryInterface interface.
+type MockAttachmentRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAttachmentRepositoryInterfaceMockRecorder is the mock recorder for MockAttachmentRepositoryInterface.
+type MockAttachmentRepositoryInterfaceMockRecorder struct {
+	mock *MockAttachmentRepositoryInterface
+}
+
+// NewMockAttachmentRepositoryInterface creates a new mock instance.
+func NewMockAttachmentRepositoryInterface(ctrl *gomock.Controller) *MockAttachmentRepositoryInterface {
+	mock := &MockAttachmentRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAttachmentRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentRepositoryInterface) EXPECT() *MockAttachmentRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetAttachment mocks base method.
+func (m *MockAttachmentRepositoryInterface) GetAttachment(ctx context.Context, attachmentID string) (*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, attachmentID)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockAttachmentRepositoryInterfaceMockRecorder) GetAttachment(ctx, attachmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockAttachmentRepositoryInterface)(nil).GetAttachment), ctx, attachmentID)
+}
+
+// ListAttachments mocks base method.
+func (m *MockAttachmentRepositoryInterface) ListAttachments(ctx context.Context, chatID string) ([]*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttachments", ctx, chatID)
+	ret0, _ := ret[0].([]*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttachments indicates an expected call of ListAttachments.
+func (mr *MockAttachmentRepositoryInterfaceMockRecorder) ListAttachments(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachments", reflect.TypeOf((*MockAttachmentRepositoryInterface)(nil).ListAttachments), ctx, chatID)
+}
+
+// ReadAttachment mocks base method.
+func (m *MockAttachmentRepositoryInterface) ReadAttachment(ctx context.Context, attachmentID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAttachment", ctx, attachmentID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAttachment indicates an expected call of ReadAttachment.
+func (mr *MockAttachmentRepositoryInterfaceMockRecorder) ReadAttachment(ctx, attachmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAttachment", reflect.TypeOf((*MockAttachmentRepositoryInterface)(nil).ReadAttachment), ctx, attachmentID)
+}
+
+// SaveAttachment mocks base method.
+func (m *MockAttachmentRepositoryInterface) SaveAttachment(ctx context.Context, attachment *models.Attachment, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttachment", ctx, attachment, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttachment indicates an expected call of SaveAttachment.
+func (mr *MockAttachmentRepositoryInterfaceMockRecorder) SaveAttachment(ctx, attachment, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttachment", reflect.TypeOf((*MockAttachmentRepositoryInterface)(nil).SaveAttachment), ctx, attachment, data)
+}
+
+// MockToolRunRepositoryInterface is a mock of ToolRunRepositoryInterface interface.
+type MockToolRunRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockToolRunRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockToolRunRepositoryInterfaceMockRecorder is the mock recorder for MockToolRunRepositoryInterface.
+type MockToolRunRepositoryInterfaceMockRecorder struct {
+	mock *MockToolRunRepositoryInterface
+}
+
+// NewMockToolRunRepositoryInterface creates a new mock instance.
+func NewMockToolRunRepositoryInterface(ctrl *gomock.Controller) *MockToolRunRepositoryInterface {
+	mock := &MockToolRunRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockToolRunRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockToolRunRepositoryInterface) EXPECT() *MockToolRunRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateToolRun mocks base method.
+func (m *MockToolRunRepositoryInterface) CreateToolRun(ctx context.Context, run *models.ToolRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToolRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateToolRun indicates an expected call of CreateToolRun.
+func (mr *MockToolRunRepositoryInterfaceMockRecorder) CreateToolRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToolRun", reflect.TypeOf((*MockToolRunRepositoryInterface)(nil).CreateToolRun), ctx, run)
+}
+
+// ListToolRuns mocks base method.
+func (m *MockToolRunRepositoryInterface) ListToolRuns(ctx context.Context, userID, chatID string, limit int) ([]*models.ToolRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolRuns", ctx, userID, chatID, limit)
+	ret0, _ := ret[0].([]*models.ToolRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolRuns indicates an expected call of ListToolRuns.
+func (mr *MockToolRunRepositoryInterfaceMockRecorder) ListToolRuns(ctx, userID, chatID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolRuns", reflect.TypeOf((*MockToolRunRepositoryInterface)(nil).ListToolRuns), ctx, userID, chatID, limit)
+}
+
+// MockKnowledgeRepositoryInterface is a mock of KnowledgeRepositoryInterface interface.
+type MockKnowledgeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKnowledgeRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockKnowledgeRepositoryInterfaceMockRecorder is the mock recorder for MockKnowledgeRepositoryInterface.
+type MockKnowledgeRepositoryInterfaceMockRecorder struct {
+	mock *MockKnowledgeRepositoryInterface
+}
+
+// NewMockKnowledgeRepositoryInterface creates a new mock instance.
+func NewMockKnowledgeRepositoryInterface(ctrl *gomock.Controller) *MockKnowledgeRepositoryInterface {
+	mock := &MockKnowledgeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockKnowledgeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKnowledgeRepositoryInterface) EXPECT() *MockKnowledgeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateDocument mocks base method.
+func (m *MockKnowledgeRepositoryInterface) CreateDocument(ctx context.Context, document *models.KnowledgeDocument) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDocument", ctx, document)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDocument indicates an expected call of CreateDocument.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) CreateDocument(ctx, document any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDocument", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).CreateDocument), ctx, document)
+}
+
+// CreateKnowledgeBase mocks base method.
+func (m *MockKnowledgeRepositoryInterface) CreateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKnowledgeBase", ctx, knowledgeBase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateKnowledgeBase indicates an expected call of CreateKnowledgeBase.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) CreateKnowledgeBase(ctx, knowledgeBase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKnowledgeBase", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).CreateKnowledgeBase), ctx, knowledgeBase)
+}
+
+// DeleteDocument mocks base method.
+func (m *MockKnowledgeRepositoryInterface) DeleteDocument(ctx context.Context, userID, documentID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDocument", ctx, userID, documentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDocument indicates an expected call of DeleteDocument.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) DeleteDocument(ctx, userID, documentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDocument", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).DeleteDocument), ctx, userID, documentID)
+}
+
+// DeleteKnowledgeBase mocks base method.
+func (m *MockKnowledgeRepositoryInterface) DeleteKnowledgeBase(ctx context.Context, userID, knowledgeBaseID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKnowledgeBase", ctx, userID, knowledgeBaseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKnowledgeBase indicates an expected call of DeleteKnowledgeBase.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) DeleteKnowledgeBase(ctx, userID, knowledgeBaseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKnowledgeBase", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).DeleteKnowledgeBase), ctx, userID, knowledgeBaseID)
+}
+
+// ForEachChunk mocks base method.
+func (m *MockKnowledgeRepositoryInterface) ForEachChunk(ctx context.Context, knowledgeBaseIDs []string, fn func(*models.KnowledgeChunk) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachChunk", ctx, knowledgeBaseIDs, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachChunk indicates an expected call of ForEachChunk.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) ForEachChunk(ctx, knowledgeBaseIDs, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachChunk", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).ForEachChunk), ctx, knowledgeBaseIDs, fn)
+}
+
+// GetDocument mocks base method.
+func (m *MockKnowledgeRepositoryInterface) GetDocument(ctx context.Context, userID, documentID string) (*models.KnowledgeDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocument", ctx, userID, documentID)
+	ret0, _ := ret[0].(*models.KnowledgeDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDocument indicates an expected call of GetDocument.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) GetDocument(ctx, userID, documentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocument", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).GetDocument), ctx, userID, documentID)
+}
+
+// GetKnowledgeBase mocks base method.
+func (m *MockKnowledgeRepositoryInterface) GetKnowledgeBase(ctx context.Context, userID, knowledgeBaseID string) (*models.KnowledgeBase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKnowledgeBase", ctx, userID, knowledgeBaseID)
+	ret0, _ := ret[0].(*models.KnowledgeBase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKnowledgeBase indicates an expected call of GetKnowledgeBase.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) GetKnowledgeBase(ctx, userID, knowledgeBaseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKnowledgeBase", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).GetKnowledgeBase), ctx, userID, knowledgeBaseID)
+}
+
+// ListDocuments mocks base method.
+func (m *MockKnowledgeRepositoryInterface) ListDocuments(ctx context.Context, userID, knowledgeBaseID string) ([]*models.KnowledgeDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDocuments", ctx, userID, knowledgeBaseID)
+	ret0, _ := ret[0].([]*models.KnowledgeDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDocuments indicates an expected call of ListDocuments.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) ListDocuments(ctx, userID, knowledgeBaseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDocuments", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).ListDocuments), ctx, userID, knowledgeBaseID)
+}
+
+// ListKnowledgeBases mocks base method.
+func (m *MockKnowledgeRepositoryInterface) ListKnowledgeBases(ctx context.Context, userID string) ([]*models.KnowledgeBase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKnowledgeBases", ctx, userID)
+	ret0, _ := ret[0].([]*models.KnowledgeBase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKnowledgeBases indicates an expected call of ListKnowledgeBases.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) ListKnowledgeBases(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKnowledgeBases", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).ListKnowledgeBases), ctx, userID)
+}
+
+// ReplaceChunks mocks base method.
+func (m *MockKnowledgeRepositoryInterface) ReplaceChunks(ctx context.Context, documentID string, chunks []*models.KnowledgeChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceChunks", ctx, documentID, chunks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceChunks indicates an expected call of ReplaceChunks.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) ReplaceChunks(ctx, documentID, chunks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceChunks", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).ReplaceChunks), ctx, documentID, chunks)
+}
+
+// StartIndexing mocks base method.
+func (m *MockKnowledgeRepositoryInterface) StartIndexing(ctx context.Context, userID, documentID string, startedAt, staleBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartIndexing", ctx, userID, documentID, startedAt, staleBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartIndexing indicates an expected call of StartIndexing.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) StartIndexing(ctx, userID, documentID, startedAt, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartIndexing", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).StartIndexing), ctx, userID, documentID, startedAt, staleBefore)
+}
+
+// UpdateDocument mocks base method.
+func (m *MockKnowledgeRepositoryInterface) UpdateDocument(ctx context.Context, document *models.KnowledgeDocument) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDocument", ctx, document)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDocument indicates an expected call of UpdateDocument.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) UpdateDocument(ctx, document any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDocument", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).UpdateDocument), ctx, document)
+}
+
+// UpdateKnowledgeBase mocks base method.
+func (m *MockKnowledgeRepositoryInterface) UpdateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKnowledgeBase", ctx, knowledgeBase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKnowledgeBase indicates an expected call of UpdateKnowledgeBase.
+func (mr *MockKnowledgeRepositoryInterfaceMockRecorder) UpdateKnowledgeBase(ctx, knowledgeBase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKnowledgeBase", reflect.TypeOf((*MockKnowledgeRepositoryInterface)(nil).UpdateKnowledgeBase), ctx, knowledgeBase)
+}
+
+// MockPromptCacheRepositoryInterface is a mock of PromptCacheRepositoryInterface interface.
+type MockPromptCacheRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromptCacheRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockPromptCacheRepositoryInterfaceMockRecorder is the mock recorder for MockPromptCacheRepositoryInterface.
+type MockPromptCacheRepositoryInterfaceMockRecorder struct {
+	mock *MockPromptCacheRepositoryInterface
+}
+
+// NewMockPromptCacheRepositoryInterface creates a new mock instance.
+func NewMockPromptCacheRepositoryInterface(ctrl *gomock.Controller) *MockPromptCacheRepositoryInterface {
+	mock := &MockPromptCacheRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPromptCacheRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromptCacheRepositoryInterface) EXPECT() *MockPromptCacheRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreatePromptCache mocks base method.
+func (m *MockPromptCacheRepositoryInterface) CreatePromptCache(ctx context.Context, cache *models.PromptCache) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromptCache", ctx, cache)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePromptCache indicates an expected call of CreatePromptCache.
+func (mr *MockPromptCacheRepositoryInterfaceMockRecorder) CreatePromptCache(ctx, cache any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromptCache", reflect.TypeOf((*MockPromptCacheRepositoryInterface)(nil).CreatePromptCache), ctx, cache)
+}
+
+// DeletePromptCache mocks base method.
+func (m *MockPromptCacheRepositoryInterface) DeletePromptCache(ctx context.Context, cacheID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePromptCache", ctx, cacheID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePromptCache indicates an expected call of DeletePromptCache.
+func (mr *MockPromptCacheRepositoryInterfaceMockRecorder) DeletePromptCache(ctx, cacheID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePromptCache", reflect.TypeOf((*MockPromptCacheRepositoryInterface)(nil).DeletePromptCache), ctx, cacheID)
+}
+
+// GetPromptCache mocks base method.
+func (m *MockPromptCacheRepositoryInterface) GetPromptCache(ctx context.Context, scope, platform, model string) (*models.PromptCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromptCache", ctx, scope, platform, model)
+	ret0, _ := ret[0].(*models.PromptCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromptCache indicates an expected call of GetPromptCache.
+func (mr *MockPromptCacheRepositoryInterfaceMockRecorder) GetPromptCache(ctx, scope, platform, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromptCache", reflect.TypeOf((*MockPromptCacheRepositoryInterface)(nil).GetPromptCache), ctx, scope, platform, model)
+}
+
+// ListExpiredPromptCaches mocks base method.
+func (m *MockPromptCacheRepositoryInterface) ListExpiredPromptCaches(ctx context.Context, before time.Time) ([]*models.PromptCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredPromptCaches", ctx, before)
+	ret0, _ := ret[0].([]*models.PromptCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredPromptCaches indicates an expected call of ListExpiredPromptCaches.
+func (mr *MockPromptCacheRepositoryInterfaceMockRecorder) ListExpiredPromptCaches(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredPromptCaches", reflect.TypeOf((*MockPromptCacheRepositoryInterface)(nil).ListExpiredPromptCaches), ctx, before)
+}
+
+// UpdatePromptCache mocks base method.
+func (m *MockPromptCacheRepositoryInterface) UpdatePromptCache(ctx context.Context, cache *models.PromptCache) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromptCache", ctx, cache)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePromptCache indicates an expected call of UpdatePromptCache.
+func (mr *MockPromptCacheRepositoryInterfaceMockRecorder) UpdatePromptCache(ctx, cache any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromptCache", reflect.TypeOf((*MockPromptCacheRepositoryInterface)(nil).UpdatePromptCache), ctx, cache)
+}
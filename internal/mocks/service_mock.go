@@ -11,7 +11,9 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/lutefd/ai-router-go/internal/models"
 	service "github.com/lutefd/ai-router-go/internal/service"
@@ -42,6 +44,34 @@ func (m *MockAIServiceInterface) EXPECT() *MockAIServiceInterfaceMockRecorder {
 	return m.recorder
 }
 
+// GenerateChatResponse mocks base method.
+func (m *MockAIServiceInterface) GenerateChatResponse(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateChatResponse", ctx, req, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateChatResponse indicates an expected call of GenerateChatResponse.
+func (mr *MockAIServiceInterfaceMockRecorder) GenerateChatResponse(ctx, req, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChatResponse", reflect.TypeOf((*MockAIServiceInterface)(nil).GenerateChatResponse), ctx, req, callback)
+}
+
+// GenerateDeepSeekChatResponse mocks base method.
+func (m *MockAIServiceInterface) GenerateDeepSeekChatResponse(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateDeepSeekChatResponse", ctx, req, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateDeepSeekChatResponse indicates an expected call of GenerateDeepSeekChatResponse.
+func (mr *MockAIServiceInterfaceMockRecorder) GenerateDeepSeekChatResponse(ctx, req, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDeepSeekChatResponse", reflect.TypeOf((*MockAIServiceInterface)(nil).GenerateDeepSeekChatResponse), ctx, req, callback)
+}
+
 // GenerateDeepSeekResponse mocks base method.
 func (m *MockAIServiceInterface) GenerateDeepSeekResponse(ctx context.Context, model, prompt string, callback func(string)) error {
 	m.ctrl.T.Helper()
@@ -56,6 +86,20 @@ func (mr *MockAIServiceInterfaceMockRecorder) GenerateDeepSeekResponse(ctx, mode
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDeepSeekResponse", reflect.TypeOf((*MockAIServiceInterface)(nil).GenerateDeepSeekResponse), ctx, model, prompt, callback)
 }
 
+// GenerateOpenAIChatResponse mocks base method.
+func (m *MockAIServiceInterface) GenerateOpenAIChatResponse(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateOpenAIChatResponse", ctx, req, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateOpenAIChatResponse indicates an expected call of GenerateOpenAIChatResponse.
+func (mr *MockAIServiceInterfaceMockRecorder) GenerateOpenAIChatResponse(ctx, req, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateOpenAIChatResponse", reflect.TypeOf((*MockAIServiceInterface)(nil).GenerateOpenAIChatResponse), ctx, req, callback)
+}
+
 // GenerateOpenAIResponse mocks base method.
 func (m *MockAIServiceInterface) GenerateOpenAIResponse(ctx context.Context, model, prompt string, callback func(string)) error {
 	m.ctrl.T.Helper()
@@ -84,6 +128,58 @@ func (mr *MockAIServiceInterfaceMockRecorder) GenerateResponse(ctx, model, promp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateResponse", reflect.TypeOf((*MockAIServiceInterface)(nil).GenerateResponse), ctx, model, prompt, callback)
 }
 
+// MockGeneratorInterface is a mock of GeneratorInterface interface.
+type MockGeneratorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeneratorInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockGeneratorInterfaceMockRecorder is the mock recorder for MockGeneratorInterface.
+type MockGeneratorInterfaceMockRecorder struct {
+	mock *MockGeneratorInterface
+}
+
+// NewMockGeneratorInterface creates a new mock instance.
+func NewMockGeneratorInterface(ctrl *gomock.Controller) *MockGeneratorInterface {
+	mock := &MockGeneratorInterface{ctrl: ctrl}
+	mock.recorder = &MockGeneratorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeneratorInterface) EXPECT() *MockGeneratorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GenerateChatResponse mocks base method.
+func (m *MockGeneratorInterface) GenerateChatResponse(ctx context.Context, platform string, req *models.GenerationRequest, callback func(string)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateChatResponse", ctx, platform, req, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateChatResponse indicates an expected call of GenerateChatResponse.
+func (mr *MockGeneratorInterfaceMockRecorder) GenerateChatResponse(ctx, platform, req, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChatResponse", reflect.TypeOf((*MockGeneratorInterface)(nil).GenerateChatResponse), ctx, platform, req, callback)
+}
+
+// GenerateResponse mocks base method.
+func (m *MockGeneratorInterface) GenerateResponse(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateResponse", ctx, platform, model, prompt, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateResponse indicates an expected call of GenerateResponse.
+func (mr *MockGeneratorInterfaceMockRecorder) GenerateResponse(ctx, platform, model, prompt, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateResponse", reflect.TypeOf((*MockGeneratorInterface)(nil).GenerateResponse), ctx, platform, model, prompt, callback)
+}
+
 // MockAuthServiceInterface is a mock of AuthServiceInterface interface.
 type MockAuthServiceInterface struct {
 	ctrl     *gomock.Controller
@@ -208,6 +304,26 @@ func (m *MockChatServiceInterface) EXPECT() *MockChatServiceInterfaceMockRecorde
 	return m.recorder
 }
 
+// AddMessages mocks base method.
+func (m *MockChatServiceInterface) AddMessages(ctx context.Context, id string, messages ...models.Message) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range messages {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddMessages", varargs...)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMessages indicates an expected call of AddMessages.
+func (mr *MockChatServiceInterfaceMockRecorder) AddMessages(ctx, id any, messages ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, messages...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMessages", reflect.TypeOf((*MockChatServiceInterface)(nil).AddMessages), varargs...)
+}
+
 // CreateChat mocks base method.
 func (m *MockChatServiceInterface) CreateChat(ctx context.Context, chat *models.Chat) error {
 	m.ctrl.T.Helper()
@@ -223,17 +339,47 @@ func (mr *MockChatServiceInterfaceMockRecorder) CreateChat(ctx, chat any) *gomoc
 }
 
 // DeleteChat mocks base method.
-func (m *MockChatServiceInterface) DeleteChat(ctx context.Context, id string) error {
+func (m *MockChatServiceInterface) DeleteChat(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteChat", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteChat", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteChat indicates an expected call of DeleteChat.
-func (mr *MockChatServiceInterfaceMockRecorder) DeleteChat(ctx, id any) *gomock.Call {
+func (mr *MockChatServiceInterfaceMockRecorder) DeleteChat(ctx, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChat", reflect.TypeOf((*MockChatServiceInterface)(nil).DeleteChat), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChat", reflect.TypeOf((*MockChatServiceInterface)(nil).DeleteChat), ctx, id, version)
+}
+
+// DeleteChats mocks base method.
+func (m *MockChatServiceInterface) DeleteChats(ctx context.Context, userID string, chatIDs []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChats", ctx, userID, chatIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChats indicates an expected call of DeleteChats.
+func (mr *MockChatServiceInterfaceMockRecorder) DeleteChats(ctx, userID, chatIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChats", reflect.TypeOf((*MockChatServiceInterface)(nil).DeleteChats), ctx, userID, chatIDs)
+}
+
+// ForkChat mocks base method.
+func (m *MockChatServiceInterface) ForkChat(ctx context.Context, userID, id, messageID string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForkChat", ctx, userID, id, messageID)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForkChat indicates an expected call of ForkChat.
+func (mr *MockChatServiceInterfaceMockRecorder) ForkChat(ctx, userID, id, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForkChat", reflect.TypeOf((*MockChatServiceInterface)(nil).ForkChat), ctx, userID, id, messageID)
 }
 
 // GetChat mocks base method.
@@ -251,6 +397,78 @@ func (mr *MockChatServiceInterfaceMockRecorder) GetChat(ctx, id any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChat", reflect.TypeOf((*MockChatServiceInterface)(nil).GetChat), ctx, id)
 }
 
+// ListTrash mocks base method.
+func (m *MockChatServiceInterface) ListTrash(ctx context.Context, userID string) ([]*models.UserChat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserChat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockChatServiceInterfaceMockRecorder) ListTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockChatServiceInterface)(nil).ListTrash), ctx, userID)
+}
+
+// PurgeChat mocks base method.
+func (m *MockChatServiceInterface) PurgeChat(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeChat", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeChat indicates an expected call of PurgeChat.
+func (mr *MockChatServiceInterfaceMockRecorder) PurgeChat(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeChat", reflect.TypeOf((*MockChatServiceInterface)(nil).PurgeChat), ctx, userID, id)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockChatServiceInterface) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockChatServiceInterfaceMockRecorder) PurgeTrash(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockChatServiceInterface)(nil).PurgeTrash), ctx, retention)
+}
+
+// ResetSummary mocks base method.
+func (m *MockChatServiceInterface) ResetSummary(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetSummary", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetSummary indicates an expected call of ResetSummary.
+func (mr *MockChatServiceInterfaceMockRecorder) ResetSummary(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSummary", reflect.TypeOf((*MockChatServiceInterface)(nil).ResetSummary), ctx, id)
+}
+
+// RestoreChat mocks base method.
+func (m *MockChatServiceInterface) RestoreChat(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreChat", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreChat indicates an expected call of RestoreChat.
+func (mr *MockChatServiceInterfaceMockRecorder) RestoreChat(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreChat", reflect.TypeOf((*MockChatServiceInterface)(nil).RestoreChat), ctx, userID, id)
+}
+
 // UpdateChat mocks base method.
 func (m *MockChatServiceInterface) UpdateChat(ctx context.Context, chat *models.Chat) error {
 	m.ctrl.T.Helper()
@@ -265,41 +483,1642 @@ func (mr *MockChatServiceInterfaceMockRecorder) UpdateChat(ctx, chat any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChat", reflect.TypeOf((*MockChatServiceInterface)(nil).UpdateChat), ctx, chat)
 }
 
-// MockUserServiceInterface is a mock of UserServiceInterface interface.
-type MockUserServiceInterface struct {
+// UpdateChats mocks base method.
+func (m *MockChatServiceInterface) UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChats", ctx, userID, chatIDs, update)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChats indicates an expected call of UpdateChats.
+func (mr *MockChatServiceInterfaceMockRecorder) UpdateChats(ctx, userID, chatIDs, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChats", reflect.TypeOf((*MockChatServiceInterface)(nil).UpdateChats), ctx, userID, chatIDs, update)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockChatServiceInterface) UpdateSettings(ctx context.Context, id string, version int64, settings models.ChatSettings) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx, id, version, settings)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockChatServiceInterfaceMockRecorder) UpdateSettings(ctx, id, version, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockChatServiceInterface)(nil).UpdateSettings), ctx, id, version, settings)
+}
+
+// MockFolderServiceInterface is a mock of FolderServiceInterface interface.
+type MockFolderServiceInterface struct {
 	ctrl     *gomock.Controller
-	recorder *MockUserServiceInterfaceMockRecorder
+	recorder *MockFolderServiceInterfaceMockRecorder
 	isgomock struct{}
 }
 
-// MockUserServiceInterfaceMockRecorder is the mock recorder for MockUserServiceInterface.
-type MockUserServiceInterfaceMockRecorder struct {
-	mock *MockUserServiceInterface
+// MockFolderServiceInterfaceMockRecorder is the mock recorder for MockFolderServiceInterface.
+type MockFolderServiceInterfaceMockRecorder struct {
+	mock *MockFolderServiceInterface
 }
 
-// NewMockUserServiceInterface creates a new mock instance.
-func NewMockUserServiceInterface(ctrl *gomock.Controller) *MockUserServiceInterface {
-	mock := &MockUserServiceInterface{ctrl: ctrl}
-	mock.recorder = &MockUserServiceInterfaceMockRecorder{mock}
+// NewMockFolderServiceInterface creates a new mock instance.
+func NewMockFolderServiceInterface(ctrl *gomock.Controller) *MockFolderServiceInterface {
+	mock := &MockFolderServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockFolderServiceInterfaceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUserServiceInterface) EXPECT() *MockUserServiceInterfaceMockRecorder {
+func (m *MockFolderServiceInterface) EXPECT() *MockFolderServiceInterfaceMockRecorder {
 	return m.recorder
 }
 
-// GetUsersChatList mocks base method.
-func (m *MockUserServiceInterface) GetUsersChatList(ctx context.Context, userID string) ([]*models.UserChat, error) {
+// CreateFolder mocks base method.
+func (m *MockFolderServiceInterface) CreateFolder(ctx context.Context, folder *models.Folder) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsersChatList", ctx, userID)
-	ret0, _ := ret[0].([]*models.UserChat)
+	ret := m.ctrl.Call(m, "CreateFolder", ctx, folder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockFolderServiceInterfaceMockRecorder) CreateFolder(ctx, folder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockFolderServiceInterface)(nil).CreateFolder), ctx, folder)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockFolderServiceInterface) DeleteFolder(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockFolderServiceInterfaceMockRecorder) DeleteFolder(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockFolderServiceInterface)(nil).DeleteFolder), ctx, userID, id)
+}
+
+// GetFolder mocks base method.
+func (m *MockFolderServiceInterface) GetFolder(ctx context.Context, id string) (*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolder", ctx, id)
+	ret0, _ := ret[0].(*models.Folder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUsersChatList indicates an expected call of GetUsersChatList.
-func (mr *MockUserServiceInterfaceMockRecorder) GetUsersChatList(ctx, userID any) *gomock.Call {
+// GetFolder indicates an expected call of GetFolder.
+func (mr *MockFolderServiceInterfaceMockRecorder) GetFolder(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolder", reflect.TypeOf((*MockFolderServiceInterface)(nil).GetFolder), ctx, id)
+}
+
+// ListFolders mocks base method.
+func (m *MockFolderServiceInterface) ListFolders(ctx context.Context, userID string) ([]*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolders", ctx, userID)
+	ret0, _ := ret[0].([]*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolders indicates an expected call of ListFolders.
+func (mr *MockFolderServiceInterfaceMockRecorder) ListFolders(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolders", reflect.TypeOf((*MockFolderServiceInterface)(nil).ListFolders), ctx, userID)
+}
+
+// MoveChats mocks base method.
+func (m *MockFolderServiceInterface) MoveChats(ctx context.Context, userID, folderID string, chatIDs []string, version int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveChats", ctx, userID, folderID, chatIDs, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveChats indicates an expected call of MoveChats.
+func (mr *MockFolderServiceInterfaceMockRecorder) MoveChats(ctx, userID, folderID, chatIDs, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveChats", reflect.TypeOf((*MockFolderServiceInterface)(nil).MoveChats), ctx, userID, folderID, chatIDs, version)
+}
+
+// RenameFolder mocks base method.
+func (m *MockFolderServiceInterface) RenameFolder(ctx context.Context, id, name string) (*models.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameFolder", ctx, id, name)
+	ret0, _ := ret[0].(*models.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameFolder indicates an expected call of RenameFolder.
+func (mr *MockFolderServiceInterfaceMockRecorder) RenameFolder(ctx, id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFolder", reflect.TypeOf((*MockFolderServiceInterface)(nil).RenameFolder), ctx, id, name)
+}
+
+// MockTitleServiceInterface is a mock of TitleServiceInterface interface.
+type MockTitleServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTitleServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTitleServiceInterfaceMockRecorder is the mock recorder for MockTitleServiceInterface.
+type MockTitleServiceInterfaceMockRecorder struct {
+	mock *MockTitleServiceInterface
+}
+
+// NewMockTitleServiceInterface creates a new mock instance.
+func NewMockTitleServiceInterface(ctrl *gomock.Controller) *MockTitleServiceInterface {
+	mock := &MockTitleServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockTitleServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTitleServiceInterface) EXPECT() *MockTitleServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GenerateTitle mocks base method.
+func (m *MockTitleServiceInterface) GenerateTitle(ctx context.Context, chat *models.Chat) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateTitle", ctx, chat)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateTitle indicates an expected call of GenerateTitle.
+func (mr *MockTitleServiceInterfaceMockRecorder) GenerateTitle(ctx, chat any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTitle", reflect.TypeOf((*MockTitleServiceInterface)(nil).GenerateTitle), ctx, chat)
+}
+
+// MockSummaryServiceInterface is a mock of SummaryServiceInterface interface.
+type MockSummaryServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSummaryServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockSummaryServiceInterfaceMockRecorder is the mock recorder for MockSummaryServiceInterface.
+type MockSummaryServiceInterfaceMockRecorder struct {
+	mock *MockSummaryServiceInterface
+}
+
+// NewMockSummaryServiceInterface creates a new mock instance.
+func NewMockSummaryServiceInterface(ctrl *gomock.Controller) *MockSummaryServiceInterface {
+	mock := &MockSummaryServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockSummaryServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSummaryServiceInterface) EXPECT() *MockSummaryServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// UpdateSummary mocks base method.
+func (m *MockSummaryServiceInterface) UpdateSummary(ctx context.Context, chat *models.Chat) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSummary", ctx, chat)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSummary indicates an expected call of UpdateSummary.
+func (mr *MockSummaryServiceInterfaceMockRecorder) UpdateSummary(ctx, chat any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSummary", reflect.TypeOf((*MockSummaryServiceInterface)(nil).UpdateSummary), ctx, chat)
+}
+
+// MockContextBuilderInterface is a mock of ContextBuilderInterface interface.
+type MockContextBuilderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextBuilderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockContextBuilderInterfaceMockRecorder is the mock recorder for MockContextBuilderInterface.
+type MockContextBuilderInterfaceMockRecorder struct {
+	mock *MockContextBuilderInterface
+}
+
+// NewMockContextBuilderInterface creates a new mock instance.
+func NewMockContextBuilderInterface(ctrl *gomock.Controller) *MockContextBuilderInterface {
+	mock := &MockContextBuilderInterface{ctrl: ctrl}
+	mock.recorder = &MockContextBuilderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextBuilderInterface) EXPECT() *MockContextBuilderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// BuildContext mocks base method.
+func (m *MockContextBuilderInterface) BuildContext(ctx context.Context, platform, model string, messages []models.Message) ([]models.Message, *service.ContextReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildContext", ctx, platform, model, messages)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(*service.ContextReport)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BuildContext indicates an expected call of BuildContext.
+func (mr *MockContextBuilderInterfaceMockRecorder) BuildContext(ctx, platform, model, messages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildContext", reflect.TypeOf((*MockContextBuilderInterface)(nil).BuildContext), ctx, platform, model, messages)
+}
+
+// MockExportServiceInterface is a mock of ExportServiceInterface interface.
+type MockExportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockExportServiceInterfaceMockRecorder is the mock recorder for MockExportServiceInterface.
+type MockExportServiceInterfaceMockRecorder struct {
+	mock *MockExportServiceInterface
+}
+
+// NewMockExportServiceInterface creates a new mock instance.
+func NewMockExportServiceInterface(ctrl *gomock.Controller) *MockExportServiceInterface {
+	mock := &MockExportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockExportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportServiceInterface) EXPECT() *MockExportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ExportChat mocks base method.
+func (m *MockExportServiceInterface) ExportChat(ctx context.Context, w io.Writer, chat *models.Chat, format string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportChat", ctx, w, chat, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportChat indicates an expected call of ExportChat.
+func (mr *MockExportServiceInterfaceMockRecorder) ExportChat(ctx, w, chat, format any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportChat", reflect.TypeOf((*MockExportServiceInterface)(nil).ExportChat), ctx, w, chat, format)
+}
+
+// ExportUserChats mocks base method.
+func (m *MockExportServiceInterface) ExportUserChats(ctx context.Context, w io.Writer, userID, format string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUserChats", ctx, w, userID, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportUserChats indicates an expected call of ExportUserChats.
+func (mr *MockExportServiceInterfaceMockRecorder) ExportUserChats(ctx, w, userID, format any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUserChats", reflect.TypeOf((*MockExportServiceInterface)(nil).ExportUserChats), ctx, w, userID, format)
+}
+
+// MockImportServiceInterface is a mock of ImportServiceInterface interface.
+type MockImportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockImportServiceInterfaceMockRecorder is the mock recorder for MockImportServiceInterface.
+type MockImportServiceInterfaceMockRecorder struct {
+	mock *MockImportServiceInterface
+}
+
+// NewMockImportServiceInterface creates a new mock instance.
+func NewMockImportServiceInterface(ctrl *gomock.Controller) *MockImportServiceInterface {
+	mock := &MockImportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockImportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportServiceInterface) EXPECT() *MockImportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ImportChats mocks base method.
+func (m *MockImportServiceInterface) ImportChats(ctx context.Context, userID, format string, r io.Reader) ([]*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportChats", ctx, userID, format, r)
+	ret0, _ := ret[0].([]*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportChats indicates an expected call of ImportChats.
+func (mr *MockImportServiceInterfaceMockRecorder) ImportChats(ctx, userID, format, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportChats", reflect.TypeOf((*MockImportServiceInterface)(nil).ImportChats), ctx, userID, format, r)
+}
+
+// MockShareServiceInterface is a mock of ShareServiceInterface interface.
+type MockShareServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockShareServiceInterfaceMockRecorder is the mock recorder for MockShareServiceInterface.
+type MockShareServiceInterfaceMockRecorder struct {
+	mock *MockShareServiceInterface
+}
+
+// NewMockShareServiceInterface creates a new mock instance.
+func NewMockShareServiceInterface(ctrl *gomock.Controller) *MockShareServiceInterface {
+	mock := &MockShareServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockShareServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareServiceInterface) EXPECT() *MockShareServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateShare mocks base method.
+func (m *MockShareServiceInterface) CreateShare(ctx context.Context, chat *models.Chat, opts service.ShareOptions) (*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShare", ctx, chat, opts)
+	ret0, _ := ret[0].(*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShare indicates an expected call of CreateShare.
+func (mr *MockShareServiceInterfaceMockRecorder) CreateShare(ctx, chat, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShare", reflect.TypeOf((*MockShareServiceInterface)(nil).CreateShare), ctx, chat, opts)
+}
+
+// GetShare mocks base method.
+func (m *MockShareServiceInterface) GetShare(ctx context.Context, shareID string) (*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShare", ctx, shareID)
+	ret0, _ := ret[0].(*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShare indicates an expected call of GetShare.
+func (mr *MockShareServiceInterfaceMockRecorder) GetShare(ctx, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShare", reflect.TypeOf((*MockShareServiceInterface)(nil).GetShare), ctx, shareID)
+}
+
+// GetSharedChat mocks base method.
+func (m *MockShareServiceInterface) GetSharedChat(ctx context.Context, token string) (*models.SharedChat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedChat", ctx, token)
+	ret0, _ := ret[0].(*models.SharedChat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedChat indicates an expected call of GetSharedChat.
+func (mr *MockShareServiceInterfaceMockRecorder) GetSharedChat(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedChat", reflect.TypeOf((*MockShareServiceInterface)(nil).GetSharedChat), ctx, token)
+}
+
+// ListShares mocks base method.
+func (m *MockShareServiceInterface) ListShares(ctx context.Context, chatID string) ([]*models.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, chatID)
+	ret0, _ := ret[0].([]*models.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockShareServiceInterfaceMockRecorder) ListShares(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockShareServiceInterface)(nil).ListShares), ctx, chatID)
+}
+
+// RevokeShare mocks base method.
+func (m *MockShareServiceInterface) RevokeShare(ctx context.Context, shareID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShare", ctx, shareID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockShareServiceInterfaceMockRecorder) RevokeShare(ctx, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockShareServiceInterface)(nil).RevokeShare), ctx, shareID)
+}
+
+// MockUserServiceInterface is a mock of UserServiceInterface interface.
+type MockUserServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUserServiceInterfaceMockRecorder is the mock recorder for MockUserServiceInterface.
+type MockUserServiceInterfaceMockRecorder struct {
+	mock *MockUserServiceInterface
+}
+
+// NewMockUserServiceInterface creates a new mock instance.
+func NewMockUserServiceInterface(ctrl *gomock.Controller) *MockUserServiceInterface {
+	mock := &MockUserServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockUserServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserServiceInterface) EXPECT() *MockUserServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetUsersChatList mocks base method.
+func (m *MockUserServiceInterface) GetUsersChatList(ctx context.Context, userID string, filter models.ChatListFilter) ([]*models.UserChat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersChatList", ctx, userID, filter)
+	ret0, _ := ret[0].([]*models.UserChat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersChatList indicates an expected call of GetUsersChatList.
+func (mr *MockUserServiceInterfaceMockRecorder) GetUsersChatList(ctx, userID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersChatList", reflect.TypeOf((*MockUserServiceInterface)(nil).GetUsersChatList), ctx, userID, filter)
+}
+
+// MockTemplateServiceInterface is a mock of TemplateServiceInterface interface.
+type MockTemplateServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTemplateServiceInterfaceMockRecorder is the mock recorder for MockTemplateServiceInterface.
+type MockTemplateServiceInterfaceMockRecorder struct {
+	mock *MockTemplateServiceInterface
+}
+
+// NewMockTemplateServiceInterface creates a new mock instance.
+func NewMockTemplateServiceInterface(ctrl *gomock.Controller) *MockTemplateServiceInterface {
+	mock := &MockTemplateServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockTemplateServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateServiceInterface) EXPECT() *MockTemplateServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplate mocks base method.
+func (m *MockTemplateServiceInterface) CreateTemplate(ctx context.Context, tmpl *models.PromptTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", ctx, tmpl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockTemplateServiceInterfaceMockRecorder) CreateTemplate(ctx, tmpl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockTemplateServiceInterface)(nil).CreateTemplate), ctx, tmpl)
+}
+
+// DeleteTemplate mocks base method.
+func (m *MockTemplateServiceInterface) DeleteTemplate(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockTemplateServiceInterfaceMockRecorder) DeleteTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockTemplateServiceInterface)(nil).DeleteTemplate), ctx, id)
+}
+
+// GetTemplate mocks base method.
+func (m *MockTemplateServiceInterface) GetTemplate(ctx context.Context, id string) (*models.PromptTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, id)
+	ret0, _ := ret[0].(*models.PromptTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockTemplateServiceInterfaceMockRecorder) GetTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockTemplateServiceInterface)(nil).GetTemplate), ctx, id)
+}
+
+// ListTemplates mocks base method.
+func (m *MockTemplateServiceInterface) ListTemplates(ctx context.Context, userID string) ([]*models.PromptTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx, userID)
+	ret0, _ := ret[0].([]*models.PromptTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockTemplateServiceInterfaceMockRecorder) ListTemplates(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockTemplateServiceInterface)(nil).ListTemplates), ctx, userID)
+}
+
+// ListVersions mocks base method.
+func (m *MockTemplateServiceInterface) ListVersions(ctx context.Context, id string) ([]*models.PromptTemplateVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, id)
+	ret0, _ := ret[0].([]*models.PromptTemplateVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockTemplateServiceInterfaceMockRecorder) ListVersions(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockTemplateServiceInterface)(nil).ListVersions), ctx, id)
+}
+
+// RenderTemplate mocks base method.
+func (m *MockTemplateServiceInterface) RenderTemplate(ctx context.Context, tmpl *models.PromptTemplate, version int, variables map[string]any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderTemplate", ctx, tmpl, version, variables)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderTemplate indicates an expected call of RenderTemplate.
+func (mr *MockTemplateServiceInterfaceMockRecorder) RenderTemplate(ctx, tmpl, version, variables any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderTemplate", reflect.TypeOf((*MockTemplateServiceInterface)(nil).RenderTemplate), ctx, tmpl, version, variables)
+}
+
+// UpdateTemplate mocks base method.
+func (m *MockTemplateServiceInterface) UpdateTemplate(ctx context.Context, id string, update models.PromptTemplateUpdate) (*models.PromptTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplate", ctx, id, update)
+	ret0, _ := ret[0].(*models.PromptTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplate indicates an expected call of UpdateTemplate.
+func (mr *MockTemplateServiceInterfaceMockRecorder) UpdateTemplate(ctx, id, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplate", reflect.TypeOf((*MockTemplateServiceInterface)(nil).UpdateTemplate), ctx, id, update)
+}
+
+// MockAssistantServiceInterface is a mock of AssistantServiceInterface interface.
+type MockAssistantServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssistantServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAssistantServiceInterfaceMockRecorder is the mock recorder for MockAssistantServiceInterface.
+type MockAssistantServiceInterfaceMockRecorder struct {
+	mock *MockAssistantServiceInterface
+}
+
+// NewMockAssistantServiceInterface creates a new mock instance.
+func NewMockAssistantServiceInterface(ctrl *gomock.Controller) *MockAssistantServiceInterface {
+	mock := &MockAssistantServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAssistantServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssistantServiceInterface) EXPECT() *MockAssistantServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateAssistant mocks base method.
+func (m *MockAssistantServiceInterface) CreateAssistant(ctx context.Context, assistant *models.Assistant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAssistant", ctx, assistant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAssistant indicates an expected call of CreateAssistant.
+func (mr *MockAssistantServiceInterfaceMockRecorder) CreateAssistant(ctx, assistant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAssistant", reflect.TypeOf((*MockAssistantServiceInterface)(nil).CreateAssistant), ctx, assistant)
+}
+
+// DeleteAssistant mocks base method.
+func (m *MockAssistantServiceInterface) DeleteAssistant(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAssistant", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAssistant indicates an expected call of DeleteAssistant.
+func (mr *MockAssistantServiceInterfaceMockRecorder) DeleteAssistant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAssistant", reflect.TypeOf((*MockAssistantServiceInterface)(nil).DeleteAssistant), ctx, id)
+}
+
+// GetAssistant mocks base method.
+func (m *MockAssistantServiceInterface) GetAssistant(ctx context.Context, id string) (*models.Assistant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssistant", ctx, id)
+	ret0, _ := ret[0].(*models.Assistant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssistant indicates an expected call of GetAssistant.
+func (mr *MockAssistantServiceInterfaceMockRecorder) GetAssistant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssistant", reflect.TypeOf((*MockAssistantServiceInterface)(nil).GetAssistant), ctx, id)
+}
+
+// ListAssistants mocks base method.
+func (m *MockAssistantServiceInterface) ListAssistants(ctx context.Context, userID string) ([]*models.Assistant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssistants", ctx, userID)
+	ret0, _ := ret[0].([]*models.Assistant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssistants indicates an expected call of ListAssistants.
+func (mr *MockAssistantServiceInterfaceMockRecorder) ListAssistants(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssistants", reflect.TypeOf((*MockAssistantServiceInterface)(nil).ListAssistants), ctx, userID)
+}
+
+// SetChatAssistant mocks base method.
+func (m *MockAssistantServiceInterface) SetChatAssistant(ctx context.Context, userID, assistantID string, chatIDs []string, version int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChatAssistant", ctx, userID, assistantID, chatIDs, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetChatAssistant indicates an expected call of SetChatAssistant.
+func (mr *MockAssistantServiceInterfaceMockRecorder) SetChatAssistant(ctx, userID, assistantID, chatIDs, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatAssistant", reflect.TypeOf((*MockAssistantServiceInterface)(nil).SetChatAssistant), ctx, userID, assistantID, chatIDs, version)
+}
+
+// UpdateAssistant mocks base method.
+func (m *MockAssistantServiceInterface) UpdateAssistant(ctx context.Context, id string, update models.AssistantUpdate) (*models.Assistant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAssistant", ctx, id, update)
+	ret0, _ := ret[0].(*models.Assistant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAssistant indicates an expected call of UpdateAssistant.
+func (mr *MockAssistantServiceInterfaceMockRecorder) UpdateAssistant(ctx, id, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAssistant", reflect.TypeOf((*MockAssistantServiceInterface)(nil).UpdateAssistant), ctx, id, update)
+}
+
+// MockMemoryServiceInterface is a mock of MemoryServiceInterface interface.
+type MockMemoryServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMemoryServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockMemoryServiceInterfaceMockRecorder is the mock recorder for MockMemoryServiceInterface.
+type MockMemoryServiceInterfaceMockRecorder struct {
+	mock *MockMemoryServiceInterface
+}
+
+// NewMockMemoryServiceInterface creates a new mock instance.
+func NewMockMemoryServiceInterface(ctrl *gomock.Controller) *MockMemoryServiceInterface {
+	mock := &MockMemoryServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockMemoryServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMemoryServiceInterface) EXPECT() *MockMemoryServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddMemory mocks base method.
+func (m *MockMemoryServiceInterface) AddMemory(ctx context.Context, memory *models.Memory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMemory", ctx, memory)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMemory indicates an expected call of AddMemory.
+func (mr *MockMemoryServiceInterfaceMockRecorder) AddMemory(ctx, memory any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMemory", reflect.TypeOf((*MockMemoryServiceInterface)(nil).AddMemory), ctx, memory)
+}
+
+// DeleteMemory mocks base method.
+func (m *MockMemoryServiceInterface) DeleteMemory(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMemory", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMemory indicates an expected call of DeleteMemory.
+func (mr *MockMemoryServiceInterfaceMockRecorder) DeleteMemory(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMemory", reflect.TypeOf((*MockMemoryServiceInterface)(nil).DeleteMemory), ctx, userID, id)
+}
+
+// ExtractFromIdleChats mocks base method.
+func (m *MockMemoryServiceInterface) ExtractFromIdleChats(ctx context.Context, idle time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtractFromIdleChats", ctx, idle)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtractFromIdleChats indicates an expected call of ExtractFromIdleChats.
+func (mr *MockMemoryServiceInterfaceMockRecorder) ExtractFromIdleChats(ctx, idle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractFromIdleChats", reflect.TypeOf((*MockMemoryServiceInterface)(nil).ExtractFromIdleChats), ctx, idle)
+}
+
+// GetMemory mocks base method.
+func (m *MockMemoryServiceInterface) GetMemory(ctx context.Context, userID, id string) (*models.Memory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemory", ctx, userID, id)
+	ret0, _ := ret[0].(*models.Memory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemory indicates an expected call of GetMemory.
+func (mr *MockMemoryServiceInterfaceMockRecorder) GetMemory(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemory", reflect.TypeOf((*MockMemoryServiceInterface)(nil).GetMemory), ctx, userID, id)
+}
+
+// ListMemories mocks base method.
+func (m *MockMemoryServiceInterface) ListMemories(ctx context.Context, userID string) ([]*models.Memory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemories", ctx, userID)
+	ret0, _ := ret[0].([]*models.Memory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemories indicates an expected call of ListMemories.
+func (mr *MockMemoryServiceInterfaceMockRecorder) ListMemories(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemories", reflect.TypeOf((*MockMemoryServiceInterface)(nil).ListMemories), ctx, userID)
+}
+
+// MemoryPrompt mocks base method.
+func (m *MockMemoryServiceInterface) MemoryPrompt(ctx context.Context, userID, platform, model string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MemoryPrompt", ctx, userID, platform, model)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MemoryPrompt indicates an expected call of MemoryPrompt.
+func (mr *MockMemoryServiceInterfaceMockRecorder) MemoryPrompt(ctx, userID, platform, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MemoryPrompt", reflect.TypeOf((*MockMemoryServiceInterface)(nil).MemoryPrompt), ctx, userID, platform, model)
+}
+
+// UpdateMemory mocks base method.
+func (m *MockMemoryServiceInterface) UpdateMemory(ctx context.Context, userID, id string, update models.MemoryUpdate) (*models.Memory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemory", ctx, userID, id, update)
+	ret0, _ := ret[0].(*models.Memory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemory indicates an expected call of UpdateMemory.
+func (mr *MockMemoryServiceInterfaceMockRecorder) UpdateMemory(ctx, userID, id, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemory", reflect.TypeOf((*MockMemoryServiceInterface)(nil).UpdateMemory), ctx, userID, id, update)
+}
+
+// MockChatMemberServiceInterface is a mock of ChatMemberServiceInterface interface.
+type MockChatMemberServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatMemberServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockChatMemberServiceInterfaceMockRecorder is the mock recorder for MockChatMemberServiceInterface.
+type MockChatMemberServiceInterfaceMockRecorder struct {
+	mock *MockChatMemberServiceInterface
+}
+
+// NewMockChatMemberServiceInterface creates a new mock instance.
+func NewMockChatMemberServiceInterface(ctrl *gomock.Controller) *MockChatMemberServiceInterface {
+	mock := &MockChatMemberServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockChatMemberServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatMemberServiceInterface) EXPECT() *MockChatMemberServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockChatMemberServiceInterface) AddMember(ctx context.Context, chatID, email, role string) (*models.ChatMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, chatID, email, role)
+	ret0, _ := ret[0].(*models.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockChatMemberServiceInterfaceMockRecorder) AddMember(ctx, chatID, email, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockChatMemberServiceInterface)(nil).AddMember), ctx, chatID, email, role)
+}
+
+// ListMembers mocks base method.
+func (m *MockChatMemberServiceInterface) ListMembers(ctx context.Context, chatID string) ([]models.ChatMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, chatID)
+	ret0, _ := ret[0].([]models.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockChatMemberServiceInterfaceMockRecorder) ListMembers(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockChatMemberServiceInterface)(nil).ListMembers), ctx, chatID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockChatMemberServiceInterface) RemoveMember(ctx context.Context, chatID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, chatID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockChatMemberServiceInterfaceMockRecorder) RemoveMember(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockChatMemberServiceInterface)(nil).RemoveMember), ctx, chatID, userID)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockChatMemberServiceInterface) UpdateMemberRole(ctx context.Context, chatID, userID, role string) (*models.ChatMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, chatID, userID, role)
+	ret0, _ := ret[0].(*models.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockChatMemberServiceInterfaceMockRecorder) UpdateMemberRole(ctx, chatID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockChatMemberServiceInterface)(nil).UpdateMemberRole), ctx, chatID, userID, role)
+}
+
+// MockEventBrokerInterface is a mock of EventBrokerInterface interface.
+type MockEventBrokerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventBrokerInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockEventBrokerInterfaceMockRecorder is the mock recorder for MockEventBrokerInterface.
+type MockEventBrokerInterfaceMockRecorder struct {
+	mock *MockEventBrokerInterface
+}
+
+// NewMockEventBrokerInterface creates a new mock instance.
+func NewMockEventBrokerInterface(ctrl *gomock.Controller) *MockEventBrokerInterface {
+	mock := &MockEventBrokerInterface{ctrl: ctrl}
+	mock.recorder = &MockEventBrokerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventBrokerInterface) EXPECT() *MockEventBrokerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventBrokerInterface) Publish(ctx context.Context, event *models.ChatEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventBrokerInterfaceMockRecorder) Publish(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventBrokerInterface)(nil).Publish), ctx, event)
+}
+
+// Subscribe mocks base method.
+func (m *MockEventBrokerInterface) Subscribe(ctx context.Context, userID, resumeToken string) (<-chan *models.ChatEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, userID, resumeToken)
+	ret0, _ := ret[0].(<-chan *models.ChatEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventBrokerInterfaceMockRecorder) Subscribe(ctx, userID, resumeToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventBrokerInterface)(nil).Subscribe), ctx, userID, resumeToken)
+}
+
+// MockAttachmentServiceInterface is a mock of AttachmentServiceInterface interface.
+type MockAttachmentServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAttachmentServiceInterfaceMockRecorder is the mock recorder for MockAttachmentServiceInterface.
+type MockAttachmentServiceInterfaceMockRecorder struct {
+	mock *MockAttachmentServiceInterface
+}
+
+// NewMockAttachmentServiceInterface creates a new mock instance.
+func NewMockAttachmentServiceInterface(ctrl *gomock.Controller) *MockAttachmentServiceInterface {
+	mock := &MockAttachmentServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAttachmentServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentServiceInterface) EXPECT() *MockAttachmentServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetAttachment mocks base method.
+func (m *MockAttachmentServiceInterface) GetAttachment(ctx context.Context, id string) (*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, id)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) GetAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).GetAttachment), ctx, id)
+}
+
+// ListAttachments mocks base method.
+func (m *MockAttachmentServiceInterface) ListAttachments(ctx context.Context, chatID string) ([]*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttachments", ctx, chatID)
+	ret0, _ := ret[0].([]*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttachments indicates an expected call of ListAttachments.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) ListAttachments(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachments", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).ListAttachments), ctx, chatID)
+}
+
+// LoadAttachmentData mocks base method.
+func (m *MockAttachmentServiceInterface) LoadAttachmentData(ctx context.Context, chat *models.Chat, messages []models.Message, images bool) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAttachmentData", ctx, chat, messages, images)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAttachmentData indicates an expected call of LoadAttachmentData.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) LoadAttachmentData(ctx, chat, messages, images any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAttachmentData", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).LoadAttachmentData), ctx, chat, messages, images)
+}
+
+// NewAttachment mocks base method.
+func (m *MockAttachmentServiceInterface) NewAttachment(userID, name string, data []byte) (*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewAttachment", userID, name, data)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewAttachment indicates an expected call of NewAttachment.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) NewAttachment(userID, name, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAttachment", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).NewAttachment), userID, name, data)
+}
+
+// ReadAttachment mocks base method.
+func (m *MockAttachmentServiceInterface) ReadAttachment(ctx context.Context, id string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAttachment", ctx, id)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAttachment indicates an expected call of ReadAttachment.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) ReadAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAttachment", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).ReadAttachment), ctx, id)
+}
+
+// SaveAttachments mocks base method.
+func (m *MockAttachmentServiceInterface) SaveAttachments(ctx context.Context, chatID string, attachments []models.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttachments", ctx, chatID, attachments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttachments indicates an expected call of SaveAttachments.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) SaveAttachments(ctx, chatID, attachments any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttachments", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).SaveAttachments), ctx, chatID, attachments)
+}
+
+// MockToolRunnerInterface is a mock of ToolRunnerInterface interface.
+type MockToolRunnerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockToolRunnerInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockToolRunnerInterfaceMockRecorder is the mock recorder for MockToolRunnerInterface.
+type MockToolRunnerInterfaceMockRecorder struct {
+	mock *MockToolRunnerInterface
+}
+
+// NewMockToolRunnerInterface creates a new mock instance.
+func NewMockToolRunnerInterface(ctrl *gomock.Controller) *MockToolRunnerInterface {
+	mock := &MockToolRunnerInterface{ctrl: ctrl}
+	mock.recorder = &MockToolRunnerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockToolRunnerInterface) EXPECT() *MockToolRunnerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockToolRunnerInterface) Generate(ctx context.Context, platform string, req *models.GenerationRequest, scope service.ToolScope, callback func(string), onRun func(*models.ToolRun)) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", ctx, platform, req, scope, callback, onRun)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockToolRunnerInterfaceMockRecorder) Generate(ctx, platform, req, scope, callback, onRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockToolRunnerInterface)(nil).Generate), ctx, platform, req, scope, callback, onRun)
+}
+
+// ListToolRuns mocks base method.
+func (m *MockToolRunnerInterface) ListToolRuns(ctx context.Context, userID, chatID string) ([]*models.ToolRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolRuns", ctx, userID, chatID)
+	ret0, _ := ret[0].([]*models.ToolRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolRuns indicates an expected call of ListToolRuns.
+func (mr *MockToolRunnerInterfaceMockRecorder) ListToolRuns(ctx, userID, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolRuns", reflect.TypeOf((*MockToolRunnerInterface)(nil).ListToolRuns), ctx, userID, chatID)
+}
+
+// ServerTools mocks base method.
+func (m *MockToolRunnerInterface) ServerTools(names []string) ([]models.ToolDefinition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerTools", names)
+	ret0, _ := ret[0].([]models.ToolDefinition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerTools indicates an expected call of ServerTools.
+func (mr *MockToolRunnerInterfaceMockRecorder) ServerTools(names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerTools", reflect.TypeOf((*MockToolRunnerInterface)(nil).ServerTools), names)
+}
+
+// Tools mocks base method.
+func (m *MockToolRunnerInterface) Tools() []models.ToolDefinition {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tools")
+	ret0, _ := ret[0].([]models.ToolDefinition)
+	return ret0
+}
+
+// Tools indicates an expected call of Tools.
+func (mr *MockToolRunnerInterfaceMockRecorder) Tools() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tools", reflect.TypeOf((*MockToolRunnerInterface)(nil).Tools))
+}
+
+// MockStructuredOutputServiceInterface is a mock of StructuredOutputServiceInterface interface.
+type MockStructuredOutputServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStructuredOutputServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockStructuredOutputServiceInterfaceMockRecorder is the mock recorder for MockStructuredOutputServiceInterface.
+type MockStructuredOutputServiceInterfaceMockRecorder struct {
+	mock *MockStructuredOutputServiceInterface
+}
+
+// NewMockStructuredOutputServiceInterface creates a new mock instance.
+func NewMockStructuredOutputServiceInterface(ctrl *gomock.Controller) *MockStructuredOutputServiceInterface {
+	mock := &MockStructuredOutputServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockStructuredOutputServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStructuredOutputServiceInterface) EXPECT() *MockStructuredOutputServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockStructuredOutputServiceInterface) Generate(ctx context.Context, platform string, req *models.GenerationRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", ctx, platform, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockStructuredOutputServiceInterfaceMockRecorder) Generate(ctx, platform, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockStructuredOutputServiceInterface)(nil).Generate), ctx, platform, req)
+}
+
+// MockEmbeddingServiceInterface is a mock of EmbeddingServiceInterface interface.
+type MockEmbeddingServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmbeddingServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockEmbeddingServiceInterfaceMockRecorder is the mock recorder for MockEmbeddingServiceInterface.
+type MockEmbeddingServiceInterfaceMockRecorder struct {
+	mock *MockEmbeddingServiceInterface
+}
+
+// NewMockEmbeddingServiceInterface creates a new mock instance.
+func NewMockEmbeddingServiceInterface(ctrl *gomock.Controller) *MockEmbeddingServiceInterface {
+	mock := &MockEmbeddingServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockEmbeddingServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmbeddingServiceInterface) EXPECT() *MockEmbeddingServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateEmbeddings mocks base method.
+func (m *MockEmbeddingServiceInterface) CreateEmbeddings(ctx context.Context, platform string, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmbeddings", ctx, platform, req)
+	ret0, _ := ret[0].(*models.EmbeddingResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmbeddings indicates an expected call of CreateEmbeddings.
+func (mr *MockEmbeddingServiceInterfaceMockRecorder) CreateEmbeddings(ctx, platform, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmbeddings", reflect.TypeOf((*MockEmbeddingServiceInterface)(nil).CreateEmbeddings), ctx, platform, req)
+}
+
+// MockKnowledgeServiceInterface is a mock of KnowledgeServiceInterface interface.
+type MockKnowledgeServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKnowledgeServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockKnowledgeServiceInterfaceMockRecorder is the mock recorder for MockKnowledgeServiceInterface.
+type MockKnowledgeServiceInterfaceMockRecorder struct {
+	mock *MockKnowledgeServiceInterface
+}
+
+// NewMockKnowledgeServiceInterface creates a new mock instance.
+func NewMockKnowledgeServiceInterface(ctrl *gomock.Controller) *MockKnowledgeServiceInterface {
+	mock := &MockKnowledgeServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockKnowledgeServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKnowledgeServiceInterface) EXPECT() *MockKnowledgeServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddDocument mocks base method.
+func (m *MockKnowledgeServiceInterface) AddDocument(ctx context.Context, userID, knowledgeBaseID string, attachment *models.Attachment) (*models.KnowledgeDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDocument", ctx, userID, knowledgeBaseID, attachment)
+	ret0, _ := ret[0].(*models.KnowledgeDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDocument indicates an expected call of AddDocument.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) AddDocument(ctx, userID, knowledgeBaseID, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDocument", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).AddDocument), ctx, userID, knowledgeBaseID, attachment)
+}
+
+// CreateKnowledgeBase mocks base method.
+func (m *MockKnowledgeServiceInterface) CreateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKnowledgeBase", ctx, knowledgeBase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateKnowledgeBase indicates an expected call of CreateKnowledgeBase.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) CreateKnowledgeBase(ctx, knowledgeBase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKnowledgeBase", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).CreateKnowledgeBase), ctx, knowledgeBase)
+}
+
+// DeleteDocument mocks base method.
+func (m *MockKnowledgeServiceInterface) DeleteDocument(ctx context.Context, userID, knowledgeBaseID, documentID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDocument", ctx, userID, knowledgeBaseID, documentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDocument indicates an expected call of DeleteDocument.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) DeleteDocument(ctx, userID, knowledgeBaseID, documentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDocument", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).DeleteDocument), ctx, userID, knowledgeBaseID, documentID)
+}
+
+// DeleteKnowledgeBase mocks base method.
+func (m *MockKnowledgeServiceInterface) DeleteKnowledgeBase(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKnowledgeBase", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKnowledgeBase indicates an expected call of DeleteKnowledgeBase.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) DeleteKnowledgeBase(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKnowledgeBase", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).DeleteKnowledgeBase), ctx, userID, id)
+}
+
+// GetDocument mocks base method.
+func (m *MockKnowledgeServiceInterface) GetDocument(ctx context.Context, userID, knowledgeBaseID, documentID string) (*models.KnowledgeDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocument", ctx, userID, knowledgeBaseID, documentID)
+	ret0, _ := ret[0].(*models.KnowledgeDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDocument indicates an expected call of GetDocument.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) GetDocument(ctx, userID, knowledgeBaseID, documentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocument", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).GetDocument), ctx, userID, knowledgeBaseID, documentID)
+}
+
+// GetKnowledgeBase mocks base method.
+func (m *MockKnowledgeServiceInterface) GetKnowledgeBase(ctx context.Context, userID, id string) (*models.KnowledgeBase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKnowledgeBase", ctx, userID, id)
+	ret0, _ := ret[0].(*models.KnowledgeBase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKnowledgeBase indicates an expected call of GetKnowledgeBase.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) GetKnowledgeBase(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKnowledgeBase", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).GetKnowledgeBase), ctx, userID, id)
+}
+
+// IndexDocument mocks base method.
+func (m *MockKnowledgeServiceInterface) IndexDocument(ctx context.Context, document *models.KnowledgeDocument) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexDocument", ctx, document)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexDocument indicates an expected call of IndexDocument.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) IndexDocument(ctx, document any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexDocument", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).IndexDocument), ctx, document)
+}
+
+// ListDocuments mocks base method.
+func (m *MockKnowledgeServiceInterface) ListDocuments(ctx context.Context, userID, knowledgeBaseID string) ([]*models.KnowledgeDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDocuments", ctx, userID, knowledgeBaseID)
+	ret0, _ := ret[0].([]*models.KnowledgeDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDocuments indicates an expected call of ListDocuments.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) ListDocuments(ctx, userID, knowledgeBaseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDocuments", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).ListDocuments), ctx, userID, knowledgeBaseID)
+}
+
+// ListKnowledgeBases mocks base method.
+func (m *MockKnowledgeServiceInterface) ListKnowledgeBases(ctx context.Context, userID string) ([]*models.KnowledgeBase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKnowledgeBases", ctx, userID)
+	ret0, _ := ret[0].([]*models.KnowledgeBase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKnowledgeBases indicates an expected call of ListKnowledgeBases.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) ListKnowledgeBases(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKnowledgeBases", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).ListKnowledgeBases), ctx, userID)
+}
+
+// ReindexDocument mocks base method.
+func (m *MockKnowledgeServiceInterface) ReindexDocument(ctx context.Context, userID, knowledgeBaseID, documentID string) (*models.KnowledgeDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReindexDocument", ctx, userID, knowledgeBaseID, documentID)
+	ret0, _ := ret[0].(*models.KnowledgeDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReindexDocument indicates an expected call of ReindexDocument.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) ReindexDocument(ctx, userID, knowledgeBaseID, documentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReindexDocument", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).ReindexDocument), ctx, userID, knowledgeBaseID, documentID)
+}
+
+// Retrieve mocks base method.
+func (m *MockKnowledgeServiceInterface) Retrieve(ctx context.Context, userID string, knowledgeBaseIDs []string, query string) ([]models.Citation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retrieve", ctx, userID, knowledgeBaseIDs, query)
+	ret0, _ := ret[0].([]models.Citation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retrieve indicates an expected call of Retrieve.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) Retrieve(ctx, userID, knowledgeBaseIDs, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retrieve", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).Retrieve), ctx, userID, knowledgeBaseIDs, query)
+}
+
+// SetChatKnowledgeBases mocks base method.
+func (m *MockKnowledgeServiceInterface) SetChatKnowledgeBases(ctx context.Context, userID, chatID string, knowledgeBaseIDs []string, version int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChatKnowledgeBases", ctx, userID, chatID, knowledgeBaseIDs, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetChatKnowledgeBases indicates an expected call of SetChatKnowledgeBases.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) SetChatKnowledgeBases(ctx, userID, chatID, knowledgeBaseIDs, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatKnowledgeBases", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).SetChatKnowledgeBases), ctx, userID, chatID, knowledgeBaseIDs, version)
+}
+
+// UpdateKnowledgeBase mocks base method.
+func (m *MockKnowledgeServiceInterface) UpdateKnowledgeBase(ctx context.Context, userID, id, name, description string) (*models.KnowledgeBase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKnowledgeBase", ctx, userID, id, name, description)
+	ret0, _ := ret[0].(*models.KnowledgeBase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateKnowledgeBase indicates an expected call of UpdateKnowledgeBase.
+func (mr *MockKnowledgeServiceInterfaceMockRecorder) UpdateKnowledgeBase(ctx, userID, id, name, description any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKnowledgeBase", reflect.TypeOf((*MockKnowledgeServiceInterface)(nil).UpdateKnowledgeBase), ctx, userID, id, name, description)
+}
+
+// MockImageServiceInterface is a mock of ImageServiceInterface interface.
+type MockImageServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockImageServiceInterfaceMockRecorder is the mock recorder for MockImageServiceInterface.
+type MockImageServiceInterfaceMockRecorder struct {
+	mock *MockImageServiceInterface
+}
+
+// NewMockImageServiceInterface creates a new mock instance.
+func NewMockImageServiceInterface(ctrl *gomock.Controller) *MockImageServiceInterface {
+	mock := &MockImageServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockImageServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageServiceInterface) EXPECT() *MockImageServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GenerateImages mocks base method.
+func (m *MockImageServiceInterface) GenerateImages(ctx context.Context, platform string, req *models.ImageRequest) ([]models.GeneratedImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateImages", ctx, platform, req)
+	ret0, _ := ret[0].([]models.GeneratedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateImages indicates an expected call of GenerateImages.
+func (mr *MockImageServiceInterfaceMockRecorder) GenerateImages(ctx, platform, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateImages", reflect.TypeOf((*MockImageServiceInterface)(nil).GenerateImages), ctx, platform, req)
+}
+
+// SaveImages mocks base method.
+func (m *MockImageServiceInterface) SaveImages(ctx context.Context, chat *models.Chat, userID, platform string, req *models.ImageRequest, images []models.GeneratedImage) (*models.Chat, *models.ImageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveImages", ctx, chat, userID, platform, req, images)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(*models.ImageResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SaveImages indicates an expected call of SaveImages.
+func (mr *MockImageServiceInterfaceMockRecorder) SaveImages(ctx, chat, userID, platform, req, images any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveImages", reflect.TypeOf((*MockImageServiceInterface)(nil).SaveImages), ctx, chat, userID, platform, req, images)
+}
+
+// MockAudioServiceInterface is a mock of AudioServiceInterface interface.
+type MockAudioServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAudioServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAudioServiceInterfaceMockRecorder is the mock recorder for MockAudioServiceInterface.
+type MockAudioServiceInterfaceMockRecorder struct {
+	mock *MockAudioServiceInterface
+}
+
+// NewMockAudioServiceInterface creates a new mock instance.
+func NewMockAudioServiceInterface(ctrl *gomock.Controller) *MockAudioServiceInterface {
+	mock := &MockAudioServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAudioServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudioServiceInterface) EXPECT() *MockAudioServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Speak mocks base method.
+func (m *MockAudioServiceInterface) Speak(ctx context.Context, platform string, req *models.SpeechRequest) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Speak", ctx, platform, req)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Speak indicates an expected call of Speak.
+func (mr *MockAudioServiceInterfaceMockRecorder) Speak(ctx, platform, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Speak", reflect.TypeOf((*MockAudioServiceInterface)(nil).Speak), ctx, platform, req)
+}
+
+// Transcribe mocks base method.
+func (m *MockAudioServiceInterface) Transcribe(ctx context.Context, platform string, req *models.TranscriptionRequest) (*models.Transcription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transcribe", ctx, platform, req)
+	ret0, _ := ret[0].(*models.Transcription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transcribe indicates an expected call of Transcribe.
+func (mr *MockAudioServiceInterfaceMockRecorder) Transcribe(ctx, platform, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transcribe", reflect.TypeOf((*MockAudioServiceInterface)(nil).Transcribe), ctx, platform, req)
+}
+
+// MockPromptCacheManagerInterface is a mock of PromptCacheManagerInterface interface.
+type MockPromptCacheManagerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromptCacheManagerInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockPromptCacheManagerInterfaceMockRecorder is the mock recorder for MockPromptCacheManagerInterface.
+type MockPromptCacheManagerInterfaceMockRecorder struct {
+	mock *MockPromptCacheManagerInterface
+}
+
+// NewMockPromptCacheManagerInterface creates a new mock instance.
+func NewMockPromptCacheManagerInterface(ctrl *gomock.Controller) *MockPromptCacheManagerInterface {
+	mock := &MockPromptCacheManagerInterface{ctrl: ctrl}
+	mock.recorder = &MockPromptCacheManagerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromptCacheManagerInterface) EXPECT() *MockPromptCacheManagerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockPromptCacheManagerInterface) DeleteExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockPromptCacheManagerInterfaceMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockPromptCacheManagerInterface)(nil).DeleteExpired), ctx)
+}
+
+// Prepare mocks base method.
+func (m *MockPromptCacheManagerInterface) Prepare(ctx context.Context, platform, scope string, req *models.GenerationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepare", ctx, platform, scope, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prepare indicates an expected call of Prepare.
+func (mr *MockPromptCacheManagerInterfaceMockRecorder) Prepare(ctx, platform, scope, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersChatList", reflect.TypeOf((*MockUserServiceInterface)(nil).GetUsersChatList), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepare", reflect.TypeOf((*MockPromptCacheManagerInterface)(nil).Prepare), ctx, platform, scope, req)
 }
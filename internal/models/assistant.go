@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Assistant is a named preset of generation settings that chats can
+// reference. Shared assistants are visible to every user.
+type Assistant struct {
+	ID          string       `json:"id" bson:"_id"`
+	User        string       `json:"user" bson:"user"`
+	Name        string       `json:"name" bson:"name"`
+	Description string       `json:"description,omitempty" bson:"description,omitempty"`
+	Avatar      string       `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	Settings    ChatSettings `json:"settings" bson:"settings"`
+	Shared      bool         `json:"shared" bson:"shared"`
+	CreatedAt   time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+type AssistantUpdate struct {
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Avatar      *string       `json:"avatar,omitempty"`
+	Settings    *ChatSettings `json:"settings,omitempty"`
+	Shared      *bool         `json:"shared,omitempty"`
+}
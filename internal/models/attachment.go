@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	AttachmentKindImage    = "image"
+	AttachmentKindDocument = "document"
+)
+
+// Attachment is a file sent along with a message. The original lives in
+// GridFS; Data and the extracted Text of a document are only filled in while a
+// request is being generated.
+type Attachment struct {
+	ID        string    `json:"id" bson:"_id"`
+	User      string    `json:"user" bson:"user"`
+	ChatID    string    `json:"chat_id,omitempty" bson:"chat_id,omitempty"`
+	Kind      string    `json:"kind" bson:"kind"`
+	Name      string    `json:"name" bson:"name"`
+	MimeType  string    `json:"mime_type" bson:"mime_type"`
+	Size      int64     `json:"size" bson:"size"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	Data      []byte    `json:"-" bson:"-"`
+	Text      string    `json:"-" bson:"-"`
+}
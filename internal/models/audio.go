@@ -0,0 +1,31 @@
+package models
+
+import "io"
+
+// TranscriptionRequest carries an uploaded recording. FileName tells the
+// provider its format.
+type TranscriptionRequest struct {
+	Model    string
+	FileName string
+	Audio    io.Reader
+	Language string
+	Prompt   string
+}
+
+// Transcription is the text of a recording. Language and Duration, in
+// seconds, are only reported by some models.
+type Transcription struct {
+	Platform string  `json:"platform"`
+	Model    string  `json:"model"`
+	Text     string  `json:"text"`
+	Language string  `json:"language,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+type SpeechRequest struct {
+	Model  string  `json:"model"`
+	Input  string  `json:"input"`
+	Voice  string  `json:"voice"`
+	Format string  `json:"format,omitempty"`
+	Speed  float64 `json:"speed,omitempty"`
+}
@@ -2,19 +2,104 @@ package models
 
 import "time"
 
+const (
+	ChatRoleOwner  = "owner"
+	ChatRoleEditor = "editor"
+	ChatRoleViewer = "viewer"
+)
+
 type Chat struct {
-	ID        string    `json:"id" bson:"_id"`
-	User      string    `json:"user" bson:"user"`
-	Title     string    `json:"title" bson:"title"`
-	Messages  []Message `json:"messages" bson:"messages"`
-	CreatedAt time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	ID                string       `json:"id" bson:"_id"`
+	User              string       `json:"user" bson:"user"`
+	Title             string       `json:"title" bson:"title"`
+	Pinned            bool         `json:"pinned" bson:"pinned"`
+	Archived          bool         `json:"archived" bson:"archived"`
+	FolderID          string       `json:"folder_id,omitempty" bson:"folder_id,omitempty"`
+	Tags              []string     `json:"tags,omitempty" bson:"tags,omitempty"`
+	Messages          []Message    `json:"messages" bson:"messages"`
+	Summary           *Summary     `json:"summary,omitempty" bson:"summary,omitempty"`
+	Settings          ChatSettings `json:"settings" bson:"settings"`
+	AssistantID       string       `json:"assistant_id,omitempty" bson:"assistant_id,omitempty"`
+	KnowledgeBaseIDs  []string     `json:"knowledge_base_ids,omitempty" bson:"knowledge_base_ids,omitempty"`
+	ForkedFrom        *ForkSource  `json:"forked_from,omitempty" bson:"forked_from,omitempty"`
+	Members           []ChatMember `json:"members,omitempty" bson:"members,omitempty"`
+	Version           int64        `json:"version" bson:"version"`
+	CreatedAt         time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at" bson:"updated_at"`
+	MemoryExtractedAt *time.Time   `json:"memory_extracted_at,omitempty" bson:"memory_extracted_at,omitempty"`
+	// MemoryFailures counts the extraction attempts that failed in a row, and
+	// MemoryRetryAt is when the next one may run.
+	MemoryFailures int        `json:"-" bson:"memory_failures,omitempty"`
+	MemoryRetryAt  *time.Time `json:"-" bson:"memory_retry_at,omitempty"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
+// Message is a chat message. Assistant messages that asked for tools carry
+// ToolCalls, and each result is a following "tool" message whose ToolCallID
+// names the call it answers.
 type Message struct {
-	ID     string    `json:"id" bson:"_id"`
-	Text   string    `json:"text" bson:"text"`
-	Role   string    `json:"role" bson:"role"`
-	AI     string    `json:"ai,omitempty" bson:"ai,omitempty"`
-	SentAt time.Time `json:"sent_at" bson:"sent_at"`
+	ID          string          `json:"id" bson:"_id"`
+	Text        string          `json:"text" bson:"text"`
+	Role        string          `json:"role" bson:"role"`
+	AI          string          `json:"ai,omitempty" bson:"ai,omitempty"`
+	UserID      string          `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Generation  *GenerationInfo `json:"generation,omitempty" bson:"generation,omitempty"`
+	Attachments []Attachment    `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	ToolCalls   []ToolCall      `json:"tool_calls,omitempty" bson:"tool_calls,omitempty"`
+	ToolCallID  string          `json:"tool_call_id,omitempty" bson:"tool_call_id,omitempty"`
+	ToolName    string          `json:"tool_name,omitempty" bson:"tool_name,omitempty"`
+	Citations   []Citation      `json:"citations,omitempty" bson:"citations,omitempty"`
+	Usage       *Usage          `json:"usage,omitempty" bson:"usage,omitempty"`
+	SentAt      time.Time       `json:"sent_at" bson:"sent_at"`
+	// CacheControl marks the end of a prompt prefix that stays the same from
+	// turn to turn, so providers may cache everything up to it.
+	CacheControl bool `json:"-" bson:"-"`
+}
+
+// ChatMember is a user the owner invited into the chat. The owner is the
+// chat's User and is not listed among the members.
+type ChatMember struct {
+	UserID  string    `json:"user_id" bson:"user_id"`
+	Email   string    `json:"email" bson:"email"`
+	Role    string    `json:"role" bson:"role"`
+	AddedAt time.Time `json:"added_at" bson:"added_at"`
+}
+
+// ForkSource points at the chat and message a forked chat was copied from.
+type ForkSource struct {
+	ChatID    string `json:"chat_id" bson:"chat_id"`
+	MessageID string `json:"message_id" bson:"message_id"`
+}
+
+// Summary condenses the messages of a chat up to and including
+// LastMessageID.
+type Summary struct {
+	Text          string    `json:"text" bson:"text"`
+	LastMessageID string    `json:"last_message_id" bson:"last_message_id"`
+	MessageCount  int       `json:"message_count" bson:"message_count"`
+	Model         string    `json:"model" bson:"model"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ChatUpdate describes a partial update applied to one or more chats. Nil
+// fields are left untouched.
+type ChatUpdate struct {
+	Pinned      *bool   `json:"pinned,omitempty"`
+	Archived    *bool   `json:"archived,omitempty"`
+	FolderID    *string `json:"folder_id,omitempty"`
+	AssistantID *string `json:"assistant_id,omitempty"`
+	// KnowledgeBaseIDs replaces the chat's knowledge bases when not nil.
+	KnowledgeBaseIDs []string `json:"knowledge_base_ids,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	AddTags          []string `json:"add_tags,omitempty"`
+	RemoveTags       []string `json:"remove_tags,omitempty"`
+	// Version restricts the update to chats still at this version when set.
+	Version int64 `json:"-"`
+}
+
+type ChatListFilter struct {
+	FolderID string
+	Tags     []string
+	Pinned   *bool
+	Archived bool
 }
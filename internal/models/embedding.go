@@ -0,0 +1,28 @@
+package models
+
+type EmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type EmbeddingResponse struct {
+	Platform string         `json:"platform"`
+	Model    string         `json:"model"`
+	Data     []Embedding    `json:"data"`
+	Usage    EmbeddingUsage `json:"usage"`
+}
+
+// Embedding is the vector for the input at Index.
+type Embedding struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// EmbeddingUsage counts input tokens. Estimated is set when the provider does
+// not report them and they were estimated instead.
+type EmbeddingUsage struct {
+	PromptTokens int  `json:"prompt_tokens"`
+	TotalTokens  int  `json:"total_tokens"`
+	Estimated    bool `json:"estimated,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	EventChatCreated  = "chat.created"
+	EventChatUpdated  = "chat.updated"
+	EventChatDeleted  = "chat.deleted"
+	EventMessageAdded = "message.added"
+	// EventReset tells a client that events were missed and it has to reload
+	// its chats.
+	EventReset = "reset"
+)
+
+// ChatEvent is a change to a chat pushed to everyone who can see it. ID is
+// the resume token of the event and is only meaningful to the broker that
+// delivered it.
+type ChatEvent struct {
+	ID      string    `json:"-" bson:"-"`
+	Type    string    `json:"type" bson:"type"`
+	ChatID  string    `json:"chat_id,omitempty" bson:"chat_id,omitempty"`
+	Chat    *Chat     `json:"chat,omitempty" bson:"chat,omitempty"`
+	Message *Message  `json:"message,omitempty" bson:"message,omitempty"`
+	UserIDs []string  `json:"-" bson:"user_ids"`
+	At      time.Time `json:"at" bson:"at"`
+}
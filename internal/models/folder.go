@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Folder struct {
+	ID        string    `json:"id" bson:"_id"`
+	User      string    `json:"user" bson:"user"`
+	Name      string    `json:"name" bson:"name"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "encoding/json"
+
+type GenerationRequest struct {
+	Model          string           `json:"model"`
+	Messages       []Message        `json:"messages"`
+	Sampling       SamplingParams   `json:"sampling"`
+	Tools          []ToolDefinition `json:"tools,omitempty"`
+	ResponseSchema *ResponseSchema  `json:"response_schema,omitempty"`
+	// CachedContent names a provider-side cache holding the messages up to
+	// the last one marked CacheControl. Providers that cannot use it send the
+	// messages instead.
+	CachedContent string `json:"-"`
+	// OnToolCall receives each complete tool call the model makes. Calls are
+	// dropped when it is nil.
+	OnToolCall func(ToolCall) `json:"-"`
+	// OnUsage receives the token usage of the generation when the provider
+	// reports it.
+	OnUsage func(Usage) `json:"-"`
+}
+
+// Usage counts the tokens of a generation. CachedTokens are the prompt tokens
+// read from a provider-side cache, and are included in PromptTokens.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens" bson:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens" bson:"completion_tokens"`
+	CachedTokens     int `json:"cached_tokens" bson:"cached_tokens"`
+	TotalTokens      int `json:"total_tokens" bson:"total_tokens"`
+}
+
+// Add counts the usage of another generation, such as a later step of a
+// tool loop, into u.
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.CachedTokens += other.CachedTokens
+	u.TotalTokens += other.TotalTokens
+}
+
+// ResponseSchema asks the model to reply with a JSON document matching the
+// JSON Schema.
+type ResponseSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// SamplingParams are optional; nil fields leave the provider default in place.
+type SamplingParams struct {
+	Temperature *float64 `json:"temperature,omitempty" bson:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty" bson:"top_p,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty" bson:"max_tokens,omitempty"`
+}
+
+// ChatSettings are the defaults applied to every generation in a chat.
+type ChatSettings struct {
+	SystemPrompt string         `json:"system_prompt,omitempty" bson:"system_prompt,omitempty"`
+	Platform     string         `json:"platform,omitempty" bson:"platform,omitempty"`
+	Model        string         `json:"model,omitempty" bson:"model,omitempty"`
+	Sampling     SamplingParams `json:"sampling" bson:"sampling"`
+}
+
+// GenerationInfo records the settings an assistant message was generated
+// with.
+type GenerationInfo struct {
+	Platform     string         `json:"platform" bson:"platform"`
+	Model        string         `json:"model" bson:"model"`
+	AssistantID  string         `json:"assistant_id,omitempty" bson:"assistant_id,omitempty"`
+	SystemPrompt string         `json:"system_prompt,omitempty" bson:"system_prompt,omitempty"`
+	Sampling     SamplingParams `json:"sampling" bson:"sampling"`
+}
@@ -0,0 +1,35 @@
+package models
+
+type ImageRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Size   string `json:"size,omitempty"`
+	Count  int    `json:"count,omitempty"`
+	Style  string `json:"style,omitempty"`
+}
+
+// GeneratedImage is one image returned by a provider. RevisedPrompt is the
+// prompt the provider actually used, when it rewrote ours.
+type GeneratedImage struct {
+	Data          []byte
+	MimeType      string
+	RevisedPrompt string
+}
+
+// ImageResult points at a generated image stored as an attachment of the
+// chat it was generated in.
+type ImageResult struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	MimeType      string `json:"mime_type"`
+	Size          int64  `json:"size"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+type ImageResponse struct {
+	ChatID    string        `json:"chat_id"`
+	MessageID string        `json:"message_id"`
+	Platform  string        `json:"platform"`
+	Model     string        `json:"model"`
+	Images    []ImageResult `json:"images"`
+}
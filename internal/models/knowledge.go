@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+const (
+	DocumentStatusIndexing = "indexing"
+	DocumentStatusReady    = "ready"
+	DocumentStatusFailed   = "failed"
+)
+
+// KnowledgeBase is a collection of documents chats can retrieve from. All of
+// its chunks are embedded with the same model so they can be compared.
+type KnowledgeBase struct {
+	ID          string    `json:"id" bson:"_id"`
+	User        string    `json:"user" bson:"user"`
+	Name        string    `json:"name" bson:"name"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Platform    string    `json:"platform" bson:"platform"`
+	Model       string    `json:"model" bson:"model"`
+	Dimensions  int       `json:"dimensions,omitempty" bson:"dimensions,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// KnowledgeDocument keeps the extracted text of an uploaded document so it can
+// be indexed again.
+type KnowledgeDocument struct {
+	ID              string     `json:"id" bson:"_id"`
+	User            string     `json:"user" bson:"user"`
+	KnowledgeBaseID string     `json:"knowledge_base_id" bson:"knowledge_base_id"`
+	Name            string     `json:"name" bson:"name"`
+	MimeType        string     `json:"mime_type" bson:"mime_type"`
+	Size            int64      `json:"size" bson:"size"`
+	Text            string     `json:"-" bson:"text,omitempty"`
+	Status          string     `json:"status" bson:"status"`
+	Error           string     `json:"error,omitempty" bson:"error,omitempty"`
+	ChunkCount      int        `json:"chunk_count" bson:"chunk_count"`
+	CreatedAt       time.Time  `json:"created_at" bson:"created_at"`
+	IndexedAt       *time.Time `json:"indexed_at,omitempty" bson:"indexed_at,omitempty"`
+	// IndexingAt is when the last indexing run started.
+	IndexingAt *time.Time `json:"-" bson:"indexing_at,omitempty"`
+}
+
+// KnowledgeChunk is a piece of a document with its embedding. Start and End
+// are character offsets into the document's text.
+type KnowledgeChunk struct {
+	ID              string    `json:"id" bson:"_id"`
+	KnowledgeBaseID string    `json:"knowledge_base_id" bson:"knowledge_base_id"`
+	DocumentID      string    `json:"document_id" bson:"document_id"`
+	DocumentName    string    `json:"document_name" bson:"document_name"`
+	Index           int       `json:"index" bson:"index"`
+	Text            string    `json:"text" bson:"text"`
+	Start           int       `json:"start" bson:"start"`
+	End             int       `json:"end" bson:"end"`
+	Embedding       []float32 `json:"-" bson:"embedding"`
+}
+
+// Citation points from a numbered source in a prompt back to the chunk it
+// came from.
+type Citation struct {
+	Number          int     `json:"number" bson:"number"`
+	KnowledgeBaseID string  `json:"knowledge_base_id" bson:"knowledge_base_id"`
+	DocumentID      string  `json:"document_id" bson:"document_id"`
+	DocumentName    string  `json:"document_name" bson:"document_name"`
+	ChunkID         string  `json:"chunk_id" bson:"chunk_id"`
+	Start           int     `json:"start" bson:"start"`
+	End             int     `json:"end" bson:"end"`
+	Score           float64 `json:"score" bson:"score"`
+	Text            string  `json:"text" bson:"text"`
+}
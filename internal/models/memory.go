@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	MemorySourceUser      = "user"
+	MemorySourceExtracted = "extracted"
+)
+
+// Memory is a short fact about a user that is added to the system prompt of
+// their chats while enabled.
+type Memory struct {
+	ID        string    `json:"id" bson:"_id"`
+	User      string    `json:"user" bson:"user"`
+	Text      string    `json:"text" bson:"text"`
+	Source    string    `json:"source" bson:"source"`
+	ChatID    string    `json:"chat_id,omitempty" bson:"chat_id,omitempty"`
+	Enabled   bool      `json:"enabled" bson:"enabled"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type MemoryUpdate struct {
+	Text    *string `json:"text,omitempty"`
+	Enabled *bool   `json:"enabled,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// PromptCache tracks a provider-side cache of a prompt prefix. Scope is the
+// chat or assistant the prefix belongs to, such as "assistant:ast_...", and
+// Hash identifies the cached messages.
+type PromptCache struct {
+	ID         string    `json:"id" bson:"_id"`
+	Scope      string    `json:"scope" bson:"scope"`
+	Platform   string    `json:"platform" bson:"platform"`
+	Model      string    `json:"model" bson:"model"`
+	Name       string    `json:"name" bson:"name"`
+	Hash       string    `json:"hash" bson:"hash"`
+	Tokens     int       `json:"tokens" bson:"tokens"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at" bson:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// CachedPrefixLength is the number of leading messages up to and including
+// the last one marked CacheControl, or zero when none is marked.
+func CachedPrefixLength(messages []Message) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].CacheControl {
+			return i + 1
+		}
+	}
+	return 0
+}
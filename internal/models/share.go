@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type Share struct {
+	ID         string     `json:"id" bson:"_id"`
+	Token      string     `json:"token" bson:"token"`
+	ChatID     string     `json:"chat_id" bson:"chat_id"`
+	User       string     `json:"user" bson:"user"`
+	Title      string     `json:"title" bson:"title"`
+	Messages   []Message  `json:"messages,omitempty" bson:"messages"`
+	HideModels bool       `json:"hide_models" bson:"hide_models"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// SharedChat is the public view of a share. It deliberately carries no chat,
+// message or user IDs.
+type SharedChat struct {
+	Title     string          `json:"title"`
+	Messages  []SharedMessage `json:"messages"`
+	SharedAt  time.Time       `json:"shared_at"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+}
+
+type SharedMessage struct {
+	Text   string    `json:"text"`
+	Role   string    `json:"role"`
+	AI     string    `json:"ai,omitempty"`
+	SentAt time.Time `json:"sent_at"`
+}
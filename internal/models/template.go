@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// PromptTemplate is a reusable prompt rendered with text/template. Shared
+// templates are published by admins and visible to every user.
+type PromptTemplate struct {
+	ID          string    `json:"id" bson:"_id"`
+	User        string    `json:"user" bson:"user"`
+	Name        string    `json:"name" bson:"name"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	Body        string    `json:"body" bson:"body"`
+	Variables   []string  `json:"variables" bson:"variables"`
+	Version     int       `json:"version" bson:"version"`
+	Shared      bool      `json:"shared" bson:"shared"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type PromptTemplateVersion struct {
+	TemplateID string    `json:"template_id" bson:"template_id"`
+	Version    int       `json:"version" bson:"version"`
+	Body       string    `json:"body" bson:"body"`
+	Variables  []string  `json:"variables" bson:"variables"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+type PromptTemplateUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Body        *string `json:"body,omitempty"`
+	Shared      *bool   `json:"shared,omitempty"`
+}
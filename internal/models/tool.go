@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ToolDefinition describes a function the model may call. Parameters is a
+// JSON Schema object.
+type ToolDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall is a request from the model to call a tool. Arguments is a JSON
+// object encoded as a string.
+type ToolCall struct {
+	ID        string `json:"id" bson:"id"`
+	Name      string `json:"name" bson:"name"`
+	Arguments string `json:"arguments" bson:"arguments"`
+}
+
+// ToolResult is the output a client posts back for a tool call.
+type ToolResult struct {
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// ToolRun records a call to a server-side tool for auditing.
+type ToolRun struct {
+	ID         string    `json:"id" bson:"_id"`
+	User       string    `json:"user" bson:"user"`
+	ChatID     string    `json:"chat_id,omitempty" bson:"chat_id,omitempty"`
+	Step       int       `json:"step" bson:"step"`
+	ToolCallID string    `json:"tool_call_id" bson:"tool_call_id"`
+	Name       string    `json:"name" bson:"name"`
+	Arguments  string    `json:"arguments" bson:"arguments"`
+	Result     string    `json:"result,omitempty" bson:"result,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms" bson:"duration_ms"`
+	StartedAt  time.Time `json:"started_at" bson:"started_at"`
+}
@@ -2,6 +2,11 @@ package models
 
 import "time"
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID    string `json:"id" bson:"_id"`
 	Name  string `json:"name" bson:"name"`
@@ -10,9 +15,15 @@ type User struct {
 }
 
 type UserChat struct {
-	ID        string    `json:"id" bson:"id"`
-	User      string    `json:"user" bson:"user"`
-	ChatTitle string    `json:"chat_title" bson:"chat_title"`
-	CreatedAt time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	ID        string     `json:"id" bson:"id"`
+	User      string     `json:"user" bson:"user"`
+	ChatTitle string     `json:"chat_title" bson:"chat_title"`
+	Role      string     `json:"role,omitempty" bson:"role,omitempty"`
+	Pinned    bool       `json:"pinned" bson:"pinned"`
+	Archived  bool       `json:"archived" bson:"archived"`
+	FolderID  string     `json:"folder_id,omitempty" bson:"folder_id,omitempty"`
+	Tags      []string   `json:"tags,omitempty" bson:"tags,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
+	"github.com/lutefd/ai-router-go/internal/models"
 	openai "github.com/sashabaranov/go-openai"
 )
 
@@ -15,6 +21,7 @@ type DeepSeekRepository struct {
 func NewDeepSeekRepository(apiKey string) *DeepSeekRepository {
 	config := openai.DefaultConfig(apiKey)
 	config.BaseURL = "https://api.deepseek.com/v1"
+	config.HTTPClient = &http.Client{Transport: deepSeekUsageTransport{base: http.DefaultTransport}}
 	client := openai.NewClientWithConfig(config)
 
 	return &DeepSeekRepository{client: client}
@@ -53,3 +60,87 @@ func (r *DeepSeekRepository) GenerateContentStream(ctx context.Context,
 
 	return nil
 }
+
+func (r *DeepSeekRepository) GenerateChatStream(ctx context.Context,
+	req *models.GenerationRequest, callback func(string)) error {
+	return streamOpenAIChat(ctx, r.client, req, callback, false)
+}
+
+// deepSeekUsageTransport moves the cached prompt tokens DeepSeek streams as
+// prompt_cache_hit_tokens to prompt_tokens_details.cached_tokens, where
+// OpenAI reports them and the client library reads them.
+type deepSeekUsageTransport struct {
+	base http.RoundTripper
+}
+
+func (t deepSeekUsageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return resp, err
+	}
+	resp.Body = &deepSeekUsageReader{body: resp.Body, lines: bufio.NewReader(resp.Body)}
+	return resp, nil
+}
+
+// deepSeekUsageReader rewrites a server-sent event stream line by line.
+type deepSeekUsageReader struct {
+	body    io.ReadCloser
+	lines   *bufio.Reader
+	pending []byte
+	err     error
+}
+
+func (r *deepSeekUsageReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 && r.err == nil {
+		var line []byte
+		line, r.err = r.lines.ReadBytes('\n')
+		r.pending = deepSeekUsageLine(line)
+	}
+	if len(r.pending) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *deepSeekUsageReader) Close() error {
+	return r.body.Close()
+}
+
+// deepSeekUsageLine returns the event line with the cache hits of its usage
+// copied into prompt_tokens_details. Other lines are returned as they are.
+func deepSeekUsageLine(line []byte) []byte {
+	data, ok := bytes.CutPrefix(line, []byte("data: "))
+	if !ok || !bytes.Contains(data, []byte(`"prompt_cache_hit_tokens"`)) {
+		return line
+	}
+
+	var chunk map[string]json.RawMessage
+	var usage map[string]json.RawMessage
+	if json.Unmarshal(data, &chunk) != nil || json.Unmarshal(chunk["usage"], &usage) != nil {
+		return line
+	}
+	if _, ok := usage["prompt_tokens_details"]; ok {
+		return line
+	}
+	details, err := json.Marshal(openai.PromptTokensDetails{CachedTokens: deepSeekCacheHits(usage)})
+	if err != nil {
+		return line
+	}
+	usage["prompt_tokens_details"] = details
+	if chunk["usage"], err = json.Marshal(usage); err != nil {
+		return line
+	}
+	rewritten, err := json.Marshal(chunk)
+	if err != nil {
+		return line
+	}
+	return append(append([]byte("data: "), rewritten...), '\n')
+}
+
+func deepSeekCacheHits(usage map[string]json.RawMessage) int {
+	var hits int
+	json.Unmarshal(usage["prompt_cache_hit_tokens"], &hits)
+	return hits
+}
@@ -2,13 +2,24 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
 	"google.golang.org/genai"
 )
 
 type GeminiRepository struct {
 	client *genai.Client
+	// The SDK has no embeddings API, so those requests go straight to the
+	// REST endpoint.
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
 }
 
 func NewGeminiRepository(ctx context.Context, geminiSK string) *GeminiRepository {
@@ -19,7 +30,12 @@ func NewGeminiRepository(ctx context.Context, geminiSK string) *GeminiRepository
 	if err != nil {
 		panic(fmt.Errorf("failed to create gemini client: %w", err))
 	}
-	return &GeminiRepository{client: geminiClient}
+	return &GeminiRepository{
+		client:     geminiClient,
+		apiKey:     geminiSK,
+		baseURL:    geminiAPIURL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
 }
 
 func (r *GeminiRepository) GenerateContentStream(ctx context.Context,
@@ -39,3 +55,385 @@ func (r *GeminiRepository) GenerateContentStream(ctx context.Context,
 
 	return nil
 }
+
+// GenerateImages uses Imagen, which takes an aspect ratio as the size.
+// Images withheld by the safety filters are left out; when every image is
+// withheld the filter's reason is returned as the error.
+func (r *GeminiRepository) GenerateImages(ctx context.Context,
+	req *models.ImageRequest) ([]models.GeneratedImage, error) {
+	count := int64(req.Count)
+	response, err := r.client.Models.GenerateImages(ctx, req.Model, req.Prompt, &genai.GenerateImagesConfig{
+		NumberOfImages:   &count,
+		AspectRatio:      req.Size,
+		IncludeRAIReason: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating images: %w", err)
+	}
+
+	var images []models.GeneratedImage
+	var filtered string
+	for _, generated := range response.GeneratedImages {
+		if generated.Image == nil || len(generated.Image.ImageBytes) == 0 {
+			if generated.RAIFilteredReason != "" {
+				filtered = generated.RAIFilteredReason
+			}
+			continue
+		}
+		mimeType := generated.Image.MIMEType
+		if mimeType == "" {
+			mimeType = http.DetectContentType(generated.Image.ImageBytes)
+		}
+		images = append(images, models.GeneratedImage{
+			Data:          generated.Image.ImageBytes,
+			MimeType:      mimeType,
+			RevisedPrompt: generated.EnhancedPrompt,
+		})
+	}
+	if len(images) == 0 {
+		if filtered != "" {
+			return nil, fmt.Errorf("images were blocked: %s", filtered)
+		}
+		return nil, fmt.Errorf("no images were generated")
+	}
+	return images, nil
+}
+
+// CreateCachedContent caches the system messages as a system instruction and
+// the other messages as contents.
+func (r *GeminiRepository) CreateCachedContent(ctx context.Context, model string,
+	messages []models.Message, ttl time.Duration) (string, time.Time, error) {
+	system, contents := geminiContents(messages)
+	config := &genai.CreateCachedContentConfig{
+		TTL:      geminiTTL(ttl),
+		Contents: contents,
+	}
+	if len(system) > 0 {
+		config.SystemInstruction = &genai.Content{
+			Parts: []*genai.Part{{Text: strings.Join(system, "\n\n")}},
+		}
+	}
+	cache, err := r.client.Caches.Create(ctx, model, config)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error creating cached content: %w", err)
+	}
+	return cache.Name, geminiExpireTime(cache, ttl), nil
+}
+
+func (r *GeminiRepository) RefreshCachedContent(ctx context.Context, name string,
+	ttl time.Duration) (time.Time, error) {
+	cache, err := r.client.Caches.Update(ctx, name, &genai.UpdateCachedContentConfig{TTL: geminiTTL(ttl)})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error refreshing cached content: %w", err)
+	}
+	return geminiExpireTime(cache, ttl), nil
+}
+
+func (r *GeminiRepository) DeleteCachedContent(ctx context.Context, name string) error {
+	if _, err := r.client.Caches.Delete(ctx, name, nil); err != nil {
+		return fmt.Errorf("error deleting cached content: %w", err)
+	}
+	return nil
+}
+
+func geminiTTL(ttl time.Duration) string {
+	return fmt.Sprintf("%ds", int64(ttl.Seconds()))
+}
+
+func geminiExpireTime(cache *genai.CachedContent, ttl time.Duration) time.Time {
+	if cache.ExpireTime != nil {
+		return *cache.ExpireTime
+	}
+	return time.Now().Add(ttl)
+}
+
+// GenerateChatStream uses req.CachedContent in place of the messages it
+// holds. Gemini takes no system instruction or tools next to cached content,
+// so later system messages are sent ahead of the conversation, and requests
+// with tools are sent without the cache.
+func (r *GeminiRepository) GenerateChatStream(ctx context.Context,
+	req *models.GenerationRequest, callback func(string)) error {
+	messages := req.Messages
+	cached := req.CachedContent != "" && len(req.Tools) == 0
+	if cached {
+		messages = messages[models.CachedPrefixLength(messages):]
+	}
+	system, contents := geminiContents(messages)
+
+	config := &genai.GenerateContentConfig{
+		Temperature: req.Sampling.Temperature,
+		TopP:        req.Sampling.TopP,
+	}
+	if req.Sampling.MaxTokens != nil {
+		maxTokens := int64(*req.Sampling.MaxTokens)
+		config.MaxOutputTokens = &maxTokens
+	}
+	if len(req.Tools) > 0 {
+		tools, err := geminiTools(req.Tools)
+		if err != nil {
+			return err
+		}
+		config.Tools = tools
+	}
+	if req.ResponseSchema != nil {
+		schema, err := geminiSchema(req.ResponseSchema.Schema)
+		if err != nil {
+			return fmt.Errorf("invalid response schema: %w", err)
+		}
+		config.ResponseMIMEType = "application/json"
+		config.ResponseSchema = schema
+	}
+	switch {
+	case cached:
+		config.CachedContent = req.CachedContent
+		if len(system) > 0 {
+			part := &genai.Part{Text: strings.Join(system, "\n\n")}
+			if len(contents) > 0 && contents[0].Role == "user" {
+				contents[0].Parts = append([]*genai.Part{part}, contents[0].Parts...)
+			} else {
+				contents = append([]*genai.Content{{Role: "user", Parts: []*genai.Part{part}}}, contents...)
+			}
+		}
+	case len(system) > 0:
+		config.SystemInstruction = &genai.Content{
+			Parts: []*genai.Part{{Text: strings.Join(system, "\n\n")}},
+		}
+	}
+
+	var usage *genai.GenerateContentResponseUsageMetadata
+	for result, err := range r.client.Models.GenerateContentStream(ctx,
+		req.Model, contents, config) {
+		if err != nil {
+			return err
+		}
+		if result.UsageMetadata != nil {
+			usage = result.UsageMetadata
+		}
+		if len(result.Candidates) == 0 || result.Candidates[0].Content == nil {
+			continue
+		}
+		for _, part := range result.Candidates[0].Content.Parts {
+			if part.Text != "" {
+				callback(part.Text)
+			}
+			if part.FunctionCall != nil && req.OnToolCall != nil {
+				req.OnToolCall(geminiToolCall(part.FunctionCall))
+			}
+		}
+	}
+
+	if usage != nil && req.OnUsage != nil {
+		req.OnUsage(geminiUsage(usage))
+	}
+	return nil
+}
+
+func geminiUsage(metadata *genai.GenerateContentResponseUsageMetadata) models.Usage {
+	usage := models.Usage{TotalTokens: int(metadata.TotalTokenCount)}
+	if metadata.PromptTokenCount != nil {
+		usage.PromptTokens = int(*metadata.PromptTokenCount)
+	}
+	if metadata.CandidatesTokenCount != nil {
+		usage.CompletionTokens = int(*metadata.CandidatesTokenCount)
+	}
+	if metadata.CachedContentTokenCount != nil {
+		usage.CachedTokens = int(*metadata.CachedContentTokenCount)
+	}
+	return usage
+}
+
+// geminiContents converts messages into Gemini contents, returning the text
+// of the system messages separately.
+func geminiContents(messages []models.Message) ([]string, []*genai.Content) {
+	var system []string
+	contents := make([]*genai.Content, 0, len(messages))
+	for _, message := range messages {
+		switch message.Role {
+		case "system":
+			system = append(system, message.Text)
+		case "assistant":
+			contents = append(contents, &genai.Content{
+				Role:  "model",
+				Parts: geminiModelParts(message),
+			})
+		case "tool":
+			// Gemini expects the results of one turn's calls together in
+			// a single content.
+			part := &genai.Part{FunctionResponse: &genai.FunctionResponse{
+				ID:       message.ToolCallID,
+				Name:     message.ToolName,
+				Response: geminiToolResponse(message.Text),
+			}}
+			last := len(contents) - 1
+			if last >= 0 && contents[last].Role == "user" && contents[last].Parts[0].FunctionResponse != nil {
+				contents[last].Parts = append(contents[last].Parts, part)
+				continue
+			}
+			contents = append(contents, &genai.Content{Role: "user", Parts: []*genai.Part{part}})
+		default:
+			contents = append(contents, &genai.Content{
+				Role:  "user",
+				Parts: geminiParts(message),
+			})
+		}
+	}
+	return system, contents
+}
+
+// geminiParts sends attached images as inline data after the text.
+// Attachments without loaded bytes are left out.
+func geminiParts(message models.Message) []*genai.Part {
+	var parts []*genai.Part
+	if message.Text != "" {
+		parts = append(parts, &genai.Part{Text: message.Text})
+	}
+	for _, attachment := range message.Attachments {
+		if attachment.Kind != models.AttachmentKindImage || len(attachment.Data) == 0 {
+			continue
+		}
+		parts = append(parts, &genai.Part{
+			InlineData: &genai.Blob{MIMEType: attachment.MimeType, Data: attachment.Data},
+		})
+	}
+	if len(parts) == 0 {
+		parts = append(parts, &genai.Part{Text: message.Text})
+	}
+	return parts
+}
+
+func geminiModelParts(message models.Message) []*genai.Part {
+	var parts []*genai.Part
+	if message.Text != "" || len(message.ToolCalls) == 0 {
+		parts = append(parts, &genai.Part{Text: message.Text})
+	}
+	for _, call := range message.ToolCalls {
+		var args map[string]any
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			args = map[string]any{}
+		}
+		parts = append(parts, &genai.Part{FunctionCall: &genai.FunctionCall{
+			ID:   call.ID,
+			Name: call.Name,
+			Args: args,
+		}})
+	}
+	return parts
+}
+
+// geminiToolResponse wraps a tool result in the object Gemini expects. A
+// result that already is a JSON object is passed as is.
+func geminiToolResponse(text string) map[string]any {
+	var response map[string]any
+	if err := json.Unmarshal([]byte(text), &response); err == nil && response != nil {
+		return response
+	}
+	return map[string]any{"output": text}
+}
+
+// geminiToolCall converts a function call. Gemini does not always assign
+// call IDs, so one is generated to pair the call with its result.
+func geminiToolCall(call *genai.FunctionCall) models.ToolCall {
+	id := call.ID
+	if id == "" {
+		id = idgen.GenerateWithPrefix("call")
+	}
+	arguments, err := json.Marshal(call.Args)
+	if err != nil || call.Args == nil {
+		arguments = []byte("{}")
+	}
+	return models.ToolCall{ID: id, Name: call.Name, Arguments: string(arguments)}
+}
+
+func geminiTools(tools []models.ToolDefinition) ([]*genai.Tool, error) {
+	declarations := make([]*genai.FunctionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		declaration := &genai.FunctionDeclaration{Name: tool.Name, Description: tool.Description}
+		if len(tool.Parameters) > 0 {
+			schema, err := geminiSchema(tool.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameters for tool %s: %w", tool.Name, err)
+			}
+			declaration.Parameters = schema
+		}
+		declarations = append(declarations, declaration)
+	}
+	return []*genai.Tool{{FunctionDeclarations: declarations}}, nil
+}
+
+// geminiSchema converts a JSON Schema into Gemini's schema, which uses the
+// same keywords except for upper case type names, a nullable flag instead of
+// a "null" type, and string enums. Keywords Gemini does not know are dropped.
+func geminiSchema(raw json.RawMessage) (*genai.Schema, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, err
+	}
+	converted, err := json.Marshal(convertSchema(schema))
+	if err != nil {
+		return nil, err
+	}
+	var result genai.Schema
+	if err := json.Unmarshal(converted, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func convertSchema(schema map[string]any) map[string]any {
+	converted := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "type":
+			switch t := value.(type) {
+			case string:
+				converted["type"] = strings.ToUpper(t)
+			case []any:
+				for _, item := range t {
+					if name, ok := item.(string); ok {
+						if name == "null" {
+							converted["nullable"] = true
+						} else {
+							converted["type"] = strings.ToUpper(name)
+						}
+					}
+				}
+			}
+		case "properties":
+			if properties, ok := value.(map[string]any); ok {
+				result := make(map[string]any, len(properties))
+				for name, property := range properties {
+					if child, ok := property.(map[string]any); ok {
+						result[name] = convertSchema(child)
+					}
+				}
+				converted[key] = result
+			}
+		case "items":
+			if child, ok := value.(map[string]any); ok {
+				converted[key] = convertSchema(child)
+			}
+		case "anyOf":
+			if options, ok := value.([]any); ok {
+				var result []any
+				for _, option := range options {
+					if child, ok := option.(map[string]any); ok {
+						result = append(result, convertSchema(child))
+					}
+				}
+				converted[key] = result
+			}
+		case "enum":
+			if values, ok := value.([]any); ok {
+				result := make([]string, 0, len(values))
+				for _, v := range values {
+					result = append(result, fmt.Sprint(v))
+				}
+				converted[key] = result
+			}
+		case "description", "required", "format", "nullable", "title", "pattern", "minimum", "maximum",
+			"minItems", "maxItems", "minLength", "maxLength", "minProperties", "maxProperties", "default", "example":
+			converted[key] = value
+		}
+	}
+	return converted
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const geminiAPIURL = "https://generativelanguage.googleapis.com/v1beta"
+
+type geminiEmbedRequest struct {
+	Model                string             `json:"model"`
+	Content              geminiEmbedContent `json:"content"`
+	OutputDimensionality int                `json:"outputDimensionality,omitempty"`
+}
+
+type geminiEmbedContent struct {
+	Parts []geminiEmbedPart `json:"parts"`
+}
+
+type geminiEmbedPart struct {
+	Text string `json:"text"`
+}
+
+type geminiBatchEmbedResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
+
+type geminiErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// CreateEmbeddings calls batchEmbedContents. Gemini does not report token
+// usage for embeddings, so the usage is left empty.
+func (r *GeminiRepository) CreateEmbeddings(ctx context.Context,
+	req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	model := "models/" + req.Model
+	requests := make([]geminiEmbedRequest, 0, len(req.Input))
+	for _, input := range req.Input {
+		requests = append(requests, geminiEmbedRequest{
+			Model:                model,
+			Content:              geminiEmbedContent{Parts: []geminiEmbedPart{{Text: input}}},
+			OutputDimensionality: req.Dimensions,
+		})
+	}
+	body, err := json.Marshal(map[string]any{"requests": requests})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:batchEmbedContents", r.baseURL, url.PathEscape(req.Model))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", r.apiKey)
+
+	resp, err := r.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("error creating embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 256<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading embeddings: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr geminiErrorResponse
+		if err := json.Unmarshal(data, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("error creating embeddings: %s (%s)", apiErr.Error.Message, apiErr.Error.Status)
+		}
+		return nil, fmt.Errorf("error creating embeddings: status %d", resp.StatusCode)
+	}
+
+	var batch geminiBatchEmbedResponse
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("error decoding embeddings: %w", err)
+	}
+	if len(batch.Embeddings) != len(req.Input) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(req.Input), len(batch.Embeddings))
+	}
+
+	result := &models.EmbeddingResponse{
+		Model: req.Model,
+		Data:  make([]models.Embedding, 0, len(batch.Embeddings)),
+	}
+	for i, embedding := range batch.Embeddings {
+		result.Data = append(result.Data, models.Embedding{Index: i, Embedding: embedding.Values})
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeminiRepository_CreateEmbeddings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models/text-embedding-004:batchEmbedContents", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("x-goog-api-key"))
+
+		var body struct {
+			Requests []geminiEmbedRequest `json:"requests"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.Requests, 2)
+		assert.Equal(t, "models/text-embedding-004", body.Requests[0].Model)
+		assert.Equal(t, "second", body.Requests[1].Content.Parts[0].Text)
+		assert.Equal(t, 3, body.Requests[0].OutputDimensionality)
+
+		w.Write([]byte(`{"embeddings":[{"values":[0.1,0.2,0.3]},{"values":[0.4,0.5,0.6]}]}`))
+	}))
+	defer server.Close()
+
+	repo := &GeminiRepository{apiKey: "secret", baseURL: server.URL, httpClient: server.Client()}
+	response, err := repo.CreateEmbeddings(context.Background(), &models.EmbeddingRequest{
+		Model: "text-embedding-004", Input: []string{"first", "second"}, Dimensions: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, 1, response.Data[1].Index)
+	assert.Equal(t, []float32{0.4, 0.5, 0.6}, response.Data[1].Embedding)
+}
+
+func TestGeminiRepository_CreateEmbeddingsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":400,"message":"model not found","status":"INVALID_ARGUMENT"}}`))
+	}))
+	defer server.Close()
+
+	repo := &GeminiRepository{apiKey: "secret", baseURL: server.URL, httpClient: server.Client()}
+	_, err := repo.CreateEmbeddings(context.Background(), &models.EmbeddingRequest{
+		Model: "nope", Input: []string{"first"},
+	})
+	assert.ErrorContains(t, err, "model not found")
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AssistantRepository struct {
+	db *mongo.Database
+}
+
+func NewAssistantRepository(db *mongo.Database) *AssistantRepository {
+	return &AssistantRepository{db: db}
+}
+
+func (r *AssistantRepository) CreateAssistant(ctx context.Context, assistant *models.Assistant) error {
+	_, err := r.db.Collection("assistants").InsertOne(ctx, assistant)
+	if err != nil {
+		return fmt.Errorf("failed to create assistant: %w", err)
+	}
+	return nil
+}
+
+func (r *AssistantRepository) GetAssistant(ctx context.Context, id string) (*models.Assistant, error) {
+	var assistant models.Assistant
+	err := r.db.Collection("assistants").FindOne(ctx, bson.M{"_id": id}).Decode(&assistant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("assistant not found")
+		}
+		return nil, fmt.Errorf("failed to get assistant: %w", err)
+	}
+	return &assistant, nil
+}
+
+// ListAssistants returns the user's own assistants together with the shared
+// ones.
+func (r *AssistantRepository) ListAssistants(ctx context.Context, userID string) ([]*models.Assistant, error) {
+	filter := bson.M{"$or": []bson.M{{"user": userID}, {"shared": true}}}
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.db.Collection("assistants").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing assistants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	assistants := []*models.Assistant{}
+	if err = cursor.All(ctx, &assistants); err != nil {
+		return nil, fmt.Errorf("error decoding assistants: %w", err)
+	}
+	return assistants, nil
+}
+
+func (r *AssistantRepository) UpdateAssistant(ctx context.Context, assistant *models.Assistant) error {
+	result, err := r.db.Collection("assistants").ReplaceOne(ctx, bson.M{"_id": assistant.ID}, assistant)
+	if err != nil {
+		return fmt.Errorf("error updating assistant: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("assistant not found")
+	}
+	return nil
+}
+
+func (r *AssistantRepository) DeleteAssistant(ctx context.Context, assistantID string) error {
+	result, err := r.db.Collection("assistants").DeleteOne(ctx, bson.M{"_id": assistantID})
+	if err != nil {
+		return fmt.Errorf("error deleting assistant: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("assistant not found")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AttachmentRepository keeps attachment bytes in the "attachments" GridFS
+// bucket. The attachment and the text extracted from a document are stored as
+// the file's metadata.
+type AttachmentRepository struct {
+	db *mongo.Database
+}
+
+type attachmentMetadata struct {
+	models.Attachment `bson:",inline"`
+	Text              string `bson:"text,omitempty"`
+}
+
+func (m attachmentMetadata) attachment() *models.Attachment {
+	attachment := m.Attachment
+	attachment.Text = m.Text
+	return &attachment
+}
+
+func NewAttachmentRepository(db *mongo.Database) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) bucket() (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(r.db, options.GridFSBucket().SetName("attachments"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment bucket: %w", err)
+	}
+	return bucket, nil
+}
+
+func (r *AttachmentRepository) SaveAttachment(ctx context.Context, attachment *models.Attachment, data []byte) error {
+	bucket, err := r.bucket()
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		bucket.SetWriteDeadline(deadline)
+	}
+
+	metadata := attachmentMetadata{Attachment: *attachment, Text: attachment.Text}
+	opts := options.GridFSUpload().SetMetadata(metadata)
+	if err := bucket.UploadFromStreamWithID(attachment.ID, attachment.Name, bytes.NewReader(data), opts); err != nil {
+		return fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, attachmentID string) (*models.Attachment, error) {
+	var file struct {
+		Metadata attachmentMetadata `bson:"metadata"`
+	}
+	err := r.db.Collection("attachments.files").FindOne(ctx, bson.M{"_id": attachmentID}).Decode(&file)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return file.Metadata.attachment(), nil
+}
+
+func (r *AttachmentRepository) ReadAttachment(ctx context.Context, attachmentID string) ([]byte, error) {
+	bucket, err := r.bucket()
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		bucket.SetReadDeadline(deadline)
+	}
+
+	var buf bytes.Buffer
+	if _, err := bucket.DownloadToStream(attachmentID, &buf); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ListAttachments returns the attachments of a chat, oldest first, without
+// their extracted text.
+func (r *AttachmentRepository) ListAttachments(ctx context.Context, chatID string) ([]*models.Attachment, error) {
+	opts := options.Find().
+		SetSort(bson.M{"metadata.created_at": 1}).
+		SetProjection(bson.M{"metadata.text": 0})
+	cursor, err := r.db.Collection("attachments.files").Find(ctx, bson.M{"metadata.chat_id": chatID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	attachments := []*models.Attachment{}
+	for cursor.Next(ctx) {
+		var file struct {
+			Metadata models.Attachment `bson:"metadata"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			return nil, fmt.Errorf("failed to decode attachment: %w", err)
+		}
+		attachments = append(attachments, &file.Metadata)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return attachments, nil
+}
@@ -3,12 +3,30 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var notDeleted = bson.M{"$exists": false}
+
+// bumpVersion is added to every chat write made on behalf of a user so
+// clients holding an older version see a conflict. Background bookkeeping
+// such as summaries and memory extraction leaves the version alone.
+var bumpVersion = bson.M{"version": 1}
+
+// backgroundChatFields are written without a version bump by the summary and
+// memory workers and by the trash, so UpdateChat leaves them as stored.
+var backgroundChatFields = []string{"_id", "summary", "memory_extracted_at", "memory_failures", "memory_retry_at", "deleted_at"}
+
+// optionalChatFields are left out of the document when empty, so UpdateChat
+// removes them when they were cleared.
+var optionalChatFields = []string{"folder_id", "tags", "assistant_id", "knowledge_base_ids", "forked_from", "members"}
+
 type ChatRepository struct {
 	db *mongo.Database
 }
@@ -25,20 +43,28 @@ func (r *ChatRepository) CreateChat(ctx context.Context, chat *models.Chat) erro
 	return nil
 }
 
-func (r *ChatRepository) DeleteChat(ctx context.Context, chatID string) error {
-	result, err := r.db.Collection("chats").DeleteOne(ctx, bson.M{"_id": chatID})
+// DeleteChat moves the chat to the trash and returns it. A non-zero version
+// restricts it to the chat still at that version, and
+// repository.ErrVersionConflict is returned when it was written in the
+// meantime.
+func (r *ChatRepository) DeleteChat(ctx context.Context, chatID string, version int64) (*models.Chat, error) {
+	filter := bson.M{"_id": chatID, "deleted_at": notDeleted}
+	if version != 0 {
+		filter["version"] = version
+	}
+	chat, err := r.findAndUpdate(ctx, filter, bson.M{"$set": bson.M{"deleted_at": time.Now()}, "$inc": bumpVersion}, options.After)
 	if err != nil {
-		return fmt.Errorf("error deleting chat: %w", err)
+		return nil, fmt.Errorf("error deleting chat: %w", err)
 	}
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("chat not found")
+	if chat == nil {
+		return nil, r.missingChat(ctx, chatID, version != 0)
 	}
-	return nil
+	return chat, nil
 }
 
 func (r *ChatRepository) GetChat(ctx context.Context, id string) (*models.Chat, error) {
 	var chat models.Chat
-	err := r.db.Collection("chats").FindOne(ctx, bson.M{"_id": id}).Decode(&chat)
+	err := r.db.Collection("chats").FindOne(ctx, bson.M{"_id": id, "deleted_at": notDeleted}).Decode(&chat)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("chat not found")
@@ -48,13 +74,468 @@ func (r *ChatRepository) GetChat(ctx context.Context, id string) (*models.Chat,
 	return &chat, nil
 }
 
-func (r *ChatRepository) UpdateChat(ctx context.Context, chat *models.Chat) error {
-	result, err := r.db.Collection("chats").ReplaceOne(ctx, bson.M{"_id": chat.ID}, chat)
+// UpdateChat writes the chat only if the stored version still matches
+// chat.Version, and increments it. It returns the chat as stored before the
+// write, or repository.ErrVersionConflict when the chat was written in the
+// meantime. Fields kept by background work are not overwritten.
+func (r *ChatRepository) UpdateChat(ctx context.Context, chat *models.Chat) (*models.Chat, error) {
+	filter := versionFilter(chat.ID, chat.Version)
+	chat.Version++
+	update, err := chatUpdate(chat)
 	if err != nil {
-		return fmt.Errorf("error updating chat: %w", err)
+		chat.Version--
+		return nil, fmt.Errorf("error updating chat: %w", err)
+	}
+	previous, err := r.findAndUpdate(ctx, filter, update, options.Before)
+	if err != nil {
+		chat.Version--
+		return nil, fmt.Errorf("error updating chat: %w", err)
+	}
+	if previous == nil {
+		chat.Version--
+		return nil, r.missingChat(ctx, chat.ID, true)
 	}
-	if result.MatchedCount == 0 {
+	return previous, nil
+}
+
+// findAndUpdate applies the update to the chat matching filter and returns
+// the chat as it was before or after the write, or nil when none matched.
+func (r *ChatRepository) findAndUpdate(ctx context.Context, filter bson.M, update bson.M,
+	returnDocument options.ReturnDocument) (*models.Chat, error) {
+	var chat models.Chat
+	opts := options.FindOneAndUpdate().SetReturnDocument(returnDocument)
+	err := r.db.Collection("chats").FindOneAndUpdate(ctx, filter, update, opts).Decode(&chat)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// chatUpdate sets every field of the chat except the background ones, and
+// unsets the optional fields it no longer has.
+func chatUpdate(chat *models.Chat) (bson.M, error) {
+	data, err := bson.Marshal(chat)
+	if err != nil {
+		return nil, err
+	}
+	set := bson.M{}
+	if err := bson.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	for _, field := range backgroundChatFields {
+		delete(set, field)
+	}
+	unset := bson.M{}
+	for _, field := range optionalChatFields {
+		if _, ok := set[field]; !ok {
+			unset[field] = ""
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
+
+// versionFilter matches the chat only while it is at the given version.
+func versionFilter(chatID string, version int64) bson.M {
+	filter := bson.M{"_id": chatID, "deleted_at": notDeleted, "version": version}
+	if version == 0 {
+		// Chats stored before versioning have no version field.
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	return filter
+}
+
+// missingChat explains why a write matched no chat: it was either written in
+// the meantime, when the write was conditional, or is gone.
+func (r *ChatRepository) missingChat(ctx context.Context, chatID string, conditional bool) error {
+	if conditional {
+		exists, err := r.db.Collection("chats").CountDocuments(ctx, bson.M{"_id": chatID, "deleted_at": notDeleted})
+		if err == nil && exists > 0 {
+			return repository.ErrVersionConflict
+		}
+	}
+	return fmt.Errorf("chat not found")
+}
+
+// UpdateChats applies the update to each of the user's chats and returns the
+// updated ones. Chats that are missing, trashed or at another version than
+// update.Version are skipped.
+func (r *ChatRepository) UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) ([]*models.Chat, error) {
+	set := bson.M{}
+	unset := bson.M{}
+	if update.Pinned != nil {
+		set["pinned"] = *update.Pinned
+	}
+	if update.Archived != nil {
+		set["archived"] = *update.Archived
+	}
+	if update.FolderID != nil {
+		if *update.FolderID == "" {
+			unset["folder_id"] = ""
+		} else {
+			set["folder_id"] = *update.FolderID
+		}
+	}
+	if update.AssistantID != nil {
+		if *update.AssistantID == "" {
+			unset["assistant_id"] = ""
+		} else {
+			set["assistant_id"] = *update.AssistantID
+		}
+	}
+	if update.Tags != nil {
+		set["tags"] = update.Tags
+	}
+	if update.KnowledgeBaseIDs != nil {
+		if len(update.KnowledgeBaseIDs) == 0 {
+			unset["knowledge_base_ids"] = ""
+		} else {
+			set["knowledge_base_ids"] = update.KnowledgeBaseIDs
+		}
+	}
+
+	doc := bson.M{}
+	if len(set) > 0 {
+		doc["$set"] = set
+	}
+	if len(unset) > 0 {
+		doc["$unset"] = unset
+	}
+	if len(update.AddTags) > 0 {
+		doc["$addToSet"] = bson.M{"tags": bson.M{"$each": update.AddTags}}
+	}
+	if len(update.RemoveTags) > 0 {
+		doc["$pull"] = bson.M{"tags": bson.M{"$in": update.RemoveTags}}
+	}
+	if len(doc) == 0 {
+		return nil, fmt.Errorf("no changes to apply")
+	}
+	doc["$inc"] = bumpVersion
+
+	chats, err := r.updateEach(ctx, userID, chatIDs, update.Version, doc)
+	if err != nil {
+		return chats, fmt.Errorf("error updating chats: %w", err)
+	}
+	return chats, nil
+}
+
+// DeleteChats moves the user's chats to the trash and returns the ones that
+// were moved.
+func (r *ChatRepository) DeleteChats(ctx context.Context, userID string, chatIDs []string) ([]*models.Chat, error) {
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now()}, "$inc": bumpVersion}
+	chats, err := r.updateEach(ctx, userID, chatIDs, 0, update)
+	if err != nil {
+		return chats, fmt.Errorf("error deleting chats: %w", err)
+	}
+	return chats, nil
+}
+
+// updateEach applies the update to the user's chats one at a time, so every
+// updated chat can be returned as written. A non-zero version restricts it to
+// chats at that version.
+func (r *ChatRepository) updateEach(ctx context.Context, userID string, chatIDs []string, version int64,
+	update bson.M) ([]*models.Chat, error) {
+	chats := []*models.Chat{}
+	for _, chatID := range chatIDs {
+		filter := bson.M{"_id": chatID, "user": userID, "deleted_at": notDeleted}
+		if version != 0 {
+			filter["version"] = version
+		}
+		chat, err := r.findAndUpdate(ctx, filter, update, options.After)
+		if err != nil {
+			return chats, err
+		}
+		if chat != nil {
+			chats = append(chats, chat)
+		}
+	}
+	return chats, nil
+}
+
+func (r *ChatRepository) ClearFolder(ctx context.Context, userID string, folderID string) error {
+	filter := bson.M{"user": userID, "folder_id": folderID, "deleted_at": notDeleted}
+	_, err := r.db.Collection("chats").UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"folder_id": ""}, "$inc": bumpVersion})
+	if err != nil {
+		return fmt.Errorf("error clearing folder from chats: %w", err)
+	}
+	return nil
+}
+
+// ClearAssistant detaches a deleted assistant from every chat that used it,
+// including trashed ones.
+func (r *ChatRepository) ClearAssistant(ctx context.Context, assistantID string) error {
+	_, err := r.db.Collection("chats").UpdateMany(ctx, bson.M{"assistant_id": assistantID}, bson.M{"$unset": bson.M{"assistant_id": ""}, "$inc": bumpVersion})
+	if err != nil {
+		return fmt.Errorf("error clearing assistant from chats: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatRepository) ListDeletedChats(ctx context.Context, userID string) ([]*models.UserChat, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{"user": userID, "deleted_at": bson.M{"$exists": true}},
+		},
+		{
+			"$project": bson.M{
+				"_id":        0,
+				"id":         "$_id",
+				"user":       "$user",
+				"chat_title": "$title",
+				"pinned":     "$pinned",
+				"archived":   "$archived",
+				"folder_id":  "$folder_id",
+				"tags":       "$tags",
+				"created_at": "$created_at",
+				"updated_at": "$updated_at",
+				"deleted_at": "$deleted_at",
+			},
+		},
+		{
+			"$sort": bson.M{"deleted_at": -1},
+		},
+	}
+
+	cursor, err := r.db.Collection("chats").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching deleted chats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	chats := []*models.UserChat{}
+	if err = cursor.All(ctx, &chats); err != nil {
+		return nil, fmt.Errorf("error decoding deleted chats: %w", err)
+	}
+	return chats, nil
+}
+
+// RestoreChat takes the chat out of the trash and returns it.
+func (r *ChatRepository) RestoreChat(ctx context.Context, userID string, chatID string) (*models.Chat, error) {
+	filter := bson.M{"_id": chatID, "user": userID, "deleted_at": bson.M{"$exists": true}}
+	chat, err := r.findAndUpdate(ctx, filter, bson.M{"$unset": bson.M{"deleted_at": ""}, "$inc": bumpVersion}, options.After)
+	if err != nil {
+		return nil, fmt.Errorf("error restoring chat: %w", err)
+	}
+	if chat == nil {
+		return nil, fmt.Errorf("chat not found")
+	}
+	if chat.FolderID == "" {
+		return chat, nil
+	}
+
+	// ClearFolder skips trashed chats, so the folder may be gone by now.
+	folders, err := r.db.Collection("folders").CountDocuments(ctx, bson.M{"_id": chat.FolderID, "user": userID})
+	if err != nil {
+		return nil, fmt.Errorf("error restoring chat: %w", err)
+	}
+	if folders == 0 {
+		_, err = r.db.Collection("chats").UpdateOne(ctx, bson.M{"_id": chatID, "folder_id": chat.FolderID},
+			bson.M{"$unset": bson.M{"folder_id": ""}})
+		if err != nil {
+			return nil, fmt.Errorf("error restoring chat: %w", err)
+		}
+		chat.FolderID = ""
+	}
+	return chat, nil
+}
+
+func (r *ChatRepository) PurgeChat(ctx context.Context, userID string, chatID string) error {
+	filter := bson.M{"_id": chatID, "user": userID, "deleted_at": bson.M{"$exists": true}}
+	result, err := r.db.Collection("chats").DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error purging chat: %w", err)
+	}
+	if result.DeletedCount == 0 {
 		return fmt.Errorf("chat not found")
 	}
+	return r.purgeChatData(ctx, chatID)
+}
+
+// PurgeDeletedChats purges the chats one by one, so the data of a chat
+// restored in the meantime is left alone.
+func (r *ChatRepository) PurgeDeletedChats(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lte": before}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.db.Collection("chats").Find(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("error finding deleted chats: %w", err)
+	}
+	var chats []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &chats); err != nil {
+		return 0, fmt.Errorf("error decoding deleted chats: %w", err)
+	}
+
+	var purged int64
+	for _, chat := range chats {
+		result, err := r.db.Collection("chats").DeleteOne(ctx, bson.M{"_id": chat.ID, "deleted_at": bson.M{"$lte": before}})
+		if err != nil {
+			return purged, fmt.Errorf("error purging deleted chats: %w", err)
+		}
+		if result.DeletedCount == 0 {
+			continue
+		}
+		purged++
+		if err := r.purgeChatData(ctx, chat.ID); err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// purgeChatData deletes what is stored about a chat outside of it once the
+// chat itself is gone.
+func (r *ChatRepository) purgeChatData(ctx context.Context, chatID string) error {
+	if _, err := r.db.Collection("shares").DeleteMany(ctx, bson.M{"chat_id": chatID}); err != nil {
+		return fmt.Errorf("error deleting shares of chat: %w", err)
+	}
+	if _, err := r.db.Collection("tool_runs").DeleteMany(ctx, bson.M{"chat_id": chatID}); err != nil {
+		return fmt.Errorf("error deleting tool runs of chat: %w", err)
+	}
+	// Copies held by the provider expire on their own.
+	if _, err := r.db.Collection("prompt_caches").DeleteMany(ctx, bson.M{"scope": "chat:" + chatID}); err != nil {
+		return fmt.Errorf("error deleting prompt caches of chat: %w", err)
+	}
+
+	// Attachments live in the "attachments" GridFS bucket.
+	fileIDs, err := r.db.Collection("attachments.files").Distinct(ctx, "_id", bson.M{"metadata.chat_id": chatID})
+	if err != nil {
+		return fmt.Errorf("error listing attachments of chat: %w", err)
+	}
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	if _, err := r.db.Collection("attachments.chunks").DeleteMany(ctx, bson.M{"files_id": bson.M{"$in": fileIDs}}); err != nil {
+		return fmt.Errorf("error deleting attachment chunks of chat: %w", err)
+	}
+	if _, err := r.db.Collection("attachments.files").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": fileIDs}}); err != nil {
+		return fmt.Errorf("error deleting attachments of chat: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatRepository) ForEachUserChat(ctx context.Context, userID string, fn func(*models.Chat) error) error {
+	filter := bson.M{"user": userID, "deleted_at": notDeleted}
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.db.Collection("chats").Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("error fetching user chats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var chat models.Chat
+		if err := cursor.Decode(&chat); err != nil {
+			return fmt.Errorf("error decoding chat: %w", err)
+		}
+		if err := fn(&chat); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("error iterating user chats: %w", err)
+	}
+	return nil
+}
+
+// SetSummary stores the summary without touching the rest of the chat or its
+// version, so it can run in the background alongside regular updates, and
+// returns the updated chat. A nil summary removes it.
+func (r *ChatRepository) SetSummary(ctx context.Context, chatID string, summary *models.Summary) (*models.Chat, error) {
+	update := bson.M{"$unset": bson.M{"summary": ""}}
+	if summary != nil {
+		update = bson.M{"$set": bson.M{"summary": summary}}
+	}
+
+	chat, err := r.findAndUpdate(ctx, bson.M{"_id": chatID, "deleted_at": notDeleted}, update, options.After)
+	if err != nil {
+		return nil, fmt.Errorf("error updating chat summary: %w", err)
+	}
+	if chat == nil {
+		return nil, fmt.Errorf("chat not found")
+	}
+	return chat, nil
+}
+
+// ListChatsPendingMemory returns chats that have been idle since the given
+// time and received messages after their last memory extraction. Chats whose
+// last attempt failed are left out until their retry time.
+func (r *ChatRepository) ListChatsPendingMemory(ctx context.Context, idleSince time.Time, limit int) ([]*models.Chat, error) {
+	filter := bson.M{
+		"deleted_at": notDeleted,
+		"updated_at": bson.M{"$lte": idleSince},
+		"messages.0": bson.M{"$exists": true},
+		"$and": []bson.M{
+			{"$or": []bson.M{
+				{"memory_extracted_at": bson.M{"$exists": false}},
+				{"$expr": bson.M{"$lt": bson.A{"$memory_extracted_at", "$updated_at"}}},
+			}},
+			{"$or": []bson.M{
+				{"memory_retry_at": bson.M{"$exists": false}},
+				{"memory_retry_at": bson.M{"$lte": time.Now()}},
+			}},
+		},
+	}
+	opts := options.Find().SetSort(bson.M{"updated_at": 1}).SetLimit(int64(limit))
+	cursor, err := r.db.Collection("chats").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing chats pending memory extraction: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	chats := []*models.Chat{}
+	if err = cursor.All(ctx, &chats); err != nil {
+		return nil, fmt.Errorf("error decoding chats: %w", err)
+	}
+	return chats, nil
+}
+
+func (r *ChatRepository) SetMemoryExtractedAt(ctx context.Context, chatID string, at time.Time) error {
+	_, err := r.db.Collection("chats").UpdateOne(ctx, bson.M{"_id": chatID, "deleted_at": notDeleted}, bson.M{
+		"$set":   bson.M{"memory_extracted_at": at},
+		"$unset": bson.M{"memory_failures": "", "memory_retry_at": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("error updating chat memory extraction time: %w", err)
+	}
+	return nil
+}
+
+// RecordMemoryFailure counts a failed extraction attempt and holds the chat
+// back until retryAt.
+func (r *ChatRepository) RecordMemoryFailure(ctx context.Context, chatID string, retryAt time.Time) error {
+	_, err := r.db.Collection("chats").UpdateOne(ctx, bson.M{"_id": chatID, "deleted_at": notDeleted}, bson.M{
+		"$set": bson.M{"memory_retry_at": retryAt},
+		"$inc": bson.M{"memory_failures": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("error recording chat memory extraction failure: %w", err)
+	}
 	return nil
 }
+
+// SetMembers replaces the members of the chat only if it is still at the
+// given version and returns the chat as stored before, or
+// repository.ErrVersionConflict otherwise.
+func (r *ChatRepository) SetMembers(ctx context.Context, chatID string, version int64, members []models.ChatMember) (*models.Chat, error) {
+	update := bson.M{"$unset": bson.M{"members": ""}, "$inc": bumpVersion}
+	if len(members) > 0 {
+		update = bson.M{"$set": bson.M{"members": members}, "$inc": bumpVersion}
+	}
+
+	previous, err := r.findAndUpdate(ctx, versionFilter(chatID, version), update, options.Before)
+	if err != nil {
+		return nil, fmt.Errorf("error updating chat members: %w", err)
+	}
+	if previous == nil {
+		return nil, r.missingChat(ctx, chatID, true)
+	}
+	return previous, nil
+}
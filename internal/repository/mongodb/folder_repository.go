@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FolderRepository struct {
+	db *mongo.Database
+}
+
+func NewFolderRepository(db *mongo.Database) *FolderRepository {
+	return &FolderRepository{db: db}
+}
+
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *models.Folder) error {
+	_, err := r.db.Collection("folders").InsertOne(ctx, folder)
+	if err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+	return nil
+}
+
+func (r *FolderRepository) GetFolder(ctx context.Context, id string) (*models.Folder, error) {
+	var folder models.Folder
+	err := r.db.Collection("folders").FindOne(ctx, bson.M{"_id": id}).Decode(&folder)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("folder not found")
+		}
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+	return &folder, nil
+}
+
+func (r *FolderRepository) ListFolders(ctx context.Context, userID string) ([]*models.Folder, error) {
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.db.Collection("folders").Find(ctx, bson.M{"user": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing folders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	folders := []*models.Folder{}
+	if err = cursor.All(ctx, &folders); err != nil {
+		return nil, fmt.Errorf("error decoding folders: %w", err)
+	}
+	return folders, nil
+}
+
+func (r *FolderRepository) UpdateFolder(ctx context.Context, folder *models.Folder) error {
+	result, err := r.db.Collection("folders").ReplaceOne(ctx, bson.M{"_id": folder.ID}, folder)
+	if err != nil {
+		return fmt.Errorf("error updating folder: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("folder not found")
+	}
+	return nil
+}
+
+func (r *FolderRepository) DeleteFolder(ctx context.Context, folderID string) error {
+	result, err := r.db.Collection("folders").DeleteOne(ctx, bson.M{"_id": folderID})
+	if err != nil {
+		return fmt.Errorf("error deleting folder: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("folder not found")
+	}
+	return nil
+}
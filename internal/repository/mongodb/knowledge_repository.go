@@ -0,0 +1,262 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// KnowledgeRepository stores knowledge bases, their documents and the
+// embedded chunks of those documents in separate collections. Knowledge bases
+// and documents are scoped by user like memories.
+type KnowledgeRepository struct {
+	db *mongo.Database
+}
+
+func NewKnowledgeRepository(db *mongo.Database) *KnowledgeRepository {
+	return &KnowledgeRepository{db: db}
+}
+
+func (r *KnowledgeRepository) CreateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error {
+	_, err := r.db.Collection("knowledge_bases").InsertOne(ctx, knowledgeBase)
+	if err != nil {
+		return fmt.Errorf("failed to create knowledge base: %w", err)
+	}
+	return nil
+}
+
+func (r *KnowledgeRepository) GetKnowledgeBase(ctx context.Context, userID string, knowledgeBaseID string) (*models.KnowledgeBase, error) {
+	var knowledgeBase models.KnowledgeBase
+	filter := bson.M{"_id": knowledgeBaseID, "user": userID}
+	err := r.db.Collection("knowledge_bases").FindOne(ctx, filter).Decode(&knowledgeBase)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("knowledge base not found")
+		}
+		return nil, fmt.Errorf("failed to get knowledge base: %w", err)
+	}
+	return &knowledgeBase, nil
+}
+
+func (r *KnowledgeRepository) ListKnowledgeBases(ctx context.Context, userID string) ([]*models.KnowledgeBase, error) {
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.db.Collection("knowledge_bases").Find(ctx, bson.M{"user": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing knowledge bases: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	knowledgeBases := []*models.KnowledgeBase{}
+	if err = cursor.All(ctx, &knowledgeBases); err != nil {
+		return nil, fmt.Errorf("error decoding knowledge bases: %w", err)
+	}
+	return knowledgeBases, nil
+}
+
+func (r *KnowledgeRepository) UpdateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error {
+	filter := bson.M{"_id": knowledgeBase.ID, "user": knowledgeBase.User}
+	result, err := r.db.Collection("knowledge_bases").ReplaceOne(ctx, filter, knowledgeBase)
+	if err != nil {
+		return fmt.Errorf("error updating knowledge base: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("knowledge base not found")
+	}
+	return nil
+}
+
+// DeleteKnowledgeBase removes the knowledge base with its documents and
+// chunks.
+func (r *KnowledgeRepository) DeleteKnowledgeBase(ctx context.Context, userID string, knowledgeBaseID string) error {
+	result, err := r.db.Collection("knowledge_bases").DeleteOne(ctx, bson.M{"_id": knowledgeBaseID, "user": userID})
+	if err != nil {
+		return fmt.Errorf("error deleting knowledge base: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("knowledge base not found")
+	}
+
+	filter := bson.M{"knowledge_base_id": knowledgeBaseID}
+	if _, err := r.db.Collection("knowledge_documents").DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("error deleting knowledge base documents: %w", err)
+	}
+	if _, err := r.db.Collection("knowledge_chunks").DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("error deleting knowledge base chunks: %w", err)
+	}
+	return nil
+}
+
+func (r *KnowledgeRepository) CreateDocument(ctx context.Context, document *models.KnowledgeDocument) error {
+	_, err := r.db.Collection("knowledge_documents").InsertOne(ctx, document)
+	if err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+	return nil
+}
+
+// GetDocument returns the document with its text.
+func (r *KnowledgeRepository) GetDocument(ctx context.Context, userID string, documentID string) (*models.KnowledgeDocument, error) {
+	var document models.KnowledgeDocument
+	filter := bson.M{"_id": documentID, "user": userID}
+	err := r.db.Collection("knowledge_documents").FindOne(ctx, filter).Decode(&document)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	return &document, nil
+}
+
+// ListDocuments returns the knowledge base's documents without their text.
+func (r *KnowledgeRepository) ListDocuments(ctx context.Context, userID string, knowledgeBaseID string) ([]*models.KnowledgeDocument, error) {
+	filter := bson.M{"user": userID, "knowledge_base_id": knowledgeBaseID}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetProjection(bson.M{"text": 0})
+	cursor, err := r.db.Collection("knowledge_documents").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := []*models.KnowledgeDocument{}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("error decoding documents: %w", err)
+	}
+	return documents, nil
+}
+
+// UpdateDocument stores the document's indexing state. Its text is not
+// rewritten.
+func (r *KnowledgeRepository) UpdateDocument(ctx context.Context, document *models.KnowledgeDocument) error {
+	filter := bson.M{"_id": document.ID, "user": document.User}
+	set := bson.M{
+		"status":      document.Status,
+		"chunk_count": document.ChunkCount,
+	}
+	unset := bson.M{}
+	if document.Error != "" {
+		set["error"] = document.Error
+	} else {
+		unset["error"] = ""
+	}
+	if document.IndexedAt != nil {
+		set["indexed_at"] = document.IndexedAt
+	}
+	if document.IndexingAt != nil {
+		set["indexing_at"] = document.IndexingAt
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := r.db.Collection("knowledge_documents").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("error updating document: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// StartIndexing moves the document to indexing in a single conditional
+// update. A document already indexing is only taken over when its run started
+// before staleBefore; otherwise repository.ErrDocumentIndexing is returned.
+func (r *KnowledgeRepository) StartIndexing(ctx context.Context, userID string, documentID string,
+	startedAt time.Time, staleBefore time.Time) error {
+	collection := r.db.Collection("knowledge_documents")
+	filter := bson.M{
+		"_id":  documentID,
+		"user": userID,
+		"$or": bson.A{
+			bson.M{"status": bson.M{"$ne": models.DocumentStatusIndexing}},
+			bson.M{"indexing_at": bson.M{"$exists": false}},
+			bson.M{"indexing_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+	update := bson.M{
+		"$set":   bson.M{"status": models.DocumentStatusIndexing, "indexing_at": startedAt},
+		"$unset": bson.M{"error": ""},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("error updating document: %w", err)
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": documentID, "user": userID})
+	if err != nil {
+		return fmt.Errorf("error updating document: %w", err)
+	}
+	if count == 0 {
+		return repository.ErrDocumentNotFound
+	}
+	return repository.ErrDocumentIndexing
+}
+
+func (r *KnowledgeRepository) DeleteDocument(ctx context.Context, userID string, documentID string) error {
+	result, err := r.db.Collection("knowledge_documents").DeleteOne(ctx, bson.M{"_id": documentID, "user": userID})
+	if err != nil {
+		return fmt.Errorf("error deleting document: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrDocumentNotFound
+	}
+	if _, err := r.db.Collection("knowledge_chunks").DeleteMany(ctx, bson.M{"document_id": documentID}); err != nil {
+		return fmt.Errorf("error deleting document chunks: %w", err)
+	}
+	return nil
+}
+
+// ReplaceChunks swaps a document's chunks for new ones. An empty list only
+// removes the old chunks.
+func (r *KnowledgeRepository) ReplaceChunks(ctx context.Context, documentID string, chunks []*models.KnowledgeChunk) error {
+	collection := r.db.Collection("knowledge_chunks")
+	if _, err := collection.DeleteMany(ctx, bson.M{"document_id": documentID}); err != nil {
+		return fmt.Errorf("error deleting document chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	docs := make([]any, 0, len(chunks))
+	for _, chunk := range chunks {
+		docs = append(docs, chunk)
+	}
+	if _, err := collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("error storing document chunks: %w", err)
+	}
+	return nil
+}
+
+func (r *KnowledgeRepository) ForEachChunk(ctx context.Context, knowledgeBaseIDs []string, fn func(*models.KnowledgeChunk) error) error {
+	filter := bson.M{"knowledge_base_id": bson.M{"$in": knowledgeBaseIDs}}
+	cursor, err := r.db.Collection("knowledge_chunks").Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error fetching chunks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var chunk models.KnowledgeChunk
+		if err := cursor.Decode(&chunk); err != nil {
+			return fmt.Errorf("error decoding chunk: %w", err)
+		}
+		if err := fn(&chunk); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("error iterating chunks: %w", err)
+	}
+	return nil
+}
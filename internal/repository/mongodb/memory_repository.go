@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MemoryRepository scopes every query by user so a memory can never be read
+// or changed through another user's account.
+type MemoryRepository struct {
+	db *mongo.Database
+}
+
+func NewMemoryRepository(db *mongo.Database) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+func (r *MemoryRepository) CreateMemory(ctx context.Context, memory *models.Memory) error {
+	_, err := r.db.Collection("memories").InsertOne(ctx, memory)
+	if err != nil {
+		return fmt.Errorf("failed to create memory: %w", err)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetMemory(ctx context.Context, userID string, memoryID string) (*models.Memory, error) {
+	var memory models.Memory
+	err := r.db.Collection("memories").FindOne(ctx, bson.M{"_id": memoryID, "user": userID}).Decode(&memory)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("memory not found")
+		}
+		return nil, fmt.Errorf("failed to get memory: %w", err)
+	}
+	return &memory, nil
+}
+
+func (r *MemoryRepository) ListMemories(ctx context.Context, userID string, enabledOnly bool) ([]*models.Memory, error) {
+	filter := bson.M{"user": userID}
+	if enabledOnly {
+		filter["enabled"] = true
+	}
+	opts := options.Find().SetSort(bson.M{"updated_at": -1})
+	cursor, err := r.db.Collection("memories").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing memories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	memories := []*models.Memory{}
+	if err = cursor.All(ctx, &memories); err != nil {
+		return nil, fmt.Errorf("error decoding memories: %w", err)
+	}
+	return memories, nil
+}
+
+func (r *MemoryRepository) UpdateMemory(ctx context.Context, memory *models.Memory) error {
+	filter := bson.M{"_id": memory.ID, "user": memory.User}
+	result, err := r.db.Collection("memories").ReplaceOne(ctx, filter, memory)
+	if err != nil {
+		return fmt.Errorf("error updating memory: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("memory not found")
+	}
+	return nil
+}
+
+func (r *MemoryRepository) DeleteMemory(ctx context.Context, userID string, memoryID string) error {
+	result, err := r.db.Collection("memories").DeleteOne(ctx, bson.M{"_id": memoryID, "user": userID})
+	if err != nil {
+		return fmt.Errorf("error deleting memory: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("memory not found")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PromptCacheRepository keeps one prompt cache per scope, platform and
+// model, which a unique index enforces.
+type PromptCacheRepository struct {
+	db *mongo.Database
+}
+
+func NewPromptCacheRepository(db *mongo.Database) *PromptCacheRepository {
+	return &PromptCacheRepository{db: db}
+}
+
+func (r *PromptCacheRepository) CreatePromptCache(ctx context.Context, cache *models.PromptCache) error {
+	_, err := r.db.Collection("prompt_caches").InsertOne(ctx, cache)
+	if err != nil {
+		return fmt.Errorf("failed to create prompt cache: %w", err)
+	}
+	return nil
+}
+
+func (r *PromptCacheRepository) GetPromptCache(ctx context.Context, scope string, platform string,
+	model string) (*models.PromptCache, error) {
+	var cache models.PromptCache
+	filter := bson.M{"scope": scope, "platform": platform, "model": model}
+	err := r.db.Collection("prompt_caches").FindOne(ctx, filter).Decode(&cache)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrPromptCacheNotFound
+		}
+		return nil, fmt.Errorf("failed to get prompt cache: %w", err)
+	}
+	return &cache, nil
+}
+
+func (r *PromptCacheRepository) UpdatePromptCache(ctx context.Context, cache *models.PromptCache) error {
+	result, err := r.db.Collection("prompt_caches").ReplaceOne(ctx, bson.M{"_id": cache.ID}, cache)
+	if err != nil {
+		return fmt.Errorf("failed to update prompt cache: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrPromptCacheNotFound
+	}
+	return nil
+}
+
+func (r *PromptCacheRepository) DeletePromptCache(ctx context.Context, cacheID string) error {
+	_, err := r.db.Collection("prompt_caches").DeleteOne(ctx, bson.M{"_id": cacheID})
+	if err != nil {
+		return fmt.Errorf("failed to delete prompt cache: %w", err)
+	}
+	return nil
+}
+
+func (r *PromptCacheRepository) ListExpiredPromptCaches(ctx context.Context,
+	before time.Time) ([]*models.PromptCache, error) {
+	cursor, err := r.db.Collection("prompt_caches").Find(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired prompt caches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	caches := []*models.PromptCache{}
+	if err := cursor.All(ctx, &caches); err != nil {
+		return nil, fmt.Errorf("failed to decode prompt caches: %w", err)
+	}
+	return caches, nil
+}
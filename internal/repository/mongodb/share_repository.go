@@ -0,0 +1,75 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ShareRepository struct {
+	db *mongo.Database
+}
+
+func NewShareRepository(db *mongo.Database) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+func (r *ShareRepository) CreateShare(ctx context.Context, share *models.Share) error {
+	_, err := r.db.Collection("shares").InsertOne(ctx, share)
+	if err != nil {
+		return fmt.Errorf("failed to create share: %w", err)
+	}
+	return nil
+}
+
+func (r *ShareRepository) GetShare(ctx context.Context, shareID string) (*models.Share, error) {
+	return r.findOne(ctx, bson.M{"_id": shareID})
+}
+
+func (r *ShareRepository) GetShareByToken(ctx context.Context, token string) (*models.Share, error) {
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *ShareRepository) findOne(ctx context.Context, filter bson.M) (*models.Share, error) {
+	var share models.Share
+	err := r.db.Collection("shares").FindOne(ctx, filter).Decode(&share)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("share not found")
+		}
+		return nil, fmt.Errorf("failed to get share: %w", err)
+	}
+	return &share, nil
+}
+
+func (r *ShareRepository) ListShares(ctx context.Context, chatID string) ([]*models.Share, error) {
+	opts := options.Find().
+		SetProjection(bson.M{"messages": 0}).
+		SetSort(bson.M{"created_at": -1})
+	cursor, err := r.db.Collection("shares").Find(ctx, bson.M{"chat_id": chatID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shares: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	shares := []*models.Share{}
+	if err = cursor.All(ctx, &shares); err != nil {
+		return nil, fmt.Errorf("error decoding shares: %w", err)
+	}
+	return shares, nil
+}
+
+func (r *ShareRepository) DeleteShare(ctx context.Context, shareID string) error {
+	result, err := r.db.Collection("shares").DeleteOne(ctx, bson.M{"_id": shareID})
+	if err != nil {
+		return fmt.Errorf("error deleting share: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("share not found")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TemplateRepository struct {
+	db *mongo.Database
+}
+
+func NewTemplateRepository(db *mongo.Database) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) CreateTemplate(ctx context.Context, template *models.PromptTemplate) error {
+	_, err := r.db.Collection("templates").InsertOne(ctx, template)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	return nil
+}
+
+func (r *TemplateRepository) GetTemplate(ctx context.Context, id string) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	err := r.db.Collection("templates").FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return &template, nil
+}
+
+// ListTemplates returns the user's own templates together with the shared
+// ones.
+func (r *TemplateRepository) ListTemplates(ctx context.Context, userID string) ([]*models.PromptTemplate, error) {
+	filter := bson.M{"$or": []bson.M{{"user": userID}, {"shared": true}}}
+	opts := options.Find().SetSort(bson.D{{Key: "shared", Value: -1}, {Key: "name", Value: 1}})
+	cursor, err := r.db.Collection("templates").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []*models.PromptTemplate{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("error decoding templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *TemplateRepository) UpdateTemplate(ctx context.Context, template *models.PromptTemplate) error {
+	result, err := r.db.Collection("templates").ReplaceOne(ctx, bson.M{"_id": template.ID}, template)
+	if err != nil {
+		return fmt.Errorf("error updating template: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("template not found")
+	}
+	return nil
+}
+
+func (r *TemplateRepository) DeleteTemplate(ctx context.Context, templateID string) error {
+	result, err := r.db.Collection("templates").DeleteOne(ctx, bson.M{"_id": templateID})
+	if err != nil {
+		return fmt.Errorf("error deleting template: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("template not found")
+	}
+
+	_, err = r.db.Collection("template_versions").DeleteMany(ctx, bson.M{"template_id": templateID})
+	if err != nil {
+		return fmt.Errorf("error deleting template versions: %w", err)
+	}
+	return nil
+}
+
+func (r *TemplateRepository) CreateTemplateVersion(ctx context.Context, version *models.PromptTemplateVersion) error {
+	_, err := r.db.Collection("template_versions").InsertOne(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to create template version: %w", err)
+	}
+	return nil
+}
+
+func (r *TemplateRepository) GetTemplateVersion(ctx context.Context, templateID string, version int) (*models.PromptTemplateVersion, error) {
+	var templateVersion models.PromptTemplateVersion
+	filter := bson.M{"template_id": templateID, "version": version}
+	err := r.db.Collection("template_versions").FindOne(ctx, filter).Decode(&templateVersion)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("template version not found")
+		}
+		return nil, fmt.Errorf("failed to get template version: %w", err)
+	}
+	return &templateVersion, nil
+}
+
+func (r *TemplateRepository) ListTemplateVersions(ctx context.Context, templateID string) ([]*models.PromptTemplateVersion, error) {
+	opts := options.Find().SetSort(bson.M{"version": -1})
+	cursor, err := r.db.Collection("template_versions").Find(ctx, bson.M{"template_id": templateID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing template versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := []*models.PromptTemplateVersion{}
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("error decoding template versions: %w", err)
+	}
+	return versions, nil
+}
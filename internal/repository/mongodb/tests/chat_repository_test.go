@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
 	"github.com/lutefd/ai-router-go/internal/repository/mongodb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,3 +108,270 @@ func TestChatRepository_GetChat(t *testing.T) {
 		})
 	}
 }
+
+func TestChatRepository_UpdateChats(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewChatRepository(conn.DB)
+	ctx := context.Background()
+
+	for _, chat := range []*models.Chat{
+		{ID: "chat-1", User: "user-1", Title: "First", Tags: []string{"go"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "chat-2", User: "user-1", Title: "Second", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "chat-3", User: "user-2", Title: "Other", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	} {
+		require.NoError(t, repo.CreateChat(ctx, chat))
+	}
+
+	archived := true
+	updated, err := repo.UpdateChats(ctx, "user-1", []string{"chat-1", "chat-2", "chat-3"},
+		models.ChatUpdate{Archived: &archived, AddTags: []string{"work"}})
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	assert.True(t, updated[0].Archived, "updated chats are returned as written")
+
+	chat, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.True(t, chat.Archived)
+	assert.ElementsMatch(t, []string{"go", "work"}, chat.Tags)
+
+	other, err := repo.GetChat(ctx, "chat-3")
+	require.NoError(t, err)
+	assert.False(t, other.Archived)
+
+	updated, err = repo.UpdateChats(ctx, "user-1", []string{"chat-1"}, models.ChatUpdate{RemoveTags: []string{"go"}})
+	require.NoError(t, err)
+	assert.Len(t, updated, 1)
+
+	chat, err = repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work"}, chat.Tags)
+
+	_, err = repo.UpdateChats(ctx, "user-1", []string{"chat-1"}, models.ChatUpdate{})
+	assert.Error(t, err)
+}
+
+func TestChatRepository_DeleteChats(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewChatRepository(conn.DB)
+	ctx := context.Background()
+
+	for _, chat := range []*models.Chat{
+		{ID: "chat-1", User: "user-1", FolderID: "folder-1", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: "chat-2", User: "user-1", FolderID: "folder-1", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	} {
+		require.NoError(t, repo.CreateChat(ctx, chat))
+	}
+
+	deleted, err := repo.DeleteChats(ctx, "user-1", []string{"chat-1", "missing"})
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.NotNil(t, deleted[0].DeletedAt)
+
+	require.NoError(t, repo.ClearFolder(ctx, "user-1", "folder-1"))
+	var trashed models.Chat
+	require.NoError(t, conn.DB.Collection("chats").FindOne(ctx, bson.M{"_id": "chat-1"}).Decode(&trashed))
+	assert.Equal(t, "folder-1", trashed.FolderID, "trashed chats keep their folder")
+
+	chat, err := repo.GetChat(ctx, "chat-2")
+	require.NoError(t, err)
+	assert.Empty(t, chat.FolderID)
+
+	restored, err := repo.RestoreChat(ctx, "user-1", "chat-1")
+	require.NoError(t, err)
+	assert.Empty(t, restored.FolderID)
+	chat, err = repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.Empty(t, chat.FolderID, "restored chats leave a deleted folder")
+}
+
+func TestChatRepository_Trash(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewChatRepository(conn.DB)
+	ctx := context.Background()
+
+	chat := &models.Chat{ID: "chat-1", User: "user-1", Title: "Trash me", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, repo.CreateChat(ctx, chat))
+
+	trashedChat, err := repo.DeleteChat(ctx, "chat-1", 0)
+	require.NoError(t, err)
+	assert.NotNil(t, trashedChat.DeletedAt)
+	_, err = repo.DeleteChat(ctx, "chat-1", 0)
+	assert.Error(t, err, "a trashed chat cannot be deleted twice")
+
+	_, err = repo.GetChat(ctx, "chat-1")
+	assert.Error(t, err, "trashed chats are hidden from regular queries")
+
+	trashed, err := repo.ListDeletedChats(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.NotNil(t, trashed[0].DeletedAt)
+
+	_, err = repo.RestoreChat(ctx, "user-2", "chat-1")
+	assert.Error(t, err, "only the owner can restore")
+	_, err = repo.RestoreChat(ctx, "user-1", "chat-1")
+	require.NoError(t, err)
+
+	restored, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+
+	_, err = repo.DeleteChat(ctx, "chat-1", 0)
+	require.NoError(t, err)
+	_, err = conn.DB.Collection("shares").InsertOne(ctx, bson.M{"_id": "share-1", "chat_id": "chat-1"})
+	require.NoError(t, err)
+	_, err = conn.DB.Collection("tool_runs").InsertOne(ctx, bson.M{"_id": "run-1", "chat_id": "chat-1"})
+	require.NoError(t, err)
+	_, err = conn.DB.Collection("prompt_caches").InsertOne(ctx, bson.M{"_id": "cache-1", "scope": "chat:chat-1"})
+	require.NoError(t, err)
+	require.NoError(t, repo.SetMemoryExtractedAt(ctx, "chat-1", time.Now()))
+	require.NoError(t, repo.RecordMemoryFailure(ctx, "chat-1", time.Now()))
+	var stored models.Chat
+	require.NoError(t, conn.DB.Collection("chats").FindOne(ctx, bson.M{"_id": "chat-1"}).Decode(&stored))
+	assert.Nil(t, stored.MemoryExtractedAt, "memory bookkeeping skips trashed chats")
+	assert.Zero(t, stored.MemoryFailures)
+	attachments := mongodb.NewAttachmentRepository(conn.DB)
+	require.NoError(t, attachments.SaveAttachment(ctx, &models.Attachment{ID: "att-1", ChatID: "chat-1", Name: "notes.md"}, []byte("notes")))
+	purged, err := repo.PurgeDeletedChats(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged, "chats inside the retention window are kept")
+
+	purged, err = repo.PurgeDeletedChats(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	count, err := conn.DB.Collection("chats").CountDocuments(ctx, bson.M{"_id": "chat-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	count, err = conn.DB.Collection("shares").CountDocuments(ctx, bson.M{"chat_id": "chat-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count, "shares are deleted with their chat")
+
+	count, err = conn.DB.Collection("attachments.files").CountDocuments(ctx, bson.M{"metadata.chat_id": "chat-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count, "attachments are deleted with their chat")
+
+	count, err = conn.DB.Collection("attachments.chunks").CountDocuments(ctx, bson.M{"files_id": "att-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	count, err = conn.DB.Collection("tool_runs").CountDocuments(ctx, bson.M{"chat_id": "chat-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count, "tool runs are deleted with their chat")
+
+	count, err = conn.DB.Collection("prompt_caches").CountDocuments(ctx, bson.M{"scope": "chat:chat-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count, "prompt caches are deleted with their chat")
+}
+
+func TestChatRepository_SetSummary(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewChatRepository(conn.DB)
+	ctx := context.Background()
+
+	chat := &models.Chat{ID: "chat-1", User: "user-1", Title: "Long chat", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, repo.CreateChat(ctx, chat))
+
+	summary := &models.Summary{Text: "We talked about Go.", LastMessageID: "msg-10", MessageCount: 10, Model: "gpt-4o-mini"}
+	updated, err := repo.SetSummary(ctx, "chat-1", summary)
+	require.NoError(t, err)
+	assert.Equal(t, "msg-10", updated.Summary.LastMessageID)
+
+	got, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	require.NotNil(t, got.Summary)
+	assert.Equal(t, "msg-10", got.Summary.LastMessageID)
+	assert.Equal(t, "Long chat", got.Title)
+
+	_, err = repo.SetSummary(ctx, "chat-1", nil)
+	require.NoError(t, err)
+	got, err = repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.Nil(t, got.Summary)
+
+	_, err = repo.SetSummary(ctx, "missing", nil)
+	assert.Error(t, err)
+}
+
+func TestChatRepository_UpdateChatVersion(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewChatRepository(conn.DB)
+	ctx := context.Background()
+
+	chat := &models.Chat{ID: "chat-1", User: "user-1", Title: "Draft", Version: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, repo.CreateChat(ctx, chat))
+
+	first, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	second, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+
+	first.Title = "From the laptop"
+	previous, err := repo.UpdateChat(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), first.Version)
+	assert.Equal(t, "Draft", previous.Title, "the chat is returned as it was before the write")
+
+	second.Title = "From the phone"
+	_, err = repo.UpdateChat(ctx, second)
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Equal(t, int64(1), second.Version)
+
+	_, err = repo.SetSummary(ctx, "chat-1", &models.Summary{Text: "Drafting"})
+	require.NoError(t, err)
+	got, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.Equal(t, "From the laptop", got.Title)
+	assert.Equal(t, int64(2), got.Version, "summaries do not change the version")
+
+	require.NoError(t, repo.SetMemoryExtractedAt(ctx, "chat-1", time.Now()))
+	got, err = repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.Version, "memory extraction does not change the version")
+
+	members := []models.ChatMember{{UserID: "user-2", Role: models.ChatRoleViewer}}
+	_, err = repo.SetMembers(ctx, "chat-1", 1, members)
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	previous, err = repo.SetMembers(ctx, "chat-1", 2, members)
+	require.NoError(t, err)
+	assert.Empty(t, previous.Members)
+
+	latest, err := repo.GetChat(ctx, "chat-1")
+	require.NoError(t, err)
+	require.NoError(t, repo.RecordMemoryFailure(ctx, "chat-1", time.Now().Add(time.Hour)))
+	latest.Title = "Renamed"
+	latest.Summary = nil
+	latest.MemoryExtractedAt = nil
+	latest.Members = nil
+	_, err = repo.UpdateChat(ctx, latest)
+	require.NoError(t, err)
+	var stored models.Chat
+	require.NoError(t, conn.DB.Collection("chats").FindOne(ctx, bson.M{"_id": "chat-1"}).Decode(&stored))
+	assert.Equal(t, "Renamed", stored.Title)
+	assert.Empty(t, stored.Members, "cleared fields are removed")
+	require.NotNil(t, stored.Summary, "summaries written in the background are kept")
+	assert.Equal(t, "Drafting", stored.Summary.Text)
+	assert.NotNil(t, stored.MemoryExtractedAt)
+	assert.Equal(t, 1, stored.MemoryFailures)
+	assert.NotNil(t, stored.MemoryRetryAt)
+
+	_, err = repo.DeleteChat(ctx, "chat-1", 3)
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	_, err = repo.DeleteChat(ctx, "chat-1", 4)
+	require.NoError(t, err)
+
+	missing := &models.Chat{ID: "missing", Version: 1}
+	_, err = repo.UpdateChat(ctx, missing)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrVersionConflict)
+}
@@ -0,0 +1,116 @@
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/internal/repository/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnowledgeRepository_StartIndexing(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewKnowledgeRepository(conn.DB)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Millisecond)
+	recent := now.Add(-time.Minute)
+	stale := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		status     string
+		indexingAt *time.Time
+		userID     string
+		wantErr    error
+	}{
+		{name: "failed document", status: models.DocumentStatusFailed},
+		{name: "ready document", status: models.DocumentStatusReady, indexingAt: &recent},
+		{name: "indexing right now", status: models.DocumentStatusIndexing, indexingAt: &recent,
+			wantErr: repository.ErrDocumentIndexing},
+		{name: "indexing run was lost", status: models.DocumentStatusIndexing, indexingAt: &stale},
+		{name: "indexing without a start time", status: models.DocumentStatusIndexing},
+		{name: "other user's document", status: models.DocumentStatusFailed, userID: "user-2",
+			wantErr: repository.ErrDocumentNotFound},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := &models.KnowledgeDocument{
+				ID:              fmt.Sprintf("doc-%d", i),
+				User:            "user-1",
+				KnowledgeBaseID: "kb-1",
+				Name:            "notes.md",
+				Text:            "Some notes.",
+				Status:          tt.status,
+				Error:           "old failure",
+				IndexingAt:      tt.indexingAt,
+				CreatedAt:       now,
+			}
+			require.NoError(t, repo.CreateDocument(ctx, document))
+
+			userID := "user-1"
+			if tt.userID != "" {
+				userID = tt.userID
+			}
+			err := repo.StartIndexing(ctx, userID, document.ID, now, now.Add(-30*time.Minute))
+
+			stored, getErr := repo.GetDocument(ctx, "user-1", document.ID)
+			require.NoError(t, getErr)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, stored.Status)
+				assert.Equal(t, "old failure", stored.Error)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.DocumentStatusIndexing, stored.Status)
+			assert.Empty(t, stored.Error)
+			require.NotNil(t, stored.IndexingAt)
+			assert.True(t, now.Equal(*stored.IndexingAt))
+
+			assert.ErrorIs(t, repo.StartIndexing(ctx, "user-1", document.ID, now, now.Add(-30*time.Minute)),
+				repository.ErrDocumentIndexing, "a second run is refused")
+		})
+	}
+
+	t.Run("missing document", func(t *testing.T) {
+		err := repo.StartIndexing(ctx, "user-1", "doc-missing", now, now.Add(-30*time.Minute))
+		assert.ErrorIs(t, err, repository.ErrDocumentNotFound)
+	})
+}
+
+func TestKnowledgeRepository_UpdateDocumentStoresIndexingAt(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewKnowledgeRepository(conn.DB)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Millisecond)
+	document := &models.KnowledgeDocument{
+		ID: "doc-1", User: "user-1", KnowledgeBaseID: "kb-1", Name: "notes.md", Text: "Some notes.",
+		Status: models.DocumentStatusFailed, CreatedAt: now,
+	}
+	require.NoError(t, repo.CreateDocument(ctx, document))
+
+	document.Status = models.DocumentStatusIndexing
+	document.IndexingAt = &now
+	require.NoError(t, repo.UpdateDocument(ctx, document))
+
+	stored, err := repo.GetDocument(ctx, "user-1", "doc-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored.IndexingAt)
+	assert.True(t, now.Equal(*stored.IndexingAt))
+
+	document.ID = "doc-missing"
+	assert.ErrorIs(t, repo.UpdateDocument(ctx, document), repository.ErrDocumentNotFound)
+}
@@ -241,7 +241,7 @@ func TestUserRepository_GetUsersChatList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			chats, err := repo.GetUsersChatList(ctx, tt.userID)
+			chats, err := repo.GetUsersChatList(ctx, tt.userID, models.ChatListFilter{})
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -265,3 +265,68 @@ func TestUserRepository_GetUsersChatList(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_GetUsersChatList_Filters(t *testing.T) {
+	conn, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := mongodb.NewUserRepository(conn.DB)
+	ctx := context.Background()
+
+	now := time.Now()
+	testChats := []bson.M{
+		{"_id": "recent", "title": "Recent", "user": "user-1", "tags": []string{"go"}, "folder_id": "folder-1", "created_at": now, "updated_at": now},
+		{"_id": "pinned", "title": "Pinned", "user": "user-1", "pinned": true, "created_at": now, "updated_at": now.Add(-2 * time.Hour)},
+		{"_id": "archived", "title": "Archived", "user": "user-1", "archived": true, "tags": []string{"go"}, "created_at": now, "updated_at": now},
+	}
+	for _, chat := range testChats {
+		_, err := conn.DB.Collection("chats").InsertOne(ctx, chat)
+		require.NoError(t, err)
+	}
+
+	pinned := true
+	tests := []struct {
+		name    string
+		filter  models.ChatListFilter
+		wantIDs []string
+	}{
+		{
+			name:    "default hides archived and puts pinned first",
+			filter:  models.ChatListFilter{},
+			wantIDs: []string{"pinned", "recent"},
+		},
+		{
+			name:    "archived only",
+			filter:  models.ChatListFilter{Archived: true},
+			wantIDs: []string{"archived"},
+		},
+		{
+			name:    "by tag",
+			filter:  models.ChatListFilter{Tags: []string{"go"}},
+			wantIDs: []string{"recent"},
+		},
+		{
+			name:    "by folder",
+			filter:  models.ChatListFilter{FolderID: "folder-1"},
+			wantIDs: []string{"recent"},
+		},
+		{
+			name:    "pinned only",
+			filter:  models.ChatListFilter{Pinned: &pinned},
+			wantIDs: []string{"pinned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chats, err := repo.GetUsersChatList(ctx, "user-1", tt.filter)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, chat := range chats {
+				ids = append(ids, chat.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
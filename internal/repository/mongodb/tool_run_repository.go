@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ToolRunRepository struct {
+	db *mongo.Database
+}
+
+func NewToolRunRepository(db *mongo.Database) *ToolRunRepository {
+	return &ToolRunRepository{db: db}
+}
+
+func (r *ToolRunRepository) CreateToolRun(ctx context.Context, run *models.ToolRun) error {
+	_, err := r.db.Collection("tool_runs").InsertOne(ctx, run)
+	if err != nil {
+		return fmt.Errorf("failed to record tool run: %w", err)
+	}
+	return nil
+}
+
+// ListToolRuns returns the user's most recent tool runs, optionally limited
+// to one chat.
+func (r *ToolRunRepository) ListToolRuns(ctx context.Context, userID string, chatID string,
+	limit int) ([]*models.ToolRun, error) {
+	filter := bson.M{"user": userID}
+	if chatID != "" {
+		filter["chat_id"] = chatID
+	}
+	opts := options.Find().SetSort(bson.M{"started_at": -1}).SetLimit(int64(limit))
+	cursor, err := r.db.Collection("tool_runs").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool runs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	runs := []*models.ToolRun{}
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, fmt.Errorf("failed to decode tool runs: %w", err)
+	}
+	return runs, nil
+}
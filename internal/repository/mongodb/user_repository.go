@@ -85,10 +85,36 @@ func (r *UserRepository) ListUsers(ctx context.Context) ([]*models.User, error)
 	return users, nil
 }
 
-func (r *UserRepository) GetUsersChatList(ctx context.Context, userID string) ([]*models.UserChat, error) {
+func (r *UserRepository) GetUsersChatList(ctx context.Context, userID string, filter models.ChatListFilter) ([]*models.UserChat, error) {
+	match := bson.M{
+		"$or":        []bson.M{{"user": userID}, {"members.user_id": userID}},
+		"deleted_at": bson.M{"$exists": false},
+	}
+	if filter.Archived {
+		match["archived"] = true
+	} else {
+		match["archived"] = bson.M{"$ne": true}
+	}
+	if filter.FolderID != "" {
+		// Folders belong to the chat owner, so shared chats never match.
+		delete(match, "$or")
+		match["user"] = userID
+		match["folder_id"] = filter.FolderID
+	}
+	if len(filter.Tags) > 0 {
+		match["tags"] = bson.M{"$all": filter.Tags}
+	}
+	if filter.Pinned != nil {
+		if *filter.Pinned {
+			match["pinned"] = true
+		} else {
+			match["pinned"] = bson.M{"$ne": true}
+		}
+	}
+
 	pipeline := []bson.M{
 		{
-			"$match": bson.M{"user": userID},
+			"$match": match,
 		},
 		{
 			"$project": bson.M{
@@ -96,12 +122,17 @@ func (r *UserRepository) GetUsersChatList(ctx context.Context, userID string) ([
 				"id":         "$_id",
 				"user":       "$user",
 				"chat_title": "$title",
+				"role":       chatRole(userID),
+				"pinned":     "$pinned",
+				"archived":   "$archived",
+				"folder_id":  "$folder_id",
+				"tags":       "$tags",
 				"created_at": "$created_at",
 				"updated_at": "$updated_at",
 			},
 		},
 		{
-			"$sort": bson.M{"updated_at": -1},
+			"$sort": bson.D{{Key: "pinned", Value: -1}, {Key: "updated_at", Value: -1}},
 		},
 	}
 
@@ -118,3 +149,20 @@ func (r *UserRepository) GetUsersChatList(ctx context.Context, userID string) ([
 
 	return chats, nil
 }
+
+// chatRole projects the caller's role in a chat: owner for their own chats,
+// otherwise the role they were given as a member.
+func chatRole(userID string) bson.M {
+	memberRoles := bson.M{"$map": bson.M{
+		"input": bson.M{"$filter": bson.M{
+			"input": bson.M{"$ifNull": bson.A{"$members", bson.A{}}},
+			"cond":  bson.M{"$eq": bson.A{"$$this.user_id", userID}},
+		}},
+		"in": "$$this.role",
+	}}
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$user", userID}},
+		models.ChatRoleOwner,
+		bson.M{"$arrayElemAt": bson.A{memberRoles, 0}},
+	}}
+}
@@ -2,9 +2,15 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"net/http"
+	"strings"
 
+	"github.com/lutefd/ai-router-go/internal/models"
 	openai "github.com/sashabaranov/go-openai"
 )
 
@@ -52,3 +58,352 @@ func (r *OpenAIRepository) GenerateContentStream(ctx context.Context,
 
 	return nil
 }
+
+func (r *OpenAIRepository) CreateEmbeddings(ctx context.Context,
+	req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	response, err := r.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Input:      req.Input,
+		Model:      openai.EmbeddingModel(req.Model),
+		Dimensions: req.Dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating embeddings: %w", err)
+	}
+
+	result := &models.EmbeddingResponse{
+		Model: string(response.Model),
+		Data:  make([]models.Embedding, 0, len(response.Data)),
+		Usage: models.EmbeddingUsage{
+			PromptTokens: response.Usage.PromptTokens,
+			TotalTokens:  response.Usage.TotalTokens,
+		},
+	}
+	for _, embedding := range response.Data {
+		result.Data = append(result.Data, models.Embedding{Index: embedding.Index, Embedding: embedding.Embedding})
+	}
+	return result, nil
+}
+
+// GenerateImages asks for base64 data rather than URLs, which expire. The GPT
+// image models always return base64 and reject the response format.
+func (r *OpenAIRepository) GenerateImages(ctx context.Context,
+	req *models.ImageRequest) ([]models.GeneratedImage, error) {
+	request := openai.ImageRequest{
+		Prompt: req.Prompt,
+		Model:  req.Model,
+		N:      req.Count,
+		Size:   req.Size,
+		Style:  req.Style,
+	}
+	if !strings.HasPrefix(req.Model, "gpt-image") {
+		request.ResponseFormat = openai.CreateImageResponseFormatB64JSON
+	}
+	response, err := r.client.CreateImage(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error generating images: %w", err)
+	}
+
+	images := make([]models.GeneratedImage, 0, len(response.Data))
+	for _, image := range response.Data {
+		data, err := base64.StdEncoding.DecodeString(image.B64JSON)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding image: %w", err)
+		}
+		images = append(images, models.GeneratedImage{
+			Data:          data,
+			MimeType:      http.DetectContentType(data),
+			RevisedPrompt: image.RevisedPrompt,
+		})
+	}
+	return images, nil
+}
+
+// Transcribe asks the Whisper models for verbose JSON, which adds the
+// detected language and the duration. The newer transcription models only
+// return the text.
+func (r *OpenAIRepository) Transcribe(ctx context.Context,
+	req *models.TranscriptionRequest) (*models.Transcription, error) {
+	format := openai.AudioResponseFormatJSON
+	if strings.HasPrefix(req.Model, "whisper") {
+		format = openai.AudioResponseFormatVerboseJSON
+	}
+	response, err := r.client.CreateTranscription(ctx, openai.AudioRequest{
+		Model:    req.Model,
+		FilePath: req.FileName,
+		Reader:   req.Audio,
+		Prompt:   req.Prompt,
+		Language: req.Language,
+		Format:   format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error transcribing audio: %w", err)
+	}
+	return &models.Transcription{
+		Model:    req.Model,
+		Text:     response.Text,
+		Language: response.Language,
+		Duration: response.Duration,
+	}, nil
+}
+
+func (r *OpenAIRepository) Speak(ctx context.Context, req *models.SpeechRequest) (io.ReadCloser, error) {
+	response, err := r.client.CreateSpeech(ctx, openai.CreateSpeechRequest{
+		Model:          openai.SpeechModel(req.Model),
+		Input:          req.Input,
+		Voice:          openai.SpeechVoice(req.Voice),
+		ResponseFormat: openai.SpeechResponseFormat(req.Format),
+		Speed:          req.Speed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error synthesizing speech: %w", err)
+	}
+	return response, nil
+}
+
+func (r *OpenAIRepository) GenerateChatStream(ctx context.Context,
+	req *models.GenerationRequest, callback func(string)) error {
+	return streamOpenAIChat(ctx, r.client, req, callback, true)
+}
+
+// streamOpenAIChat serves OpenAI and the compatible APIs. Those without
+// json_schema response formats get JSON mode, with the schema described in a
+// system message instead. Both cache prompt prefixes by themselves, so
+// CacheControl marks and CachedContent are not sent; the cached tokens are
+// reported in the usage, for DeepSeek once deepSeekUsageTransport has moved
+// them where OpenAI puts them.
+func streamOpenAIChat(ctx context.Context, client *openai.Client,
+	req *models.GenerationRequest, callback func(string), jsonSchema bool) error {
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages)+1)
+	if req.ResponseSchema != nil && !jsonSchema {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: "Reply with a JSON document matching this JSON Schema:\n" + string(req.ResponseSchema.Schema),
+		})
+	}
+	for _, message := range req.Messages {
+		messages = append(messages, openAIMessage(message))
+	}
+
+	request := openai.ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   true,
+		Tools:    openAITools(req.Tools),
+	}
+	applyOpenAISampling(&request, req.Sampling)
+	if req.ResponseSchema != nil {
+		request.ResponseFormat = openAIResponseFormat(req.ResponseSchema, jsonSchema)
+	}
+	if req.OnUsage != nil {
+		request.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+
+	streamer, err := client.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error creating stream: %w", err)
+	}
+	defer streamer.Close()
+
+	var calls openAIToolCalls
+	for {
+		response, err := streamer.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error receiving stream data: %w", err)
+		}
+		// The usage arrives in a last chunk without choices.
+		if response.Usage != nil && req.OnUsage != nil {
+			req.OnUsage(openAIUsage(response.Usage))
+		}
+		if len(response.Choices) == 0 {
+			continue
+		}
+
+		delta := response.Choices[0].Delta
+		calls.add(delta.ToolCalls)
+		if delta.Content != "" {
+			callback(delta.Content)
+		}
+	}
+
+	calls.emit(req.OnToolCall)
+	return nil
+}
+
+func openAIUsage(usage *openai.Usage) models.Usage {
+	result := models.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		result.CachedTokens = usage.PromptTokensDetails.CachedTokens
+	}
+	return result
+}
+
+func openAIResponseFormat(schema *models.ResponseSchema, jsonSchema bool) *openai.ChatCompletionResponseFormat {
+	if !jsonSchema {
+		return &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	}
+	return &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:   schema.Name,
+			Schema: schema.Schema,
+		},
+	}
+}
+
+var emptyOpenAIParameters = json.RawMessage(`{"type":"object","properties":{}}`)
+
+func openAITools(tools []models.ToolDefinition) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	result := make([]openai.Tool, 0, len(tools))
+	for _, tool := range tools {
+		parameters := tool.Parameters
+		if len(parameters) == 0 {
+			parameters = emptyOpenAIParameters
+		}
+		result = append(result, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+	return result
+}
+
+// openAIToolCalls assembles streamed tool calls. The first chunk of a call
+// carries its ID and name, and the arguments arrive in pieces keyed by the
+// call's index.
+type openAIToolCalls struct {
+	order []int
+	calls map[int]*models.ToolCall
+}
+
+func (c *openAIToolCalls) add(deltas []openai.ToolCall) {
+	for i, delta := range deltas {
+		index := i
+		if delta.Index != nil {
+			index = *delta.Index
+		}
+		if c.calls == nil {
+			c.calls = make(map[int]*models.ToolCall)
+		}
+		call, ok := c.calls[index]
+		if !ok {
+			call = &models.ToolCall{}
+			c.calls[index] = call
+			c.order = append(c.order, index)
+		}
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Function.Name != "" {
+			call.Name = delta.Function.Name
+		}
+		call.Arguments += delta.Function.Arguments
+	}
+}
+
+func (c *openAIToolCalls) emit(onToolCall func(models.ToolCall)) {
+	if onToolCall == nil {
+		return
+	}
+	for _, index := range c.order {
+		call := *c.calls[index]
+		if call.Arguments == "" {
+			call.Arguments = "{}"
+		}
+		onToolCall(call)
+	}
+}
+
+// openAIMessage sends attached images as data URL parts next to the text.
+// Attachments without loaded bytes are left out.
+func openAIMessage(message models.Message) openai.ChatCompletionMessage {
+	result := openai.ChatCompletionMessage{Role: openAIRole(message.Role)}
+	switch {
+	case message.Role == "tool":
+		result.Content = message.Text
+		result.ToolCallID = message.ToolCallID
+		return result
+	case len(message.ToolCalls) > 0:
+		result.Content = message.Text
+		for _, call := range message.ToolCalls {
+			result.ToolCalls = append(result.ToolCalls, openai.ToolCall{
+				ID:       call.ID,
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: call.Name, Arguments: call.Arguments},
+			})
+		}
+		return result
+	}
+	var images []openai.ChatMessagePart
+	for _, attachment := range message.Attachments {
+		if attachment.Kind != models.AttachmentKindImage || len(attachment.Data) == 0 {
+			continue
+		}
+		images = append(images, openai.ChatMessagePart{
+			Type: openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{
+				URL:    "data:" + attachment.MimeType + ";base64," + base64.StdEncoding.EncodeToString(attachment.Data),
+				Detail: openai.ImageURLDetailAuto,
+			},
+		})
+	}
+	if len(images) == 0 || result.Role != openai.ChatMessageRoleUser {
+		result.Content = message.Text
+		return result
+	}
+
+	if message.Text != "" {
+		result.MultiContent = append(result.MultiContent, openai.ChatMessagePart{
+			Type: openai.ChatMessagePartTypeText,
+			Text: message.Text,
+		})
+	}
+	result.MultiContent = append(result.MultiContent, images...)
+	return result
+}
+
+func openAIRole(role string) string {
+	switch role {
+	case "assistant":
+		return openai.ChatMessageRoleAssistant
+	case "system":
+		return openai.ChatMessageRoleSystem
+	case "tool":
+		return openai.ChatMessageRoleTool
+	default:
+		return openai.ChatMessageRoleUser
+	}
+}
+
+func applyOpenAISampling(request *openai.ChatCompletionRequest, sampling models.SamplingParams) {
+	// The client drops zero values, so an explicit zero is sent as the
+	// smallest float it will keep.
+	if sampling.Temperature != nil {
+		request.Temperature = float32(*sampling.Temperature)
+		if request.Temperature == 0 {
+			request.Temperature = math.SmallestNonzeroFloat32
+		}
+	}
+	if sampling.TopP != nil {
+		request.TopP = float32(*sampling.TopP)
+		if request.TopP == 0 {
+			request.TopP = math.SmallestNonzeroFloat32
+		}
+	}
+	if sampling.MaxTokens != nil {
+		request.MaxTokens = *sampling.MaxTokens
+	}
+}
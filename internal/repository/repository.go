@@ -2,13 +2,57 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"github.com/lutefd/ai-router-go/internal/models"
 )
 
+// ErrVersionConflict is returned by conditional chat updates when the stored
+// chat has a different version than the one being written.
+var ErrVersionConflict = errors.New("chat was modified by another request")
+
+// ErrPromptCacheNotFound is returned when no prompt cache is tracked for a
+// chat or assistant.
+var ErrPromptCacheNotFound = errors.New("prompt cache not found")
+
+// ErrDocumentNotFound is returned when a knowledge document does not exist,
+// for example because it was deleted while being indexed.
+var ErrDocumentNotFound = errors.New("document not found")
+
+// ErrDocumentIndexing is returned by StartIndexing when another indexing run
+// started on the document recently.
+var ErrDocumentIndexing = errors.New("document is already being indexed")
+
 type AIRepositoryInterface interface {
 	GenerateContentStream(ctx context.Context, model string, prompt string,
 		callback func(string)) error
+	GenerateChatStream(ctx context.Context, req *models.GenerationRequest,
+		callback func(string)) error
+}
+
+type EmbeddingRepositoryInterface interface {
+	CreateEmbeddings(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error)
+}
+
+type ImageRepositoryInterface interface {
+	GenerateImages(ctx context.Context, req *models.ImageRequest) ([]models.GeneratedImage, error)
+}
+
+type AudioRepositoryInterface interface {
+	Transcribe(ctx context.Context, req *models.TranscriptionRequest) (*models.Transcription, error)
+	// Speak returns the audio as the provider streams it. The caller closes it.
+	Speak(ctx context.Context, req *models.SpeechRequest) (io.ReadCloser, error)
+}
+
+// CachedContentRepositoryInterface manages provider-side caches of the
+// leading messages of a prompt. Expiry times are the provider's.
+type CachedContentRepositoryInterface interface {
+	CreateCachedContent(ctx context.Context, model string, messages []models.Message,
+		ttl time.Duration) (string, time.Time, error)
+	RefreshCachedContent(ctx context.Context, name string, ttl time.Duration) (time.Time, error)
+	DeleteCachedContent(ctx context.Context, name string) error
 }
 
 type UserRepositoryInterface interface {
@@ -18,12 +62,105 @@ type UserRepositoryInterface interface {
 	UpdateUser(ctx context.Context, user *models.User) error
 	DeleteUser(ctx context.Context, userID string) error
 	ListUsers(ctx context.Context) ([]*models.User, error)
-	GetUsersChatList(ctx context.Context, userID string) ([]*models.UserChat, error)
+	GetUsersChatList(ctx context.Context, userID string, filter models.ChatListFilter) ([]*models.UserChat, error)
 }
 
 type ChatRepositoryInterface interface {
 	CreateChat(ctx context.Context, chat *models.Chat) error
-	DeleteChat(ctx context.Context, chatID string) error
+	DeleteChat(ctx context.Context, chatID string, version int64) (*models.Chat, error)
 	GetChat(ctx context.Context, chatID string) (*models.Chat, error)
-	UpdateChat(ctx context.Context, chat *models.Chat) error
+	UpdateChat(ctx context.Context, chat *models.Chat) (*models.Chat, error)
+	UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) ([]*models.Chat, error)
+	DeleteChats(ctx context.Context, userID string, chatIDs []string) ([]*models.Chat, error)
+	ClearFolder(ctx context.Context, userID string, folderID string) error
+	ListDeletedChats(ctx context.Context, userID string) ([]*models.UserChat, error)
+	RestoreChat(ctx context.Context, userID string, chatID string) (*models.Chat, error)
+	PurgeChat(ctx context.Context, userID string, chatID string) error
+	PurgeDeletedChats(ctx context.Context, before time.Time) (int64, error)
+	ForEachUserChat(ctx context.Context, userID string, fn func(*models.Chat) error) error
+	SetSummary(ctx context.Context, chatID string, summary *models.Summary) (*models.Chat, error)
+	ClearAssistant(ctx context.Context, assistantID string) error
+	SetMembers(ctx context.Context, chatID string, version int64, members []models.ChatMember) (*models.Chat, error)
+	ListChatsPendingMemory(ctx context.Context, idleSince time.Time, limit int) ([]*models.Chat, error)
+	SetMemoryExtractedAt(ctx context.Context, chatID string, at time.Time) error
+	RecordMemoryFailure(ctx context.Context, chatID string, retryAt time.Time) error
+}
+
+type FolderRepositoryInterface interface {
+	CreateFolder(ctx context.Context, folder *models.Folder) error
+	GetFolder(ctx context.Context, folderID string) (*models.Folder, error)
+	ListFolders(ctx context.Context, userID string) ([]*models.Folder, error)
+	UpdateFolder(ctx context.Context, folder *models.Folder) error
+	DeleteFolder(ctx context.Context, folderID string) error
+}
+
+type ShareRepositoryInterface interface {
+	CreateShare(ctx context.Context, share *models.Share) error
+	GetShare(ctx context.Context, shareID string) (*models.Share, error)
+	GetShareByToken(ctx context.Context, token string) (*models.Share, error)
+	ListShares(ctx context.Context, chatID string) ([]*models.Share, error)
+	DeleteShare(ctx context.Context, shareID string) error
+}
+
+type TemplateRepositoryInterface interface {
+	CreateTemplate(ctx context.Context, template *models.PromptTemplate) error
+	GetTemplate(ctx context.Context, templateID string) (*models.PromptTemplate, error)
+	ListTemplates(ctx context.Context, userID string) ([]*models.PromptTemplate, error)
+	UpdateTemplate(ctx context.Context, template *models.PromptTemplate) error
+	DeleteTemplate(ctx context.Context, templateID string) error
+	CreateTemplateVersion(ctx context.Context, version *models.PromptTemplateVersion) error
+	GetTemplateVersion(ctx context.Context, templateID string, version int) (*models.PromptTemplateVersion, error)
+	ListTemplateVersions(ctx context.Context, templateID string) ([]*models.PromptTemplateVersion, error)
+}
+
+type AssistantRepositoryInterface interface {
+	CreateAssistant(ctx context.Context, assistant *models.Assistant) error
+	GetAssistant(ctx context.Context, assistantID string) (*models.Assistant, error)
+	ListAssistants(ctx context.Context, userID string) ([]*models.Assistant, error)
+	UpdateAssistant(ctx context.Context, assistant *models.Assistant) error
+	DeleteAssistant(ctx context.Context, assistantID string) error
+}
+
+type MemoryRepositoryInterface interface {
+	CreateMemory(ctx context.Context, memory *models.Memory) error
+	GetMemory(ctx context.Context, userID string, memoryID string) (*models.Memory, error)
+	ListMemories(ctx context.Context, userID string, enabledOnly bool) ([]*models.Memory, error)
+	UpdateMemory(ctx context.Context, memory *models.Memory) error
+	DeleteMemory(ctx context.Context, userID string, memoryID string) error
+}
+
+type AttachmentRepositoryInterface interface {
+	SaveAttachment(ctx context.Context, attachment *models.Attachment, data []byte) error
+	GetAttachment(ctx context.Context, attachmentID string) (*models.Attachment, error)
+	ReadAttachment(ctx context.Context, attachmentID string) ([]byte, error)
+	ListAttachments(ctx context.Context, chatID string) ([]*models.Attachment, error)
+}
+
+type ToolRunRepositoryInterface interface {
+	CreateToolRun(ctx context.Context, run *models.ToolRun) error
+	ListToolRuns(ctx context.Context, userID string, chatID string, limit int) ([]*models.ToolRun, error)
+}
+
+type KnowledgeRepositoryInterface interface {
+	CreateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error
+	GetKnowledgeBase(ctx context.Context, userID string, knowledgeBaseID string) (*models.KnowledgeBase, error)
+	ListKnowledgeBases(ctx context.Context, userID string) ([]*models.KnowledgeBase, error)
+	UpdateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error
+	DeleteKnowledgeBase(ctx context.Context, userID string, knowledgeBaseID string) error
+	CreateDocument(ctx context.Context, document *models.KnowledgeDocument) error
+	GetDocument(ctx context.Context, userID string, documentID string) (*models.KnowledgeDocument, error)
+	ListDocuments(ctx context.Context, userID string, knowledgeBaseID string) ([]*models.KnowledgeDocument, error)
+	UpdateDocument(ctx context.Context, document *models.KnowledgeDocument) error
+	StartIndexing(ctx context.Context, userID string, documentID string, startedAt time.Time, staleBefore time.Time) error
+	DeleteDocument(ctx context.Context, userID string, documentID string) error
+	ReplaceChunks(ctx context.Context, documentID string, chunks []*models.KnowledgeChunk) error
+	ForEachChunk(ctx context.Context, knowledgeBaseIDs []string, fn func(*models.KnowledgeChunk) error) error
+}
+
+type PromptCacheRepositoryInterface interface {
+	CreatePromptCache(ctx context.Context, cache *models.PromptCache) error
+	GetPromptCache(ctx context.Context, scope string, platform string, model string) (*models.PromptCache, error)
+	UpdatePromptCache(ctx context.Context, cache *models.PromptCache) error
+	DeletePromptCache(ctx context.Context, cacheID string) error
+	ListExpiredPromptCaches(ctx context.Context, before time.Time) ([]*models.PromptCache, error)
 }
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestGeminiSchema(t *testing.T) {
+	schema, err := geminiSchema(json.RawMessage(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"city": {"type": "string", "description": "City name"},
+			"days": {"type": ["integer", "null"], "minimum": 1},
+			"unit": {"type": "string", "enum": ["c", "f"]},
+			"level": {"type": "integer", "enum": [1, 2]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3}
+		},
+		"required": ["city"]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, genai.TypeObject, schema.Type)
+	assert.Equal(t, []string{"city"}, schema.Required)
+	assert.Equal(t, genai.TypeString, schema.Properties["city"].Type)
+	assert.Equal(t, "City name", schema.Properties["city"].Description)
+	assert.Equal(t, genai.TypeInteger, schema.Properties["days"].Type)
+	assert.True(t, schema.Properties["days"].Nullable)
+	require.NotNil(t, schema.Properties["days"].Minimum)
+	assert.Equal(t, 1.0, *schema.Properties["days"].Minimum)
+	assert.Equal(t, []string{"c", "f"}, schema.Properties["unit"].Enum)
+	assert.Equal(t, []string{"1", "2"}, schema.Properties["level"].Enum)
+	assert.Equal(t, genai.TypeArray, schema.Properties["tags"].Type)
+	assert.Equal(t, genai.TypeString, schema.Properties["tags"].Items.Type)
+	require.NotNil(t, schema.Properties["tags"].MaxItems)
+	assert.Equal(t, int64(3), *schema.Properties["tags"].MaxItems)
+}
+
+func TestOpenAIToolCalls(t *testing.T) {
+	first, second := 0, 1
+	var calls openAIToolCalls
+	calls.add([]openai.ToolCall{{Index: &first, ID: "call-1", Function: openai.FunctionCall{Name: "get_weather"}}})
+	calls.add([]openai.ToolCall{{Index: &first, Function: openai.FunctionCall{Arguments: `{"city":`}}})
+	calls.add([]openai.ToolCall{{Index: &second, ID: "call-2", Function: openai.FunctionCall{Name: "get_time"}}})
+	calls.add([]openai.ToolCall{{Index: &first, Function: openai.FunctionCall{Arguments: `"Lisbon"}`}}})
+
+	var got []models.ToolCall
+	calls.emit(func(call models.ToolCall) { got = append(got, call) })
+
+	assert.Equal(t, []models.ToolCall{
+		{ID: "call-1", Name: "get_weather", Arguments: `{"city":"Lisbon"}`},
+		{ID: "call-2", Name: "get_time", Arguments: "{}"},
+	}, got)
+}
+
+func TestOpenAIMessage_Tools(t *testing.T) {
+	call := openAIMessage(models.Message{
+		Role:      "assistant",
+		ToolCalls: []models.ToolCall{{ID: "call-1", Name: "get_weather", Arguments: `{"city":"Lisbon"}`}},
+	})
+	assert.Equal(t, openai.ChatMessageRoleAssistant, call.Role)
+	require.Len(t, call.ToolCalls, 1)
+	assert.Equal(t, openai.ToolTypeFunction, call.ToolCalls[0].Type)
+	assert.Equal(t, "get_weather", call.ToolCalls[0].Function.Name)
+
+	result := openAIMessage(models.Message{Role: "tool", ToolCallID: "call-1", Text: "sunny"})
+	assert.Equal(t, openai.ChatMessageRoleTool, result.Role)
+	assert.Equal(t, "call-1", result.ToolCallID)
+	assert.Equal(t, "sunny", result.Content)
+}
+
+func TestGeminiToolResponse(t *testing.T) {
+	assert.Equal(t, map[string]any{"temp": 21.0}, geminiToolResponse(`{"temp": 21}`))
+	assert.Equal(t, map[string]any{"output": "sunny"}, geminiToolResponse("sunny"))
+	assert.Equal(t, map[string]any{"output": "[1,2]"}, geminiToolResponse("[1,2]"))
+}
+
+func TestOpenAIResponseFormat(t *testing.T) {
+	schema := &models.ResponseSchema{Name: "invoice", Schema: json.RawMessage(`{"type":"object"}`)}
+
+	format := openAIResponseFormat(schema, true)
+	assert.Equal(t, openai.ChatCompletionResponseFormatTypeJSONSchema, format.Type)
+	require.NotNil(t, format.JSONSchema)
+	assert.Equal(t, "invoice", format.JSONSchema.Name)
+	data, err := json.Marshal(format)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"json_schema","json_schema":{"name":"invoice","schema":{"type":"object"},"strict":false}}`, string(data))
+
+	format = openAIResponseFormat(schema, false)
+	assert.Equal(t, openai.ChatCompletionResponseFormatTypeJSONObject, format.Type)
+	assert.Nil(t, format.JSONSchema)
+}
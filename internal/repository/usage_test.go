@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestOpenAIUsage(t *testing.T) {
+	usage := openAIUsage(&openai.Usage{
+		PromptTokens:        5200,
+		CompletionTokens:    120,
+		TotalTokens:         5320,
+		PromptTokensDetails: &openai.PromptTokensDetails{CachedTokens: 4864},
+	})
+	assert.Equal(t, models.Usage{PromptTokens: 5200, CompletionTokens: 120, CachedTokens: 4864, TotalTokens: 5320}, usage)
+
+	assert.Zero(t, openAIUsage(&openai.Usage{PromptTokens: 10}).CachedTokens)
+}
+
+func TestDeepSeekUsageLine(t *testing.T) {
+	line := []byte(`data: {"id":"1","choices":[],"usage":{"prompt_tokens":5200,"completion_tokens":120,"total_tokens":5320,"prompt_cache_hit_tokens":4864,"prompt_cache_miss_tokens":336}}` + "\n")
+
+	rewritten := deepSeekUsageLine(line)
+	require.True(t, bytes.HasPrefix(rewritten, []byte("data: ")))
+	require.True(t, bytes.HasSuffix(rewritten, []byte("\n")))
+
+	var chunk openai.ChatCompletionStreamResponse
+	require.NoError(t, json.Unmarshal(bytes.TrimPrefix(rewritten, []byte("data: ")), &chunk))
+	require.NotNil(t, chunk.Usage)
+	assert.Equal(t, models.Usage{PromptTokens: 5200, CompletionTokens: 120, CachedTokens: 4864, TotalTokens: 5320},
+		openAIUsage(chunk.Usage))
+
+	content := []byte(`data: {"choices":[{"delta":{"content":"hi"}}]}` + "\n")
+	assert.Equal(t, content, deepSeekUsageLine(content))
+	assert.Equal(t, []byte("data: [DONE]\n"), deepSeekUsageLine([]byte("data: [DONE]\n")))
+}
+
+func TestDeepSeekUsageReader(t *testing.T) {
+	stream := "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n" +
+		"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":10,\"prompt_cache_hit_tokens\":8}}\n\n" +
+		"data: [DONE]"
+	reader := &deepSeekUsageReader{body: io.NopCloser(nil), lines: bufio.NewReader(strings.NewReader(stream))}
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"content":"hi"`)
+	assert.Contains(t, string(out), `"prompt_tokens_details":{"audio_tokens":0,"cached_tokens":8}`)
+	assert.True(t, strings.HasSuffix(string(out), "\n\ndata: [DONE]"))
+}
+
+func TestGeminiUsage(t *testing.T) {
+	prompt, candidates, cached := int64(6000), int64(90), int64(5000)
+	usage := geminiUsage(&genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        &prompt,
+		CandidatesTokenCount:    &candidates,
+		CachedContentTokenCount: &cached,
+		TotalTokenCount:         6090,
+	})
+	assert.Equal(t, models.Usage{PromptTokens: 6000, CompletionTokens: 90, CachedTokens: 5000, TotalTokens: 6090}, usage)
+}
+
+func TestCachedPrefixLength(t *testing.T) {
+	messages := []models.Message{
+		{Role: "system", CacheControl: true},
+		{Role: "system"},
+		{Role: "user"},
+	}
+	assert.Equal(t, 1, models.CachedPrefixLength(messages))
+	assert.Zero(t, models.CachedPrefixLength(messages[1:]))
+}
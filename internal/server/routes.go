@@ -8,7 +8,7 @@ import (
 	"github.com/lutefd/ai-router-go/internal/middleware"
 )
 
-func routes(handler *handler.AIHandler, authHandler *handler.AuthHandler, chatHandler *handler.ChatHandler, userHandler *handler.UserHandler, healthHandler *handler.HealthHandler, authMiddleware *middleware.AuthMiddleware) chi.Router {
+func routes(handler *handler.AIHandler, embeddingHandler *handler.EmbeddingHandler, imageHandler *handler.ImageHandler, audioHandler *handler.AudioHandler, authHandler *handler.AuthHandler, chatHandler *handler.ChatHandler, folderHandler *handler.FolderHandler, exportHandler *handler.ExportHandler, shareHandler *handler.ShareHandler, memberHandler *handler.ChatMemberHandler, attachmentHandler *handler.AttachmentHandler, templateHandler *handler.TemplateHandler, assistantHandler *handler.AssistantHandler, memoryHandler *handler.MemoryHandler, knowledgeHandler *handler.KnowledgeHandler, toolHandler *handler.ToolHandler, userHandler *handler.UserHandler, eventHandler *handler.EventHandler, healthHandler *handler.HealthHandler, authMiddleware *middleware.AuthMiddleware) chi.Router {
 
 	r := chi.NewRouter()
 
@@ -18,8 +18,8 @@ func routes(handler *handler.AIHandler, authHandler *handler.AuthHandler, chatHa
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Refresh-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Refresh-Token", "Chat-ID", "Temperature", "Top-P", "Max-Tokens", "Template-ID", "Template-Version", "If-Match", "Last-Event-ID"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -27,6 +27,8 @@ func routes(handler *handler.AIHandler, authHandler *handler.AuthHandler, chatHa
 	r.Get("/healthz", healthHandler.LivenessCheck)
 	r.Get("/readiness", healthHandler.ReadinessCheck)
 
+	r.Get("/share/{token}", shareHandler.GetSharedChat)
+
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/auth", func(r chi.Router) {
 			r.Get("/google/login", authHandler.GoogleLogin)
@@ -40,16 +42,107 @@ func routes(handler *handler.AIHandler, authHandler *handler.AuthHandler, chatHa
 			r.Route("/generate", func(r chi.Router) {
 				r.Post("/", handler.ProxyRequest)
 			})
+			r.Post("/embeddings", embeddingHandler.CreateEmbeddings)
+			r.Post("/images", imageHandler.GenerateImages)
+			r.Post("/transcriptions", audioHandler.Transcribe)
+			r.Post("/speech", audioHandler.Speak)
 		})
 
 		r.Route("/chats", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
 			r.Post("/", chatHandler.CreateChat)
+			r.Post("/bulk", chatHandler.BulkUpdate)
+			r.Get("/export", exportHandler.ExportUserChats)
+			r.Post("/import", exportHandler.ImportChats)
+			r.Get("/trash", chatHandler.ListTrash)
+			r.Post("/trash/{id}/restore", chatHandler.RestoreChat)
+			r.Delete("/trash/{id}", chatHandler.PurgeChat)
 			r.Get("/{id}", chatHandler.GetChat)
+			r.Get("/{id}/export", exportHandler.ExportChat)
+			r.Post("/{id}/fork", chatHandler.ForkChat)
+			r.Get("/{id}/shares", shareHandler.ListShares)
+			r.Post("/{id}/shares", shareHandler.CreateShare)
+			r.Delete("/{id}/shares/{shareID}", shareHandler.RevokeShare)
+			r.Get("/{id}/members", memberHandler.ListMembers)
+			r.Post("/{id}/members", memberHandler.AddMember)
+			r.Put("/{id}/members/{userID}", memberHandler.UpdateMember)
+			r.Delete("/{id}/members/{userID}", memberHandler.RemoveMember)
+			r.Get("/{id}/attachments", attachmentHandler.ListAttachments)
+			r.Get("/{id}/attachments/{attachmentID}", attachmentHandler.DownloadAttachment)
+			r.Get("/{id}/summary", chatHandler.GetSummary)
+			r.Delete("/{id}/summary", chatHandler.ResetSummary)
 			r.Put("/{id}/title", chatHandler.UpdateChatTitle)
+			r.Put("/{id}/settings", chatHandler.UpdateSettings)
+			r.Put("/{id}/assistant", chatHandler.SetAssistant)
+			r.Put("/{id}/knowledge-bases", chatHandler.SetKnowledgeBases)
+			r.Put("/{id}/pin", chatHandler.SetPinned)
+			r.Put("/{id}/archive", chatHandler.SetArchived)
+			r.Put("/{id}/folder", chatHandler.MoveToFolder)
+			r.Put("/{id}/tags", chatHandler.SetTags)
 			r.Delete("/{id}", chatHandler.DeleteChat)
 		})
 
+		r.Route("/folders", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", folderHandler.ListFolders)
+			r.Post("/", folderHandler.CreateFolder)
+			r.Put("/{id}", folderHandler.RenameFolder)
+			r.Delete("/{id}", folderHandler.DeleteFolder)
+		})
+
+		r.Route("/templates", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", templateHandler.ListTemplates)
+			r.Post("/", templateHandler.CreateTemplate)
+			r.Get("/{id}", templateHandler.GetTemplate)
+			r.Put("/{id}", templateHandler.UpdateTemplate)
+			r.Delete("/{id}", templateHandler.DeleteTemplate)
+			r.Get("/{id}/versions", templateHandler.ListVersions)
+			r.Post("/{id}/render", templateHandler.RenderTemplate)
+		})
+
+		r.Route("/assistants", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", assistantHandler.ListAssistants)
+			r.Post("/", assistantHandler.CreateAssistant)
+			r.Get("/{id}", assistantHandler.GetAssistant)
+			r.Put("/{id}", assistantHandler.UpdateAssistant)
+			r.Delete("/{id}", assistantHandler.DeleteAssistant)
+		})
+
+		r.Route("/memories", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", memoryHandler.ListMemories)
+			r.Post("/", memoryHandler.CreateMemory)
+			r.Put("/{id}", memoryHandler.UpdateMemory)
+			r.Delete("/{id}", memoryHandler.DeleteMemory)
+		})
+
+		r.Route("/knowledge-bases", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", knowledgeHandler.ListKnowledgeBases)
+			r.Post("/", knowledgeHandler.CreateKnowledgeBase)
+			r.Get("/{id}", knowledgeHandler.GetKnowledgeBase)
+			r.Put("/{id}", knowledgeHandler.UpdateKnowledgeBase)
+			r.Delete("/{id}", knowledgeHandler.DeleteKnowledgeBase)
+			r.Get("/{id}/documents", knowledgeHandler.ListDocuments)
+			r.Post("/{id}/documents", knowledgeHandler.UploadDocuments)
+			r.Get("/{id}/documents/{documentID}", knowledgeHandler.GetDocument)
+			r.Delete("/{id}/documents/{documentID}", knowledgeHandler.DeleteDocument)
+			r.Post("/{id}/documents/{documentID}/reindex", knowledgeHandler.ReindexDocument)
+		})
+
+		r.Route("/tools", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", toolHandler.ListTools)
+			r.Get("/runs", toolHandler.ListToolRuns)
+		})
+
+		r.Route("/events", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", eventHandler.StreamEvents)
+		})
+
 		r.Route("/users", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
 			r.Get("/me/chats", userHandler.GetUserChats)
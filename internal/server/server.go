@@ -12,12 +12,15 @@ import (
 
 	"github.com/lutefd/ai-router-go/internal/config"
 	database "github.com/lutefd/ai-router-go/internal/database/mongodb"
+	"github.com/lutefd/ai-router-go/internal/events"
 	"github.com/lutefd/ai-router-go/internal/handler"
 	"github.com/lutefd/ai-router-go/internal/middleware"
 	"github.com/lutefd/ai-router-go/internal/repository"
 	"github.com/lutefd/ai-router-go/internal/repository/mongodb"
 	"github.com/lutefd/ai-router-go/internal/service"
 	"github.com/lutefd/ai-router-go/internal/strategy"
+	"github.com/lutefd/ai-router-go/internal/tools"
+	"github.com/lutefd/ai-router-go/internal/worker"
 	"github.com/lutefd/ai-router-go/pkg/idgen"
 )
 
@@ -45,25 +48,114 @@ func Run() error {
 	aiService := service.NewAIService(geminiRepo, openaiRepo, deepseekRepo)
 	authService := service.NewAuthService(userRepo, cfg.JWTSecret)
 	aiStrategy := strategy.NewAIStrategy(aiService)
-	aiHandler := handler.NewAIHandler(aiStrategy)
+	var broker service.EventBrokerInterface = events.NewMemoryBroker(1000)
+	if cfg.EventsBackend == "mongo" {
+		broker = events.NewMongoBroker(conn.DB)
+	}
+	chatRepo := events.NewChatRepository(mongodb.NewChatRepository(conn.DB), broker)
+	attachmentRepo := mongodb.NewAttachmentRepository(conn.DB)
+	chatService := service.NewChatService(chatRepo, attachmentRepo)
+	titleService := service.NewTitleService(chatService, aiStrategy, cfg.TitlePlatform, cfg.TitleModel)
+	contextBuilder := service.NewContextBuilder(aiStrategy, cfg.ContextStrategy, cfg.ContextLastTurns,
+		cfg.SummaryPlatform, cfg.SummaryModel)
+	summaryService := service.NewSummaryService(chatRepo, aiStrategy, cfg.SummaryPlatform, cfg.SummaryModel,
+		cfg.SummaryEvery)
+	templateRepo := mongodb.NewTemplateRepository(conn.DB)
+	templateService := service.NewTemplateService(templateRepo)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	assistantRepo := mongodb.NewAssistantRepository(conn.DB)
+	assistantService := service.NewAssistantService(assistantRepo, chatRepo)
+	assistantHandler := handler.NewAssistantHandler(assistantService)
+	memoryRepo := mongodb.NewMemoryRepository(conn.DB)
+	memoryService := service.NewMemoryService(memoryRepo, chatRepo, aiStrategy, cfg.SummaryPlatform, cfg.SummaryModel,
+		cfg.MemoryTokenBudget)
+	memoryHandler := handler.NewMemoryHandler(memoryService)
+	attachmentService := service.NewAttachmentService(attachmentRepo)
+	toolRegistry, err := tools.NewRegistry(
+		tools.NewCalculator(),
+		tools.NewDateTime(nil),
+		tools.NewChatSearch(chatRepo),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register tools: %w", err)
+	}
+	toolRunRepo := mongodb.NewToolRunRepository(conn.DB)
+	toolRunner := service.NewToolRunner(toolRegistry, aiStrategy, toolRunRepo, cfg.ToolMaxSteps)
+	toolHandler := handler.NewToolHandler(toolRunner)
+	structuredOutput := service.NewStructuredOutputService(aiStrategy, cfg.OutputRetries)
+	embeddingService := service.NewEmbeddingService(geminiRepo, openaiRepo)
+	audioService := service.NewAudioService(openaiRepo)
+	audioHandler := handler.NewAudioHandler(audioService)
+	knowledgeRepo := mongodb.NewKnowledgeRepository(conn.DB)
+	knowledgeService := service.NewKnowledgeService(knowledgeRepo, chatRepo, embeddingService, cfg.RAGTopK)
+	knowledgeHandler := handler.NewKnowledgeHandler(knowledgeService, attachmentService)
+	promptCacheRepo := mongodb.NewPromptCacheRepository(conn.DB)
+	promptCache := service.NewPromptCacheManager(promptCacheRepo, geminiRepo, cfg.PromptCacheTTL)
+	aiHandler := handler.NewAIHandler(aiStrategy, chatService, titleService, contextBuilder, summaryService,
+		templateService, assistantService, memoryService, attachmentService, toolRunner, structuredOutput,
+		knowledgeService, audioService, promptCache, cfg.TranscriptionPlatform, cfg.TranscriptionModel)
+	embeddingHandler := handler.NewEmbeddingHandler(embeddingService)
+	imageService := service.NewImageService(geminiRepo, openaiRepo, chatService, attachmentService)
+	imageHandler := handler.NewImageHandler(imageService, chatService)
 	authHandler := handler.NewAuthHandler(authService, cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.AuthRedirectURL, cfg.ClientURL, cfg.AndroidClientID)
-	chatRepo := mongodb.NewChatRepository(conn.DB)
-	chatService := service.NewChatService(chatRepo)
-	chatHandler := handler.NewChatHandler(chatService)
+	folderRepo := mongodb.NewFolderRepository(conn.DB)
+	folderService := service.NewFolderService(folderRepo, chatRepo)
+	chatHandler := handler.NewChatHandler(chatService, folderService, assistantService, knowledgeService)
+	folderHandler := handler.NewFolderHandler(folderService)
+	exportService := service.NewExportService(chatRepo)
+	importService := service.NewImportService(chatRepo)
+	exportHandler := handler.NewExportHandler(chatService, exportService, importService)
+	shareRepo := mongodb.NewShareRepository(conn.DB)
+	shareService := service.NewShareService(shareRepo, chatRepo)
+	shareHandler := handler.NewShareHandler(chatService, shareService)
+	memberService := service.NewChatMemberService(chatRepo, userRepo)
+	memberHandler := handler.NewChatMemberHandler(chatService, memberService)
+	attachmentHandler := handler.NewAttachmentHandler(chatService, attachmentService)
 	authMiddleware := middleware.NewAuthMiddleware(authService)
 	userService := service.NewUserService(userRepo)
 	userHandler := handler.NewUserHandler(userService)
+	eventHandler := handler.NewEventHandler(broker)
 	healthHandler := handler.NewHealthHandler("1.0.0", conn.DB)
 
 	router := routes(
 		aiHandler,
+		embeddingHandler,
+		imageHandler,
+		audioHandler,
 		authHandler,
 		chatHandler,
+		folderHandler,
+		exportHandler,
+		shareHandler,
+		memberHandler,
+		attachmentHandler,
+		templateHandler,
+		assistantHandler,
+		memoryHandler,
+		knowledgeHandler,
+		toolHandler,
 		userHandler,
+		eventHandler,
 		healthHandler,
 		authMiddleware,
 	)
 
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	trashPurger := worker.NewTrashPurger(chatService, cfg.TrashRetention, time.Hour)
+	go trashPurger.Run(workerCtx)
+
+	if cfg.PromptCacheTTL > 0 {
+		promptCacheCleaner := worker.NewPromptCacheCleaner(promptCache, 10*time.Minute)
+		go promptCacheCleaner.Run(workerCtx)
+	}
+
+	if cfg.MemoryIdle > 0 {
+		memoryExtractor := worker.NewMemoryExtractor(memoryService, cfg.MemoryIdle, 10*time.Minute)
+		go memoryExtractor.Run(workerCtx)
+	}
+
 	healthHandler.MarkAsReady()
 
 	srv := &http.Server{
@@ -80,6 +172,7 @@ func Run() error {
 		log.Println("Server is shutting down...")
 
 		healthHandler.MarkAsNotReady()
+		stopWorkers()
 
 		ctx, cancel := context.WithTimeout(context.Background(),
 			30*time.Second)
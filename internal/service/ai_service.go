@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/repository"
 )
 
@@ -51,3 +52,31 @@ func (s *AIService) GenerateDeepSeekResponse(ctx context.Context, model string,
 	}
 	return s.deepseekRepo.GenerateContentStream(ctx, model, prompt, callback)
 }
+
+func (s *AIService) GenerateChatResponse(ctx context.Context,
+	req *models.GenerationRequest, callback func(string)) error {
+	if len(req.Messages) == 0 {
+		return fmt.Errorf("empty conversation")
+	}
+
+	if s.geminiRepo == nil {
+		return fmt.Errorf("gemini repository not initialized")
+	}
+	return s.geminiRepo.GenerateChatStream(ctx, req, callback)
+}
+
+func (s *AIService) GenerateOpenAIChatResponse(ctx context.Context,
+	req *models.GenerationRequest, callback func(string)) error {
+	if s.openaiRepo == nil {
+		return fmt.Errorf("openai repository not initialized")
+	}
+	return s.openaiRepo.GenerateChatStream(ctx, req, callback)
+}
+
+func (s *AIService) GenerateDeepSeekChatResponse(ctx context.Context,
+	req *models.GenerationRequest, callback func(string)) error {
+	if s.deepseekRepo == nil {
+		return fmt.Errorf("deepseek repository not initialized")
+	}
+	return s.deepseekRepo.GenerateChatStream(ctx, req, callback)
+}
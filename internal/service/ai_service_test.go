@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,3 +95,61 @@ func TestAIService_Timeout(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, context.DeadlineExceeded)
 }
+
+func TestAIService_GenerateChatResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	geminiMock := mocks.NewMockAIRepositoryInterface(ctrl)
+	openaiMock := mocks.NewMockAIRepositoryInterface(ctrl)
+	deepseekMock := mocks.NewMockAIRepositoryInterface(ctrl)
+
+	aiService := service.NewAIService(geminiMock, openaiMock, deepseekMock)
+
+	tests := []struct {
+		name      string
+		messages  []models.Message
+		setupMock func(req *models.GenerationRequest)
+		wantErr   bool
+	}{
+		{
+			name: "successful generation",
+			messages: []models.Message{
+				{Text: "be brief", Role: "system"},
+				{Text: "hello", Role: "user"},
+			},
+			setupMock: func(req *models.GenerationRequest) {
+				geminiMock.EXPECT().GenerateChatStream(gomock.Any(), req, gomock.Any()).DoAndReturn(func(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+					callback("hi")
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name:      "empty conversation",
+			messages:  nil,
+			setupMock: func(req *models.GenerationRequest) {},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &models.GenerationRequest{Model: "gemini-pro", Messages: tt.messages}
+			var responses []string
+
+			tt.setupMock(req)
+			err := aiService.GenerateChatResponse(context.Background(), req, func(response string) {
+				responses = append(responses, response)
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"hi"}, responses)
+		})
+	}
+}
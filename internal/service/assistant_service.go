@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+type AssistantService struct {
+	assistantRepo repository.AssistantRepositoryInterface
+	chatRepo      repository.ChatRepositoryInterface
+}
+
+func NewAssistantService(assistantRepo repository.AssistantRepositoryInterface,
+	chatRepo repository.ChatRepositoryInterface) *AssistantService {
+	return &AssistantService{
+		assistantRepo: assistantRepo,
+		chatRepo:      chatRepo,
+	}
+}
+
+func (s *AssistantService) CreateAssistant(ctx context.Context, assistant *models.Assistant) error {
+	assistant.Name = strings.TrimSpace(assistant.Name)
+	if assistant.Name == "" {
+		return fmt.Errorf("assistant name is required")
+	}
+	if assistant.User == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if err := validateSettings(assistant.Settings); err != nil {
+		return err
+	}
+
+	assistant.ID = idgen.GenerateWithPrefix("asst")
+	assistant.CreatedAt = time.Now()
+	assistant.UpdatedAt = time.Now()
+
+	return s.assistantRepo.CreateAssistant(ctx, assistant)
+}
+
+func (s *AssistantService) GetAssistant(ctx context.Context, id string) (*models.Assistant, error) {
+	if id == "" {
+		return nil, fmt.Errorf("assistant ID is required")
+	}
+
+	return s.assistantRepo.GetAssistant(ctx, id)
+}
+
+func (s *AssistantService) ListAssistants(ctx context.Context, userID string) ([]*models.Assistant, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	return s.assistantRepo.ListAssistants(ctx, userID)
+}
+
+func (s *AssistantService) UpdateAssistant(ctx context.Context, id string, update models.AssistantUpdate) (*models.Assistant, error) {
+	if id == "" {
+		return nil, fmt.Errorf("assistant ID is required")
+	}
+
+	assistant, err := s.assistantRepo.GetAssistant(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assistant: %w", err)
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("assistant name is required")
+		}
+		assistant.Name = name
+	}
+	if update.Description != nil {
+		assistant.Description = *update.Description
+	}
+	if update.Avatar != nil {
+		assistant.Avatar = *update.Avatar
+	}
+	if update.Settings != nil {
+		if err := validateSettings(*update.Settings); err != nil {
+			return nil, err
+		}
+		assistant.Settings = *update.Settings
+	}
+	if update.Shared != nil {
+		assistant.Shared = *update.Shared
+	}
+
+	assistant.UpdatedAt = time.Now()
+	if err := s.assistantRepo.UpdateAssistant(ctx, assistant); err != nil {
+		return nil, err
+	}
+	return assistant, nil
+}
+
+func (s *AssistantService) DeleteAssistant(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("assistant ID is required")
+	}
+
+	if err := s.assistantRepo.DeleteAssistant(ctx, id); err != nil {
+		return err
+	}
+	return s.chatRepo.ClearAssistant(ctx, id)
+}
+
+// SetChatAssistant points the user's chats at an assistant they can use, or
+// detaches them when assistantID is empty. A non-zero version restricts it to
+// chats still at that version.
+func (s *AssistantService) SetChatAssistant(ctx context.Context, userID string, assistantID string, chatIDs []string,
+	version int64) (int64, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("user ID is required")
+	}
+	if len(chatIDs) == 0 {
+		return 0, fmt.Errorf("at least one chat ID is required")
+	}
+
+	if assistantID != "" {
+		assistant, err := s.assistantRepo.GetAssistant(ctx, assistantID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get assistant: %w", err)
+		}
+		if !CanUseAssistant(assistant, userID) {
+			return 0, fmt.Errorf("assistant not found")
+		}
+	}
+
+	return chatCount(s.chatRepo.UpdateChats(ctx, userID, chatIDs, models.ChatUpdate{AssistantID: &assistantID, Version: version}))
+}
+
+func CanUseAssistant(assistant *models.Assistant, userID string) bool {
+	return assistant.User == userID || assistant.Shared
+}
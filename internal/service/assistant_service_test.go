@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAssistantService_CreateAssistant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssistantRepo := mocks.NewMockAssistantRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	assistantService := service.NewAssistantService(mockAssistantRepo, mockChatRepo)
+
+	tests := []struct {
+		name      string
+		assistant *models.Assistant
+		setup     func()
+		wantErr   bool
+	}{
+		{
+			name: "successful creation",
+			assistant: &models.Assistant{
+				User: "user-123",
+				Name: " Code reviewer ",
+				Settings: models.ChatSettings{
+					SystemPrompt: "Review code carefully.",
+					Platform:     "openai",
+					Model:        "gpt-4o",
+				},
+			},
+			setup: func() {
+				mockAssistantRepo.EXPECT().CreateAssistant(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "missing name",
+			assistant: &models.Assistant{User: "user-123"},
+			setup:     func() {},
+			wantErr:   true,
+		},
+		{
+			name: "unsupported platform",
+			assistant: &models.Assistant{
+				User:     "user-123",
+				Name:     "Oracle",
+				Settings: models.ChatSettings{Platform: "mystery"},
+			},
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := assistantService.CreateAssistant(context.Background(), tt.assistant)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, tt.assistant.ID)
+			assert.Equal(t, "Code reviewer", tt.assistant.Name)
+		})
+	}
+}
+
+func TestAssistantService_SetChatAssistant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssistantRepo := mocks.NewMockAssistantRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	assistantService := service.NewAssistantService(mockAssistantRepo, mockChatRepo)
+
+	assistantID := "asst-1"
+	detach := ""
+
+	tests := []struct {
+		name        string
+		assistantID string
+		setup       func()
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name:        "own assistant",
+			assistantID: assistantID,
+			setup: func() {
+				mockAssistantRepo.EXPECT().
+					GetAssistant(gomock.Any(), assistantID).
+					Return(&models.Assistant{ID: assistantID, User: "user-123"}, nil)
+				mockChatRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1"}, models.ChatUpdate{AssistantID: &assistantID}).
+					Return([]*models.Chat{{ID: "chat-1"}}, nil)
+			},
+			want: 1,
+		},
+		{
+			name:        "shared assistant",
+			assistantID: assistantID,
+			setup: func() {
+				mockAssistantRepo.EXPECT().
+					GetAssistant(gomock.Any(), assistantID).
+					Return(&models.Assistant{ID: assistantID, User: "user-456", Shared: true}, nil)
+				mockChatRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1"}, gomock.Any()).
+					Return([]*models.Chat{{ID: "chat-1"}}, nil)
+			},
+			want: 1,
+		},
+		{
+			name:        "private assistant of another user",
+			assistantID: assistantID,
+			setup: func() {
+				mockAssistantRepo.EXPECT().
+					GetAssistant(gomock.Any(), assistantID).
+					Return(&models.Assistant{ID: assistantID, User: "user-456"}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "detach",
+			assistantID: detach,
+			setup: func() {
+				mockChatRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1"}, models.ChatUpdate{AssistantID: &detach}).
+					Return([]*models.Chat{{ID: "chat-1"}}, nil)
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := assistantService.SetChatAssistant(context.Background(), "user-123", tt.assistantID, []string{"chat-1"}, 0)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssistantService_DeleteAssistant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAssistantRepo := mocks.NewMockAssistantRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	assistantService := service.NewAssistantService(mockAssistantRepo, mockChatRepo)
+
+	mockAssistantRepo.EXPECT().DeleteAssistant(gomock.Any(), "asst-1").Return(nil)
+	mockChatRepo.EXPECT().ClearAssistant(gomock.Any(), "asst-1").Return(nil)
+	require.NoError(t, assistantService.DeleteAssistant(context.Background(), "asst-1"))
+
+	mockAssistantRepo.EXPECT().DeleteAssistant(gomock.Any(), "asst-2").Return(fmt.Errorf("assistant not found"))
+	assert.Error(t, assistantService.DeleteAssistant(context.Background(), "asst-2"))
+}
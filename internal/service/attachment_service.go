@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+const (
+	MaxImageSize             = 20 << 20
+	MaxDocumentSize          = 10 << 20
+	MaxDocumentText          = 2 << 20
+	MaxAttachmentsPerMessage = 10
+)
+
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+var documentTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".csv":      "text/csv",
+}
+
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepositoryInterface
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepositoryInterface) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+	}
+}
+
+// NewAttachment validates an uploaded file and returns an unsaved attachment
+// holding its bytes. Images are recognised from their content. PDFs and any
+// UTF-8 text file, which covers Markdown, CSV and source code, become
+// documents with their text extracted.
+func (s *AttachmentService) NewAttachment(userID string, name string, data []byte) (*models.Attachment, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("file %q is empty", name)
+	}
+	if name == "" {
+		name = "file"
+	}
+
+	attachment := &models.Attachment{
+		ID:        idgen.GenerateWithPrefix("att"),
+		User:      userID,
+		Name:      name,
+		Size:      int64(len(data)),
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+
+	sniffed := http.DetectContentType(data)
+	if imageTypes[sniffed] {
+		if len(data) > MaxImageSize {
+			return nil, fmt.Errorf("image %q is larger than %d MB", name, MaxImageSize>>20)
+		}
+		attachment.Kind = models.AttachmentKindImage
+		attachment.MimeType = sniffed
+		return attachment, nil
+	}
+
+	if len(data) > MaxDocumentSize {
+		return nil, fmt.Errorf("file %q is larger than %d MB", name, MaxDocumentSize>>20)
+	}
+	attachment.Kind = models.AttachmentKindDocument
+	switch {
+	case sniffed == "application/pdf":
+		attachment.MimeType = sniffed
+		text, err := pdfText(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PDF %q: %w", name, err)
+		}
+		attachment.Text = text
+	case utf8.Valid(data) && !bytes.ContainsRune(data, 0):
+		attachment.MimeType = documentType(name)
+		attachment.Text = string(data)
+	default:
+		return nil, fmt.Errorf("file %q has unsupported type %s", name, sniffed)
+	}
+
+	attachment.Text = strings.TrimSpace(attachment.Text)
+	if attachment.Text == "" {
+		return nil, fmt.Errorf("file %q has no text", name)
+	}
+	if len(attachment.Text) > MaxDocumentText {
+		return nil, fmt.Errorf("file %q has more than %d MB of text", name, MaxDocumentText>>20)
+	}
+	return attachment, nil
+}
+
+func documentType(name string) string {
+	if mimeType, ok := documentTypes[strings.ToLower(filepath.Ext(name))]; ok {
+		return mimeType
+	}
+	return "text/plain"
+}
+
+// pdfText extracts the plain text of a PDF. The parser panics on some
+// malformed files, so that is turned into an error.
+func pdfText(data []byte) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", err
+	}
+	content, err := io.ReadAll(io.LimitReader(plain, MaxDocumentText+1))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func (s *AttachmentService) SaveAttachments(ctx context.Context, chatID string, attachments []models.Attachment) error {
+	for i := range attachments {
+		attachments[i].ChatID = chatID
+		if err := s.attachmentRepo.SaveAttachment(ctx, &attachments[i], attachments[i].Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AttachmentService) GetAttachment(ctx context.Context, id string) (*models.Attachment, error) {
+	return s.attachmentRepo.GetAttachment(ctx, id)
+}
+
+func (s *AttachmentService) ReadAttachment(ctx context.Context, id string) ([]byte, error) {
+	return s.attachmentRepo.ReadAttachment(ctx, id)
+}
+
+func (s *AttachmentService) ListAttachments(ctx context.Context, chatID string) ([]*models.Attachment, error) {
+	return s.attachmentRepo.ListAttachments(ctx, chatID)
+}
+
+// LoadAttachmentData returns a copy of the chat's messages with the extracted
+// text of every document filled in, and the bytes of every image when images
+// is set. Attachments whose file can no longer be read, or that belong to
+// someone outside the chat, are dropped so an old chat keeps working.
+func (s *AttachmentService) LoadAttachmentData(ctx context.Context, chat *models.Chat, messages []models.Message,
+	images bool) ([]models.Message, error) {
+	loaded := make([]models.Message, len(messages))
+	for i, message := range messages {
+		loaded[i] = message
+		if len(message.Attachments) == 0 {
+			continue
+		}
+
+		attachments := make([]models.Attachment, 0, len(message.Attachments))
+		for _, attachment := range message.Attachments {
+			err := s.loadAttachment(ctx, chat, &attachment, images)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				log.Printf("Skipping attachment %s: %v", attachment.ID, err)
+				continue
+			}
+			attachments = append(attachments, attachment)
+		}
+		loaded[i].Attachments = attachments
+	}
+	return loaded, nil
+}
+
+// loadAttachment fills in an attachment from its stored file. Messages only
+// hold attachment IDs, so the stored metadata decides whether the chat may
+// use the file: it must have been uploaded to the chat or by someone in it.
+func (s *AttachmentService) loadAttachment(ctx context.Context, chat *models.Chat, attachment *models.Attachment,
+	images bool) error {
+	if attachment.Kind == models.AttachmentKindImage && !images {
+		return nil
+	}
+
+	stored, err := s.attachmentRepo.GetAttachment(ctx, attachment.ID)
+	if err != nil {
+		return err
+	}
+	if stored.ChatID != chat.ID && ChatRole(chat, stored.User) == "" {
+		return fmt.Errorf("attachment not found")
+	}
+
+	if stored.Kind == models.AttachmentKindImage && images {
+		data, err := s.attachmentRepo.ReadAttachment(ctx, stored.ID)
+		if err != nil {
+			return err
+		}
+		stored.Data = data
+	}
+	*attachment = *stored
+	return nil
+}
+
+// InlineDocuments returns a copy of messages with the text of attached
+// documents appended to the message text between file delimiters. Documents
+// share the model's document budget, newest first; a document that does not
+// fit is truncated and the ones after it are left out with a note.
+func InlineDocuments(info catalog.ModelInfo, messages []models.Message) []models.Message {
+	inlined := make([]models.Message, len(messages))
+	copy(inlined, messages)
+
+	remaining := info.DocumentBudget()
+	for i := len(inlined) - 1; i >= 0; i-- {
+		message := inlined[i]
+		var text strings.Builder
+		text.WriteString(message.Text)
+		var attachments []models.Attachment
+		for _, attachment := range message.Attachments {
+			if attachment.Kind != models.AttachmentKindDocument {
+				attachments = append(attachments, attachment)
+				continue
+			}
+			text.WriteString("\n\n")
+			text.WriteString(documentBlock(info, attachment, &remaining))
+		}
+		inlined[i].Text = text.String()
+		inlined[i].Attachments = attachments
+	}
+	return inlined
+}
+
+func documentBlock(info catalog.ModelInfo, attachment models.Attachment, remaining *int) string {
+	if *remaining <= 0 || attachment.Text == "" {
+		return fmt.Sprintf("<file name=%q type=%q omitted=\"over the size budget\"/>",
+			attachment.Name, attachment.MimeType)
+	}
+
+	content := attachment.Text
+	tokens := info.EstimateTokens(content)
+	if tokens > *remaining {
+		runes := []rune(content)
+		content = string(runes[:int(float64(*remaining)*info.CharsPerToken)]) + "\n[truncated]"
+		tokens = *remaining
+	}
+	*remaining -= tokens
+	return fmt.Sprintf("<file name=%q type=%q>\n%s\n</file>", attachment.Name, attachment.MimeType, content)
+}
@@ -0,0 +1,250 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+// minimalPDF builds a one-page PDF showing text, with a valid xref table.
+func minimalPDF(text string) []byte {
+	content := fmt.Sprintf("BT /F1 12 Tf 72 712 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R " +
+			"/Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestAttachmentService_NewAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	attachmentService := service.NewAttachmentService(mocks.NewMockAttachmentRepositoryInterface(ctrl))
+
+	tests := []struct {
+		name         string
+		fileName     string
+		data         []byte
+		wantKind     string
+		wantMimeType string
+		wantName     string
+		wantText     string
+		wantErr      bool
+	}{
+		{
+			name: "png", fileName: "cat.png", data: pngHeader,
+			wantKind: models.AttachmentKindImage, wantMimeType: "image/png", wantName: "cat.png",
+		},
+		{
+			name: "jpeg without name", data: []byte("\xFF\xD8\xFF\xE0"),
+			wantKind: models.AttachmentKindImage, wantMimeType: "image/jpeg", wantName: "file",
+		},
+		{
+			name: "markdown", fileName: "README.md", data: []byte("# Title\n\nBody\n"),
+			wantKind: models.AttachmentKindDocument, wantMimeType: "text/markdown", wantName: "README.md",
+			wantText: "# Title\n\nBody",
+		},
+		{
+			name: "csv", fileName: "data.CSV", data: []byte("a,b\n1,2\n"),
+			wantKind: models.AttachmentKindDocument, wantMimeType: "text/csv", wantName: "data.CSV",
+			wantText: "a,b\n1,2",
+		},
+		{
+			name: "source code", fileName: "main.go", data: []byte("package main\n"),
+			wantKind: models.AttachmentKindDocument, wantMimeType: "text/plain", wantName: "main.go",
+			wantText: "package main",
+		},
+		{
+			name: "pdf", fileName: "report.pdf", data: minimalPDF("Quarterly report"),
+			wantKind: models.AttachmentKindDocument, wantMimeType: "application/pdf", wantName: "report.pdf",
+			wantText: "Quarterly report",
+		},
+		{name: "broken pdf", fileName: "broken.pdf", data: []byte("%PDF-1.4\ngarbage"), wantErr: true},
+		{name: "empty", fileName: "empty.png", wantErr: true},
+		{name: "blank text", fileName: "blank.txt", data: []byte(" \n\t"), wantErr: true},
+		{name: "binary", fileName: "app.bin", data: []byte{0x00, 0x01, 0x02, 0xFF}, wantErr: true},
+		{
+			name:     "image too large",
+			fileName: "big.png",
+			data:     append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, service.MaxImageSize)...),
+			wantErr:  true,
+		},
+		{
+			name:     "document too large",
+			fileName: "big.txt",
+			data:     bytes.Repeat([]byte("a"), service.MaxDocumentSize+1),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment, err := attachmentService.NewAttachment("user-123", tt.fileName, tt.data)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, attachment.ID)
+			assert.Equal(t, "user-123", attachment.User)
+			assert.Equal(t, tt.wantKind, attachment.Kind)
+			assert.Equal(t, tt.wantMimeType, attachment.MimeType)
+			assert.Equal(t, tt.wantName, attachment.Name)
+			assert.Equal(t, int64(len(tt.data)), attachment.Size)
+			assert.Equal(t, tt.wantText, attachment.Text)
+		})
+	}
+}
+
+func TestAttachmentService_SaveAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttachmentRepo := mocks.NewMockAttachmentRepositoryInterface(ctrl)
+	attachmentService := service.NewAttachmentService(mockAttachmentRepo)
+
+	attachments := []models.Attachment{{ID: "att-1", Data: pngHeader}}
+	mockAttachmentRepo.EXPECT().SaveAttachment(gomock.Any(), gomock.Any(), pngHeader).
+		DoAndReturn(func(_ context.Context, attachment *models.Attachment, _ []byte) error {
+			assert.Equal(t, "chat-123", attachment.ChatID)
+			return nil
+		})
+
+	require.NoError(t, attachmentService.SaveAttachments(context.Background(), "chat-123", attachments))
+	assert.Equal(t, "chat-123", attachments[0].ChatID)
+}
+
+func TestAttachmentService_LoadAttachmentData(t *testing.T) {
+	messages := []models.Message{
+		{ID: "msg-1", Text: "what is this?", Role: "user", Attachments: []models.Attachment{
+			{ID: "att-1", Kind: models.AttachmentKindImage},
+			{ID: "att-2", Kind: models.AttachmentKindImage},
+			{ID: "att-3", Kind: models.AttachmentKindDocument},
+		}},
+		{ID: "msg-2", Text: "a cat", Role: "assistant"},
+	}
+	chat := &models.Chat{ID: "chat-123", User: "user-123", Messages: messages}
+
+	t.Run("with images", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttachmentRepo := mocks.NewMockAttachmentRepositoryInterface(ctrl)
+		attachmentService := service.NewAttachmentService(mockAttachmentRepo)
+
+		mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-1").
+			Return(&models.Attachment{ID: "att-1", ChatID: "chat-123", Kind: models.AttachmentKindImage}, nil)
+		mockAttachmentRepo.EXPECT().ReadAttachment(gomock.Any(), "att-1").Return(pngHeader, nil)
+		mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-2").Return(nil, fmt.Errorf("attachment not found"))
+		mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-3").
+			Return(&models.Attachment{ID: "att-3", User: "user-123", Kind: models.AttachmentKindDocument, Text: "notes"}, nil)
+
+		loaded, err := attachmentService.LoadAttachmentData(context.Background(), chat, messages, true)
+		require.NoError(t, err)
+		require.Len(t, loaded, 2)
+		require.Len(t, loaded[0].Attachments, 2)
+		assert.Equal(t, pngHeader, loaded[0].Attachments[0].Data)
+		assert.Equal(t, "notes", loaded[0].Attachments[1].Text)
+		assert.Empty(t, messages[0].Attachments[0].Data, "input messages are not modified")
+		assert.Len(t, messages[0].Attachments, 3)
+	})
+
+	t.Run("without images", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttachmentRepo := mocks.NewMockAttachmentRepositoryInterface(ctrl)
+		attachmentService := service.NewAttachmentService(mockAttachmentRepo)
+
+		mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-3").
+			Return(&models.Attachment{ID: "att-3", ChatID: "chat-123", Kind: models.AttachmentKindDocument, Text: "notes"}, nil)
+
+		loaded, err := attachmentService.LoadAttachmentData(context.Background(), chat, messages, false)
+		require.NoError(t, err)
+		require.Len(t, loaded[0].Attachments, 3)
+		assert.Empty(t, loaded[0].Attachments[0].Data)
+		assert.Equal(t, "notes", loaded[0].Attachments[2].Text)
+	})
+
+	t.Run("attachments of other users are dropped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttachmentRepo := mocks.NewMockAttachmentRepositoryInterface(ctrl)
+		attachmentService := service.NewAttachmentService(mockAttachmentRepo)
+
+		mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-3").
+			Return(&models.Attachment{ID: "att-3", User: "stranger-1", ChatID: "chat-999", Kind: models.AttachmentKindDocument, Text: "secret"}, nil)
+
+		loaded, err := attachmentService.LoadAttachmentData(context.Background(), chat, messages, false)
+		require.NoError(t, err)
+		require.Len(t, loaded[0].Attachments, 2)
+		for _, attachment := range loaded[0].Attachments {
+			assert.Empty(t, attachment.Text)
+		}
+	})
+}
+
+func TestInlineDocuments(t *testing.T) {
+	info := catalog.Lookup("openai", "gpt-4")
+	budget := info.DocumentBudget()
+	large := strings.Repeat("x", int(float64(budget)*info.CharsPerToken)-8)
+
+	messages := []models.Message{
+		{Text: "older", Role: "user", Attachments: []models.Attachment{
+			{Kind: models.AttachmentKindDocument, Name: "old.txt", MimeType: "text/plain", Text: "old notes"},
+		}},
+		{Text: "ok", Role: "assistant"},
+		{Text: "newer", Role: "user", Attachments: []models.Attachment{
+			{Kind: models.AttachmentKindImage, Name: "cat.png", MimeType: "image/png"},
+			{Kind: models.AttachmentKindDocument, Name: "big.csv", MimeType: "text/csv", Text: large},
+			{Kind: models.AttachmentKindDocument, Name: "more.md", MimeType: "text/markdown", Text: "more notes"},
+		}},
+	}
+
+	inlined := service.InlineDocuments(info, messages)
+	require.Len(t, inlined, 3)
+
+	newer := inlined[2].Text
+	assert.True(t, strings.HasPrefix(newer, "newer\n\n<file name=\"big.csv\" type=\"text/csv\">\n"))
+	assert.Contains(t, newer, "<file name=\"more.md\" type=\"text/markdown\">\nmore")
+	assert.Contains(t, newer, "[truncated]\n</file>")
+	require.Len(t, inlined[2].Attachments, 1, "only images are left as attachments")
+	assert.Equal(t, models.AttachmentKindImage, inlined[2].Attachments[0].Kind)
+
+	assert.Equal(t, "older\n\n<file name=\"old.txt\" type=\"text/plain\" omitted=\"over the size budget\"/>",
+		inlined[0].Text)
+	assert.Equal(t, "newer", messages[2].Text, "input messages are not modified")
+	assert.LessOrEqual(t, info.EstimateTokens(newer), budget+50, "delimiters are not counted")
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+const (
+	MaxAudioSize        = 25 << 20
+	maxSpeechInput      = 4096
+	minSpeechSpeed      = 0.25
+	maxSpeechSpeed      = 4.0
+	defaultSpeechFormat = "mp3"
+)
+
+var audioExtensions = []string{".flac", ".m4a", ".mp3", ".mp4", ".mpeg", ".mpga", ".oga", ".ogg", ".wav", ".webm"}
+
+var speechVoices = []string{"alloy", "ash", "ballad", "coral", "echo", "fable", "nova", "onyx", "sage", "shimmer", "verse"}
+
+// speechFormats maps the formats speech can be synthesized in to their
+// content types.
+var speechFormats = map[string]string{
+	"mp3":  "audio/mpeg",
+	"opus": "audio/ogg",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"wav":  "audio/wav",
+	"pcm":  "audio/pcm",
+}
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2}$`)
+
+// ValidateTranscriptionRequest checks the recording's format, recognised from
+// its file name, and the optional ISO-639-1 language.
+func ValidateTranscriptionRequest(platform string, req *models.TranscriptionRequest) error {
+	if platform != "openai" {
+		return fmt.Errorf("platform %s does not support transcription", platform)
+	}
+	if req.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if !slices.Contains(audioExtensions, strings.ToLower(filepath.Ext(req.FileName))) {
+		return fmt.Errorf("audio %q has an unsupported format; use one of %s", req.FileName,
+			strings.Join(audioExtensions, ", "))
+	}
+	if req.Language != "" && !languagePattern.MatchString(req.Language) {
+		return fmt.Errorf("language must be an ISO-639-1 code such as en")
+	}
+	return nil
+}
+
+// ValidateSpeechRequest checks the text, voice, format and speed of a speech
+// request. An empty format means mp3 and a zero speed the normal speed.
+func ValidateSpeechRequest(platform string, req *models.SpeechRequest) error {
+	if platform != "openai" {
+		return fmt.Errorf("platform %s does not support speech", platform)
+	}
+	if req.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if strings.TrimSpace(req.Input) == "" {
+		return fmt.Errorf("input is required")
+	}
+	if utf8.RuneCountInString(req.Input) > maxSpeechInput {
+		return fmt.Errorf("input must be at most %d characters", maxSpeechInput)
+	}
+	if !slices.Contains(speechVoices, req.Voice) {
+		return fmt.Errorf("voice must be one of %s", strings.Join(speechVoices, ", "))
+	}
+	if _, ok := speechFormats[req.Format]; req.Format != "" && !ok {
+		return fmt.Errorf("unsupported format %s", req.Format)
+	}
+	if req.Speed != 0 && (req.Speed < minSpeechSpeed || req.Speed > maxSpeechSpeed) {
+		return fmt.Errorf("speed must be between %.2f and %.1f", minSpeechSpeed, maxSpeechSpeed)
+	}
+	return nil
+}
+
+// SpeechContentType returns the content type of speech synthesized in format.
+func SpeechContentType(format string) string {
+	if format == "" {
+		format = defaultSpeechFormat
+	}
+	return speechFormats[format]
+}
+
+type AudioService struct {
+	openaiRepo repository.AudioRepositoryInterface
+}
+
+func NewAudioService(openaiRepo repository.AudioRepositoryInterface) *AudioService {
+	return &AudioService{
+		openaiRepo: openaiRepo,
+	}
+}
+
+func (s *AudioService) Transcribe(ctx context.Context, platform string,
+	req *models.TranscriptionRequest) (*models.Transcription, error) {
+	if err := ValidateTranscriptionRequest(platform, req); err != nil {
+		return nil, err
+	}
+	transcription, err := s.openaiRepo.Transcribe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	transcription.Platform = platform
+	transcription.Text = strings.TrimSpace(transcription.Text)
+	return transcription, nil
+}
+
+func (s *AudioService) Speak(ctx context.Context, platform string, req *models.SpeechRequest) (io.ReadCloser, error) {
+	if err := ValidateSpeechRequest(platform, req); err != nil {
+		return nil, err
+	}
+	request := *req
+	if request.Format == "" {
+		request.Format = defaultSpeechFormat
+	}
+	return s.openaiRepo.Speak(ctx, &request)
+}
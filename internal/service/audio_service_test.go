@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidateTranscriptionRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		req      models.TranscriptionRequest
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			platform: "openai",
+			req:      models.TranscriptionRequest{Model: "whisper-1", FileName: "memo.M4A", Language: "pt"},
+		},
+		{
+			name:     "unsupported platform",
+			platform: "deepseek",
+			req:      models.TranscriptionRequest{Model: "whisper-1", FileName: "memo.mp3"},
+			wantErr:  "platform deepseek does not support transcription",
+		},
+		{
+			name:     "unsupported format",
+			platform: "openai",
+			req:      models.TranscriptionRequest{Model: "whisper-1", FileName: "memo.aiff"},
+			wantErr:  `audio "memo.aiff" has an unsupported format; use one of .flac, .m4a, .mp3, .mp4, .mpeg, .mpga, .oga, .ogg, .wav, .webm`,
+		},
+		{
+			name:     "invalid language",
+			platform: "openai",
+			req:      models.TranscriptionRequest{Model: "whisper-1", FileName: "memo.mp3", Language: "english"},
+			wantErr:  "language must be an ISO-639-1 code such as en",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateTranscriptionRequest(tt.platform, &tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateSpeechRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.SpeechRequest
+		wantErr string
+	}{
+		{
+			name: "valid",
+			req:  models.SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "nova", Format: "opus", Speed: 1.5},
+		},
+		{
+			name:    "missing input",
+			req:     models.SpeechRequest{Model: "tts-1", Voice: "nova"},
+			wantErr: "input is required",
+		},
+		{
+			name:    "too long",
+			req:     models.SpeechRequest{Model: "tts-1", Input: strings.Repeat("a", 4097), Voice: "nova"},
+			wantErr: "input must be at most 4096 characters",
+		},
+		{
+			name:    "unknown voice",
+			req:     models.SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "hal"},
+			wantErr: "voice must be one of alloy, ash, ballad, coral, echo, fable, nova, onyx, sage, shimmer, verse",
+		},
+		{
+			name:    "unknown format",
+			req:     models.SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "nova", Format: "midi"},
+			wantErr: "unsupported format midi",
+		},
+		{
+			name:    "speed out of range",
+			req:     models.SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "nova", Speed: 5},
+			wantErr: "speed must be between 0.25 and 4.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateSpeechRequest("openai", &tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAudioService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOpenAI := mocks.NewMockAudioRepositoryInterface(ctrl)
+	audioService := service.NewAudioService(mockOpenAI)
+
+	t.Run("transcriptions are trimmed", func(t *testing.T) {
+		req := &models.TranscriptionRequest{Model: "whisper-1", FileName: "memo.webm", Audio: strings.NewReader("audio")}
+		mockOpenAI.EXPECT().Transcribe(gomock.Any(), req).
+			Return(&models.Transcription{Model: "whisper-1", Text: " Remind me to call Ana. ", Language: "english"}, nil)
+
+		transcription, err := audioService.Transcribe(context.Background(), "openai", req)
+		require.NoError(t, err)
+		assert.Equal(t, "openai", transcription.Platform)
+		assert.Equal(t, "Remind me to call Ana.", transcription.Text)
+	})
+
+	t.Run("speech defaults to mp3", func(t *testing.T) {
+		mockOpenAI.EXPECT().Speak(gomock.Any(), &models.SpeechRequest{Model: "tts-1", Input: "Hi", Voice: "alloy", Format: "mp3"}).
+			Return(io.NopCloser(strings.NewReader("mp3 data")), nil)
+
+		audio, err := audioService.Speak(context.Background(), "openai", &models.SpeechRequest{Model: "tts-1", Input: "Hi", Voice: "alloy"})
+		require.NoError(t, err)
+		defer audio.Close()
+		data, err := io.ReadAll(audio)
+		require.NoError(t, err)
+		assert.Equal(t, "mp3 data", string(data))
+		assert.Equal(t, "audio/mpeg", service.SpeechContentType(""))
+	})
+}
@@ -51,7 +51,7 @@ func (s *AuthService) AuthenticateUser(ctx context.Context, email string, name s
 			ID:    googleID,
 			Name:  name,
 			Email: email,
-			Role:  "user",
+			Role:  models.RoleUser,
 		}
 		if err := s.userRepo.CreateUser(ctx, user); err != nil {
 			return nil, nil, fmt.Errorf("failed to create user: %w", err)
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+type ChatMemberService struct {
+	chatRepo repository.ChatRepositoryInterface
+	userRepo repository.UserRepositoryInterface
+}
+
+func NewChatMemberService(chatRepo repository.ChatRepositoryInterface,
+	userRepo repository.UserRepositoryInterface) *ChatMemberService {
+	return &ChatMemberService{
+		chatRepo: chatRepo,
+		userRepo: userRepo,
+	}
+}
+
+// ListMembers returns everyone in the chat, starting with the owner.
+func (s *ChatMemberService) ListMembers(ctx context.Context, chatID string) ([]models.ChatMember, error) {
+	chat, err := s.getChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := models.ChatMember{UserID: chat.User, Role: models.ChatRoleOwner, AddedAt: chat.CreatedAt}
+	if user, err := s.userRepo.GetUser(ctx, chat.User); err == nil {
+		owner.Email = user.Email
+	}
+	return append([]models.ChatMember{owner}, chat.Members...), nil
+}
+
+func (s *ChatMemberService) AddMember(ctx context.Context, chatID string, email string, role string) (*models.ChatMember, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, fmt.Errorf("email is required")
+	}
+	if err := validateMemberRole(role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("no user with email %s", email)
+	}
+
+	member := models.ChatMember{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Role:    role,
+		AddedAt: time.Now(),
+	}
+	err = s.updateMembers(ctx, chatID, func(chat *models.Chat) ([]models.ChatMember, error) {
+		if ChatRole(chat, user.ID) != "" {
+			return nil, fmt.Errorf("%s is already in the chat", email)
+		}
+		return append(chat.Members, member), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (s *ChatMemberService) UpdateMemberRole(ctx context.Context, chatID string, userID string, role string) (*models.ChatMember, error) {
+	if err := validateMemberRole(role); err != nil {
+		return nil, err
+	}
+
+	var updated models.ChatMember
+	err := s.updateMembers(ctx, chatID, func(chat *models.Chat) ([]models.ChatMember, error) {
+		for i := range chat.Members {
+			if chat.Members[i].UserID == userID {
+				chat.Members[i].Role = role
+				updated = chat.Members[i]
+				return chat.Members, nil
+			}
+		}
+		return nil, fmt.Errorf("member not found")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (s *ChatMemberService) RemoveMember(ctx context.Context, chatID string, userID string) error {
+	return s.updateMembers(ctx, chatID, func(chat *models.Chat) ([]models.ChatMember, error) {
+		members := make([]models.ChatMember, 0, len(chat.Members))
+		for _, member := range chat.Members {
+			if member.UserID != userID {
+				members = append(members, member)
+			}
+		}
+		if len(members) == len(chat.Members) {
+			return nil, fmt.Errorf("member not found")
+		}
+		return members, nil
+	})
+}
+
+// updateMembers stores the members change returns for the current chat. The
+// write is conditional on the version that was read, and is retried against
+// a fresh copy when the chat was written in the meantime.
+func (s *ChatMemberService) updateMembers(ctx context.Context, chatID string,
+	change func(*models.Chat) ([]models.ChatMember, error)) error {
+	for attempt := 1; ; attempt++ {
+		chat, err := s.getChat(ctx, chatID)
+		if err != nil {
+			return err
+		}
+
+		members, err := change(chat)
+		if err != nil {
+			return err
+		}
+		_, err = s.chatRepo.SetMembers(ctx, chat.ID, chat.Version, members)
+		if errors.Is(err, ErrVersionConflict) && attempt < maxUpdateAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+func (s *ChatMemberService) getChat(ctx context.Context, chatID string) (*models.Chat, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat ID is required")
+	}
+	return s.chatRepo.GetChat(ctx, chatID)
+}
+
+// ChatRole returns the user's role in the chat, or an empty string when they
+// have no access to it.
+func ChatRole(chat *models.Chat, userID string) string {
+	if chat.User == userID {
+		return models.ChatRoleOwner
+	}
+	for _, member := range chat.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// IsPrivateChat reports whether the user owns the chat and nobody else can
+// read it, so what is generated there may draw on the user's private data.
+func IsPrivateChat(chat *models.Chat, userID string) bool {
+	return chat.User == userID && len(chat.Members) == 0
+}
+
+// CanReadChat reports whether the user may see the chat and its messages.
+func CanReadChat(chat *models.Chat, userID string) bool {
+	return ChatRole(chat, userID) != ""
+}
+
+// CanPostToChat reports whether the user may add messages to the chat.
+func CanPostToChat(chat *models.Chat, userID string) bool {
+	role := ChatRole(chat, userID)
+	return role == models.ChatRoleOwner || role == models.ChatRoleEditor
+}
+
+func validateMemberRole(role string) error {
+	if role != models.ChatRoleEditor && role != models.ChatRoleViewer {
+		return fmt.Errorf("role must be %s or %s", models.ChatRoleEditor, models.ChatRoleViewer)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func sharedChat() *models.Chat {
+	return &models.Chat{
+		ID:   "chat-123",
+		User: "owner-1",
+		Members: []models.ChatMember{
+			{UserID: "editor-1", Email: "editor@example.com", Role: models.ChatRoleEditor},
+			{UserID: "viewer-1", Email: "viewer@example.com", Role: models.ChatRoleViewer},
+		},
+	}
+}
+
+func TestChatMemberService_AddMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	memberService := service.NewChatMemberService(mockChatRepo, mockUserRepo)
+
+	tests := []struct {
+		name    string
+		email   string
+		role    string
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name:  "invites a user by email",
+			email: " new@example.com ",
+			role:  models.ChatRoleViewer,
+			setup: func() {
+				mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(sharedChat(), nil)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "new@example.com").
+					Return(&models.User{ID: "new-1", Email: "new@example.com"}, nil)
+				mockChatRepo.EXPECT().SetMembers(gomock.Any(), "chat-123", int64(0), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, chatID string, version int64, members []models.ChatMember) (*models.Chat, error) {
+						require.Len(t, members, 3)
+						assert.Equal(t, "new-1", members[2].UserID)
+						assert.Equal(t, models.ChatRoleViewer, members[2].Role)
+						return nil, nil
+					})
+			},
+		},
+		{
+			name:  "already a member",
+			email: "editor@example.com",
+			role:  models.ChatRoleViewer,
+			setup: func() {
+				mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(sharedChat(), nil)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "editor@example.com").
+					Return(&models.User{ID: "editor-1", Email: "editor@example.com"}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:  "unknown email",
+			email: "nobody@example.com",
+			role:  models.ChatRoleEditor,
+			setup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@example.com").
+					Return(nil, fmt.Errorf("user not found"))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "owner role cannot be granted",
+			email:   "new@example.com",
+			role:    models.ChatRoleOwner,
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			member, err := memberService.AddMember(context.Background(), "chat-123", tt.email, tt.role)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.role, member.Role)
+		})
+	}
+}
+
+func TestChatMemberService_RemoveMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	memberService := service.NewChatMemberService(mockChatRepo, mocks.NewMockUserRepositoryInterface(ctrl))
+
+	mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(sharedChat(), nil)
+	mockChatRepo.EXPECT().SetMembers(gomock.Any(), "chat-123", int64(0), []models.ChatMember{sharedChat().Members[1]}).Return(nil, nil)
+	require.NoError(t, memberService.RemoveMember(context.Background(), "chat-123", "editor-1"))
+
+	// A concurrent write is retried against the chat as it is now.
+	stale := sharedChat()
+	current := sharedChat()
+	current.Version = 1
+	current.Members = append(current.Members, models.ChatMember{UserID: "new-1", Role: models.ChatRoleViewer})
+	gomock.InOrder(
+		mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(stale, nil),
+		mockChatRepo.EXPECT().SetMembers(gomock.Any(), "chat-123", int64(0), gomock.Any()).Return(nil, service.ErrVersionConflict),
+		mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(current, nil),
+		mockChatRepo.EXPECT().SetMembers(gomock.Any(), "chat-123", int64(1),
+			[]models.ChatMember{current.Members[1], current.Members[2]}).Return(nil, nil),
+	)
+	require.NoError(t, memberService.RemoveMember(context.Background(), "chat-123", "editor-1"))
+
+	mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(sharedChat(), nil)
+	assert.Error(t, memberService.RemoveMember(context.Background(), "chat-123", "stranger-1"))
+}
+
+func TestChatRole(t *testing.T) {
+	chat := sharedChat()
+
+	tests := []struct {
+		userID   string
+		wantRole string
+		canRead  bool
+		canPost  bool
+	}{
+		{userID: "owner-1", wantRole: models.ChatRoleOwner, canRead: true, canPost: true},
+		{userID: "editor-1", wantRole: models.ChatRoleEditor, canRead: true, canPost: true},
+		{userID: "viewer-1", wantRole: models.ChatRoleViewer, canRead: true, canPost: false},
+		{userID: "stranger-1", wantRole: "", canRead: false, canPost: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			assert.Equal(t, tt.wantRole, service.ChatRole(chat, tt.userID))
+			assert.Equal(t, tt.canRead, service.CanReadChat(chat, tt.userID))
+			assert.Equal(t, tt.canPost, service.CanPostToChat(chat, tt.userID))
+			assert.False(t, service.IsPrivateChat(chat, tt.userID), "the chat has members")
+		})
+	}
+
+	private := &models.Chat{ID: "chat-456", User: "owner-1"}
+	assert.True(t, service.IsPrivateChat(private, "owner-1"))
+	assert.False(t, service.IsPrivateChat(private, "stranger-1"))
+}
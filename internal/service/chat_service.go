@@ -2,33 +2,59 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/lutefd/ai-router-go/internal/catalog"
 	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/repository"
 	"github.com/lutefd/ai-router-go/pkg/idgen"
 )
 
+const maxUpdateAttempts = 3
+
+// ErrVersionConflict is returned when a chat update was made against a
+// version of the chat that is no longer current.
+var ErrVersionConflict = repository.ErrVersionConflict
+
 type ChatService struct {
-	chatRepo repository.ChatRepositoryInterface
+	chatRepo       repository.ChatRepositoryInterface
+	attachmentRepo repository.AttachmentRepositoryInterface
 }
 
-func NewChatService(chatRepo repository.ChatRepositoryInterface) *ChatService {
+func NewChatService(chatRepo repository.ChatRepositoryInterface,
+	attachmentRepo repository.AttachmentRepositoryInterface) *ChatService {
 	return &ChatService{
-		chatRepo: chatRepo,
+		chatRepo:       chatRepo,
+		attachmentRepo: attachmentRepo,
 	}
 }
 
+// CreateChat stores a new, empty chat. Only its owner, title, settings,
+// assistant, folder and tags are kept; members, knowledge bases and the
+// state maintained by the server start out empty.
 func (s *ChatService) CreateChat(ctx context.Context, chat *models.Chat) error {
-	if chat.Title == "" {
-		return fmt.Errorf("chat title is required")
+	if err := validateSettings(chat.Settings); err != nil {
+		return err
 	}
 
-	chat.ID = generateID()
-	chat.CreatedAt = time.Now()
-	chat.UpdatedAt = time.Now()
-	chat.Messages = []models.Message{}
+	now := time.Now()
+	*chat = models.Chat{
+		ID:          generateID(),
+		User:        chat.User,
+		Title:       chat.Title,
+		FolderID:    chat.FolderID,
+		Tags:        chat.Tags,
+		Messages:    []models.Message{},
+		Settings:    chat.Settings,
+		AssistantID: chat.AssistantID,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
 
 	return s.chatRepo.CreateChat(ctx, chat)
 }
@@ -41,6 +67,8 @@ func (s *ChatService) GetChat(ctx context.Context, id string) (*models.Chat, err
 	return s.chatRepo.GetChat(ctx, id)
 }
 
+// UpdateChat renames the chat. When chat.Version is set the update only
+// applies to that version of the chat. On success chat holds the stored chat.
 func (s *ChatService) UpdateChat(ctx context.Context, chat *models.Chat) error {
 	if chat.ID == "" {
 		return fmt.Errorf("chat ID is required")
@@ -49,25 +77,339 @@ func (s *ChatService) UpdateChat(ctx context.Context, chat *models.Chat) error {
 		return fmt.Errorf("chat title is required")
 	}
 
-	existingChat, err := s.chatRepo.GetChat(ctx, chat.ID)
+	updated, err := s.modifyChat(ctx, chat.ID, chat.Version, func(existing *models.Chat) {
+		existing.Title = chat.Title
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get chat: %w", err)
+		return err
+	}
+	*chat = *updated
+	return nil
+}
+
+// DeleteChat moves the chat to the trash. A non-zero version makes it fail
+// with ErrVersionConflict when the chat is no longer at that version.
+func (s *ChatService) DeleteChat(ctx context.Context, id string, version int64) error {
+	if id == "" {
+		return fmt.Errorf("chat ID is required")
+	}
+
+	_, err := s.chatRepo.DeleteChat(ctx, id, version)
+	return err
+}
+
+func (s *ChatService) AddMessages(ctx context.Context, id string, messages ...models.Message) (*models.Chat, error) {
+	if id == "" {
+		return nil, fmt.Errorf("chat ID is required")
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
+	}
+
+	now := time.Now()
+	for i := range messages {
+		if messages[i].ID == "" {
+			messages[i].ID = generateID()
+		}
+		if messages[i].SentAt.IsZero() {
+			messages[i].SentAt = now
+		}
+	}
+
+	return s.modifyChat(ctx, id, 0, func(chat *models.Chat) {
+		chat.Messages = append(chat.Messages, messages...)
+	})
+}
+
+func (s *ChatService) UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) (int64, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("user ID is required")
+	}
+	if len(chatIDs) == 0 {
+		return 0, fmt.Errorf("at least one chat ID is required")
+	}
+
+	tagOps := 0
+	for _, tags := range [][]string{update.Tags, update.AddTags, update.RemoveTags} {
+		if tags != nil {
+			tagOps++
+		}
+	}
+	if tagOps > 1 {
+		return 0, fmt.Errorf("tags, add_tags and remove_tags cannot be combined")
+	}
+	if update.Tags != nil {
+		update.Tags = normalizeTags(update.Tags)
 	}
+	update.AddTags = normalizeTags(update.AddTags)
+	update.RemoveTags = normalizeTags(update.RemoveTags)
 
-	existingChat.Title = chat.Title
-	existingChat.UpdatedAt = time.Now()
+	return chatCount(s.chatRepo.UpdateChats(ctx, userID, chatIDs, update))
+}
+
+func (s *ChatService) DeleteChats(ctx context.Context, userID string, chatIDs []string) (int64, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("user ID is required")
+	}
+	if len(chatIDs) == 0 {
+		return 0, fmt.Errorf("at least one chat ID is required")
+	}
+
+	return chatCount(s.chatRepo.DeleteChats(ctx, userID, chatIDs))
+}
+
+func (s *ChatService) ListTrash(ctx context.Context, userID string) ([]*models.UserChat, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	return s.chatRepo.ListDeletedChats(ctx, userID)
+}
+
+func (s *ChatService) RestoreChat(ctx context.Context, userID string, id string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if id == "" {
+		return fmt.Errorf("chat ID is required")
+	}
 
-	return s.chatRepo.UpdateChat(ctx, existingChat)
+	_, err := s.chatRepo.RestoreChat(ctx, userID, id)
+	return err
 }
 
-func (s *ChatService) DeleteChat(ctx context.Context, id string) error {
+func (s *ChatService) PurgeChat(ctx context.Context, userID string, id string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
 	if id == "" {
 		return fmt.Errorf("chat ID is required")
 	}
 
-	return s.chatRepo.DeleteChat(ctx, id)
+	return s.chatRepo.PurgeChat(ctx, userID, id)
+}
+
+func (s *ChatService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention < 0 {
+		return 0, fmt.Errorf("retention must not be negative")
+	}
+
+	return s.chatRepo.PurgeDeletedChats(ctx, time.Now().Add(-retention))
+}
+
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// chatCount turns the chats written by a bulk update into the number of chats
+// affected.
+func chatCount(chats []*models.Chat, err error) (int64, error) {
+	return int64(len(chats)), err
 }
 
 func generateID() string {
 	return idgen.Generate()
 }
+
+func (s *ChatService) ResetSummary(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("chat ID is required")
+	}
+	_, err := s.chatRepo.SetSummary(ctx, id, nil)
+	return err
+}
+
+func (s *ChatService) UpdateSettings(ctx context.Context, id string, version int64, settings models.ChatSettings) (*models.Chat, error) {
+	if id == "" {
+		return nil, fmt.Errorf("chat ID is required")
+	}
+	if err := validateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return s.modifyChat(ctx, id, version, func(chat *models.Chat) {
+		chat.Settings = settings
+	})
+}
+
+// modifyChat applies modify to the stored chat and writes it back. With a
+// version the write fails with ErrVersionConflict unless the chat
+// is still at that version; without one, conflicting writes are retried on
+// the latest chat.
+func (s *ChatService) modifyChat(ctx context.Context, id string, version int64,
+	modify func(*models.Chat)) (*models.Chat, error) {
+	for attempt := 1; ; attempt++ {
+		chat, err := s.chatRepo.GetChat(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chat: %w", err)
+		}
+		if version != 0 && chat.Version != version {
+			return nil, ErrVersionConflict
+		}
+
+		modify(chat)
+		chat.UpdatedAt = time.Now()
+		_, err = s.chatRepo.UpdateChat(ctx, chat)
+		if errors.Is(err, ErrVersionConflict) && version == 0 && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return chat, nil
+	}
+}
+
+// ForkChat copies the messages of a chat up to and including messageID into
+// a new chat owned by userID. Their attachments are copied along, so the fork
+// keeps them when the original chat is purged.
+func (s *ChatService) ForkChat(ctx context.Context, userID string, id string, messageID string) (*models.Chat, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("chat ID is required")
+	}
+	if messageID == "" {
+		return nil, fmt.Errorf("message ID is required")
+	}
+
+	source, err := s.chatRepo.GetChat(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat: %w", err)
+	}
+
+	end := -1
+	for i, message := range source.Messages {
+		if message.ID == messageID {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("message %s not found in chat", messageID)
+	}
+
+	now := time.Now()
+	fork := &models.Chat{
+		ID:          generateID(),
+		User:        userID,
+		Title:       source.Title,
+		Tags:        source.Tags,
+		Messages:    make([]models.Message, 0, end+1),
+		Settings:    source.Settings,
+		AssistantID: source.AssistantID,
+		ForkedFrom:  &models.ForkSource{ChatID: source.ID, MessageID: messageID},
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if source.User == userID {
+		fork.FolderID = source.FolderID
+		fork.KnowledgeBaseIDs = source.KnowledgeBaseIDs
+	}
+	for _, message := range source.Messages[:end+1] {
+		message.ID = generateID()
+		message.Attachments = s.copyAttachments(ctx, source, fork.ID, message.Attachments)
+		fork.Messages = append(fork.Messages, message)
+	}
+
+	if err := s.chatRepo.CreateChat(ctx, fork); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+// copyAttachments stores a copy of each attachment for the chat chatID and
+// returns the copies. Attachments that do not belong to the source chat or
+// can no longer be read are left out.
+func (s *ChatService) copyAttachments(ctx context.Context, source *models.Chat, chatID string,
+	attachments []models.Attachment) []models.Attachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	copies := make([]models.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		stored, err := s.attachmentRepo.GetAttachment(ctx, attachment.ID)
+		if err == nil && stored.ChatID != source.ID && ChatRole(source, stored.User) == "" {
+			err = fmt.Errorf("attachment not found")
+		}
+		var data []byte
+		if err == nil {
+			data, err = s.attachmentRepo.ReadAttachment(ctx, stored.ID)
+		}
+		if err != nil {
+			log.Printf("Skipping attachment %s of forked chat %s: %v", attachment.ID, source.ID, err)
+			continue
+		}
+
+		stored.ID = idgen.GenerateWithPrefix("att")
+		stored.ChatID = chatID
+		if err := s.attachmentRepo.SaveAttachment(ctx, stored, data); err != nil {
+			log.Printf("Error copying attachment %s of forked chat %s: %v", attachment.ID, source.ID, err)
+			continue
+		}
+		stored.Text = ""
+		copies = append(copies, *stored)
+	}
+	return copies
+}
+
+// ResolveSettings applies the per-request overrides on top of the chat
+// defaults.
+func ResolveSettings(defaults models.ChatSettings, overrides models.ChatSettings) models.ChatSettings {
+	resolved := defaults
+	if overrides.SystemPrompt != "" {
+		resolved.SystemPrompt = overrides.SystemPrompt
+	}
+	if overrides.Platform != "" {
+		resolved.Platform = overrides.Platform
+	}
+	if overrides.Model != "" {
+		resolved.Model = overrides.Model
+	}
+	if overrides.Sampling.Temperature != nil {
+		resolved.Sampling.Temperature = overrides.Sampling.Temperature
+	}
+	if overrides.Sampling.TopP != nil {
+		resolved.Sampling.TopP = overrides.Sampling.TopP
+	}
+	if overrides.Sampling.MaxTokens != nil {
+		resolved.Sampling.MaxTokens = overrides.Sampling.MaxTokens
+	}
+	return resolved
+}
+
+func validateSettings(settings models.ChatSettings) error {
+	if settings.Platform != "" && !catalog.KnownPlatform(settings.Platform) {
+		return fmt.Errorf("unsupported platform: %s", settings.Platform)
+	}
+	return ValidateSampling(settings.Sampling)
+}
+
+func ValidateSampling(sampling models.SamplingParams) error {
+	if t := sampling.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if p := sampling.TopP; p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1")
+	}
+	if m := sampling.MaxTokens; m != nil && *m < 1 {
+		return fmt.Errorf("max_tokens must be positive")
+	}
+	return nil
+}
@@ -29,7 +29,7 @@ func TestChatService_CreateChat(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
-	chatService := service.NewChatService(mockRepo)
+	chatService := service.NewChatService(mockRepo, nil)
 
 	tests := []struct {
 		name    string
@@ -56,8 +56,12 @@ func TestChatService_CreateChat(t *testing.T) {
 				Title: "",
 				User:  "user-123",
 			},
-			setup:   func() {},
-			wantErr: true,
+			setup: func() {
+				mockRepo.EXPECT().
+					CreateChat(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantErr: false,
 		},
 		{
 			name: "repository error",
@@ -93,12 +97,47 @@ func TestChatService_CreateChat(t *testing.T) {
 	}
 }
 
+func TestChatService_CreateChatDropsServerState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	extractedAt := time.Now()
+	chat := &models.Chat{
+		ID:                "chosen-id",
+		User:              "user-123",
+		Title:             "Plans",
+		Members:           []models.ChatMember{{UserID: "user-456", Role: models.ChatRoleOwner}},
+		KnowledgeBaseIDs:  []string{"kb-1"},
+		Summary:           &models.Summary{Text: "forged"},
+		ForkedFrom:        &models.ForkSource{ChatID: "chat-9"},
+		MemoryExtractedAt: &extractedAt,
+		DeletedAt:         &extractedAt,
+		Version:           7,
+	}
+	mockRepo.EXPECT().CreateChat(gomock.Any(), chat).Return(nil)
+
+	require.NoError(t, chatService.CreateChat(context.Background(), chat))
+	assert.NotEqual(t, "chosen-id", chat.ID)
+	assert.Equal(t, "user-123", chat.User)
+	assert.Equal(t, "Plans", chat.Title)
+	assert.Empty(t, chat.Members)
+	assert.Empty(t, chat.KnowledgeBaseIDs)
+	assert.Nil(t, chat.Summary)
+	assert.Nil(t, chat.ForkedFrom)
+	assert.Nil(t, chat.MemoryExtractedAt)
+	assert.Nil(t, chat.DeletedAt)
+	assert.Equal(t, int64(1), chat.Version)
+}
+
 func TestChatService_GetChat(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
-	chatService := service.NewChatService(mockRepo)
+	chatService := service.NewChatService(mockRepo, nil)
 
 	testChat := &models.Chat{
 		ID:        "chat-123",
@@ -167,7 +206,7 @@ func TestChatService_UpdateChat(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
-	chatService := service.NewChatService(mockRepo)
+	chatService := service.NewChatService(mockRepo, nil)
 
 	existingChat := &models.Chat{
 		ID:        "chat-123",
@@ -195,7 +234,7 @@ func TestChatService_UpdateChat(t *testing.T) {
 					Return(existingChat, nil)
 				mockRepo.EXPECT().
 					UpdateChat(gomock.Any(), gomock.Any()).
-					Return(nil)
+					Return(nil, nil)
 			},
 			wantErr: false,
 		},
@@ -250,7 +289,7 @@ func TestChatService_DeleteChat(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
-	chatService := service.NewChatService(mockRepo)
+	chatService := service.NewChatService(mockRepo, nil)
 
 	tests := []struct {
 		name    string
@@ -263,8 +302,8 @@ func TestChatService_DeleteChat(t *testing.T) {
 			chatID: "chat-123",
 			setup: func() {
 				mockRepo.EXPECT().
-					DeleteChat(gomock.Any(), "chat-123").
-					Return(nil)
+					DeleteChat(gomock.Any(), "chat-123", int64(0)).
+					Return(nil, nil)
 			},
 			wantErr: false,
 		},
@@ -279,8 +318,8 @@ func TestChatService_DeleteChat(t *testing.T) {
 			chatID: "nonexistent",
 			setup: func() {
 				mockRepo.EXPECT().
-					DeleteChat(gomock.Any(), "nonexistent").
-					Return(fmt.Errorf("not found"))
+					DeleteChat(gomock.Any(), "nonexistent", int64(0)).
+					Return(nil, fmt.Errorf("not found"))
 			},
 			wantErr: true,
 		},
@@ -290,7 +329,7 @@ func TestChatService_DeleteChat(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
 
-			err := chatService.DeleteChat(context.Background(), tt.chatID)
+			err := chatService.DeleteChat(context.Background(), tt.chatID, 0)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -300,3 +339,481 @@ func TestChatService_DeleteChat(t *testing.T) {
 		})
 	}
 }
+
+func TestChatService_AddMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	tests := []struct {
+		name     string
+		chatID   string
+		messages []models.Message
+		setup    func()
+		wantErr  bool
+	}{
+		{
+			name:   "successful append",
+			chatID: "chat-123",
+			messages: []models.Message{
+				{Text: "Hello", Role: "user"},
+				{Text: "Hi there", Role: "assistant", AI: "gpt-4o"},
+			},
+			setup: func() {
+				mockRepo.EXPECT().
+					GetChat(gomock.Any(), "chat-123").
+					Return(&models.Chat{ID: "chat-123", User: "user-123"}, nil)
+				mockRepo.EXPECT().
+					UpdateChat(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "empty ID",
+			chatID:   "",
+			messages: []models.Message{{Text: "Hello", Role: "user"}},
+			setup:    func() {},
+			wantErr:  true,
+		},
+		{
+			name:     "no messages",
+			chatID:   "chat-123",
+			messages: nil,
+			setup:    func() {},
+			wantErr:  true,
+		},
+		{
+			name:     "not found",
+			chatID:   "nonexistent",
+			messages: []models.Message{{Text: "Hello", Role: "user"}},
+			setup: func() {
+				mockRepo.EXPECT().
+					GetChat(gomock.Any(), "nonexistent").
+					Return(nil, fmt.Errorf("not found"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			chat, err := chatService.AddMessages(context.Background(), tt.chatID, tt.messages...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, chat.Messages, len(tt.messages))
+			for _, message := range chat.Messages {
+				assert.NotEmpty(t, message.ID)
+				assert.False(t, message.SentAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestChatService_UpdateChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	pinned := true
+
+	tests := []struct {
+		name    string
+		chatIDs []string
+		update  models.ChatUpdate
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name:    "pin",
+			chatIDs: []string{"chat-1"},
+			update:  models.ChatUpdate{Pinned: &pinned},
+			setup: func() {
+				mockRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1"}, models.ChatUpdate{Pinned: &pinned}).
+					Return([]*models.Chat{{ID: "chat-1"}}, nil)
+			},
+		},
+		{
+			name:    "tags are trimmed and deduplicated",
+			chatIDs: []string{"chat-1", "chat-2"},
+			update:  models.ChatUpdate{AddTags: []string{" work ", "work", "", "go"}},
+			setup: func() {
+				mockRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1", "chat-2"},
+						models.ChatUpdate{AddTags: []string{"work", "go"}}).
+					Return([]*models.Chat{{ID: "chat-1"}, {ID: "chat-2"}}, nil)
+			},
+		},
+		{
+			name:    "conflicting tag operations",
+			chatIDs: []string{"chat-1"},
+			update:  models.ChatUpdate{AddTags: []string{"a"}, RemoveTags: []string{"b"}},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "no chat IDs",
+			update:  models.ChatUpdate{Pinned: &pinned},
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			_, err := chatService.UpdateChats(context.Background(), "user-123", tt.chatIDs, tt.update)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestChatService_RestoreChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	tests := []struct {
+		name    string
+		chatID  string
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name:   "successful restore",
+			chatID: "chat-123",
+			setup: func() {
+				mockRepo.EXPECT().
+					RestoreChat(gomock.Any(), "user-123", "chat-123").
+					Return(nil, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "empty ID",
+			chatID:  "",
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:   "not in trash",
+			chatID: "chat-456",
+			setup: func() {
+				mockRepo.EXPECT().
+					RestoreChat(gomock.Any(), "user-123", "chat-456").
+					Return(nil, fmt.Errorf("chat not found"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := chatService.RestoreChat(context.Background(), "user-123", tt.chatID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestChatService_PurgeTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	retention := 7 * 24 * time.Hour
+	mockRepo.EXPECT().
+		PurgeDeletedChats(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().Add(-retention), before, time.Minute)
+			return 2, nil
+		})
+
+	purged, err := chatService.PurgeTrash(context.Background(), retention)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	_, err = chatService.PurgeTrash(context.Background(), -time.Hour)
+	assert.Error(t, err)
+}
+
+func TestChatService_UpdateSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	temperature := 0.2
+	tooHot := 3.0
+
+	tests := []struct {
+		name     string
+		version  int64
+		settings models.ChatSettings
+		setup    func()
+		wantErr  bool
+	}{
+		{
+			name: "successful update",
+			settings: models.ChatSettings{
+				SystemPrompt: "Answer like a pirate.",
+				Platform:     "openai",
+				Model:        "gpt-4o",
+				Sampling:     models.SamplingParams{Temperature: &temperature},
+			},
+			setup: func() {
+				mockRepo.EXPECT().
+					GetChat(gomock.Any(), "chat-123").
+					Return(&models.Chat{ID: "chat-123", Title: "Pirates"}, nil)
+				mockRepo.EXPECT().
+					UpdateChat(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, chat *models.Chat) (*models.Chat, error) {
+						assert.Equal(t, "Answer like a pirate.", chat.Settings.SystemPrompt)
+						assert.Equal(t, "Pirates", chat.Title)
+						return nil, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:     "unknown platform",
+			settings: models.ChatSettings{Platform: "mystery"},
+			setup:    func() {},
+			wantErr:  true,
+		},
+		{
+			name:     "temperature out of range",
+			settings: models.ChatSettings{Sampling: models.SamplingParams{Temperature: &tooHot}},
+			setup:    func() {},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			chat, err := chatService.UpdateSettings(context.Background(), "chat-123", tt.version, tt.settings)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.settings, chat.Settings)
+		})
+	}
+}
+
+func TestChatService_VersionConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, nil)
+
+	t.Run("stale version is rejected", func(t *testing.T) {
+		mockRepo.EXPECT().
+			GetChat(gomock.Any(), "chat-123").
+			Return(&models.Chat{ID: "chat-123", Title: "Original", Version: 4}, nil)
+
+		err := chatService.UpdateChat(context.Background(), &models.Chat{ID: "chat-123", Title: "Renamed", Version: 3})
+		assert.ErrorIs(t, err, service.ErrVersionConflict)
+	})
+
+	t.Run("conditional write that loses the race is not retried", func(t *testing.T) {
+		mockRepo.EXPECT().
+			GetChat(gomock.Any(), "chat-123").
+			Return(&models.Chat{ID: "chat-123", Version: 4}, nil)
+		mockRepo.EXPECT().
+			UpdateChat(gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrVersionConflict)
+
+		_, err := chatService.UpdateSettings(context.Background(), "chat-123", 4, models.ChatSettings{})
+		assert.ErrorIs(t, err, service.ErrVersionConflict)
+	})
+
+	t.Run("appending messages retries on the latest chat", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().
+				GetChat(gomock.Any(), "chat-123").
+				Return(&models.Chat{ID: "chat-123", Version: 4}, nil),
+			mockRepo.EXPECT().
+				UpdateChat(gomock.Any(), gomock.Any()).
+				Return(nil, service.ErrVersionConflict),
+			mockRepo.EXPECT().
+				GetChat(gomock.Any(), "chat-123").
+				Return(&models.Chat{ID: "chat-123", Version: 5, Summary: &models.Summary{Text: "Earlier"}}, nil),
+			mockRepo.EXPECT().
+				UpdateChat(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, chat *models.Chat) (*models.Chat, error) {
+					assert.Equal(t, int64(5), chat.Version)
+					chat.Version++
+					return nil, nil
+				}),
+		)
+
+		chat, err := chatService.AddMessages(context.Background(), "chat-123", models.Message{Text: "Hello", Role: "user"})
+		require.NoError(t, err)
+		assert.Len(t, chat.Messages, 1)
+		assert.NotNil(t, chat.Summary)
+		assert.Equal(t, int64(6), chat.Version)
+	})
+}
+
+func TestChatService_ForkChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	mockAttachmentRepo := mocks.NewMockAttachmentRepositoryInterface(ctrl)
+	chatService := service.NewChatService(mockRepo, mockAttachmentRepo)
+
+	source := &models.Chat{
+		ID:       "chat-123",
+		User:     "user-123",
+		Title:    "Original",
+		FolderID: "folder-1",
+		Pinned:   true,
+		Settings: models.ChatSettings{SystemPrompt: "Be brief."},
+		Summary:  &models.Summary{Text: "Earlier", LastMessageID: "msg-1"},
+		Messages: []models.Message{
+			{ID: "msg-1", Role: "user", Text: "first", Attachments: []models.Attachment{
+				{ID: "att-1", Kind: models.AttachmentKindImage},
+				{ID: "att-2", Kind: models.AttachmentKindImage},
+			}},
+			{ID: "msg-2", Role: "assistant", Text: "second"},
+			{ID: "msg-3", Role: "user", Text: "third"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		messageID string
+		setup     func()
+		wantTexts []string
+		wantErr   bool
+	}{
+		{
+			name:      "forks up to the message",
+			messageID: "msg-2",
+			setup: func() {
+				mockRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(source, nil)
+				mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-1").
+					Return(&models.Attachment{ID: "att-1", User: "user-123", ChatID: "chat-123", Kind: models.AttachmentKindImage}, nil)
+				mockAttachmentRepo.EXPECT().ReadAttachment(gomock.Any(), "att-1").Return([]byte("png"), nil)
+				mockAttachmentRepo.EXPECT().SaveAttachment(gomock.Any(), gomock.Any(), []byte("png")).
+					DoAndReturn(func(ctx context.Context, attachment *models.Attachment, data []byte) error {
+						assert.NotEqual(t, "att-1", attachment.ID)
+						assert.NotEqual(t, "chat-123", attachment.ChatID)
+						return nil
+					})
+				mockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), "att-2").
+					Return(&models.Attachment{ID: "att-2", User: "stranger-1", ChatID: "chat-999"}, nil)
+				mockRepo.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantTexts: []string{"first", "second"},
+		},
+		{
+			name:      "unknown message",
+			messageID: "msg-9",
+			setup: func() {
+				mockRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(source, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "missing message ID",
+			messageID: "",
+			setup:     func() {},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			fork, err := chatService.ForkChat(context.Background(), "user-123", "chat-123", tt.messageID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, source.ID, fork.ID)
+			assert.Equal(t, "user-123", fork.User)
+			assert.Equal(t, &models.ForkSource{ChatID: "chat-123", MessageID: tt.messageID}, fork.ForkedFrom)
+			assert.Equal(t, source.Settings, fork.Settings)
+			assert.Equal(t, "folder-1", fork.FolderID)
+			assert.False(t, fork.Pinned)
+			assert.Nil(t, fork.Summary)
+
+			var texts []string
+			for i, message := range fork.Messages {
+				texts = append(texts, message.Text)
+				assert.NotEqual(t, source.Messages[i].ID, message.ID)
+			}
+			assert.Equal(t, tt.wantTexts, texts)
+			assert.Equal(t, "msg-1", source.Messages[0].ID)
+
+			require.Len(t, fork.Messages[0].Attachments, 1, "attachments outside the chat are not copied")
+			assert.Equal(t, fork.ID, fork.Messages[0].Attachments[0].ChatID)
+			assert.Equal(t, "att-1", source.Messages[0].Attachments[0].ID)
+		})
+	}
+}
+
+func TestResolveSettings(t *testing.T) {
+	temperature, override := 0.7, 0.0
+	maxTokens := 512
+
+	defaults := models.ChatSettings{
+		SystemPrompt: "Be brief.",
+		Platform:     "openai",
+		Model:        "gpt-4o",
+		Sampling:     models.SamplingParams{Temperature: &temperature, MaxTokens: &maxTokens},
+	}
+
+	resolved := service.ResolveSettings(defaults, models.ChatSettings{})
+	assert.Equal(t, defaults, resolved)
+
+	resolved = service.ResolveSettings(defaults, models.ChatSettings{
+		Model:    "gpt-4o-mini",
+		Sampling: models.SamplingParams{Temperature: &override},
+	})
+	assert.Equal(t, "openai", resolved.Platform)
+	assert.Equal(t, "gpt-4o-mini", resolved.Model)
+	assert.Equal(t, "Be brief.", resolved.SystemPrompt)
+	assert.Equal(t, 0.0, *resolved.Sampling.Temperature)
+	assert.Equal(t, 512, *resolved.Sampling.MaxTokens)
+	assert.Equal(t, 0.7, *defaults.Sampling.Temperature)
+}
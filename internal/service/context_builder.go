@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const (
+	ContextDropOldest = "drop_oldest"
+	ContextLastTurns  = "last_turns"
+	ContextSummarize  = "summarize"
+)
+
+const summaryGenTimeout = 30 * time.Second
+
+const summaryPrompt = `Summarize the earlier part of the conversation below so it can replace it.
+Keep facts, decisions, names and open questions. Be concise and write in the
+language of the conversation. Reply with the summary only.
+
+%s`
+
+// ContextReport tells the client how the history sent to the model differs
+// from the stored chat.
+type ContextReport struct {
+	Trimmed         bool   `json:"trimmed"`
+	Strategy        string `json:"strategy"`
+	DroppedMessages int    `json:"dropped_messages"`
+	Summarized      bool   `json:"summarized"`
+	EstimatedTokens int    `json:"estimated_tokens"`
+	Budget          int    `json:"budget"`
+}
+
+type ContextBuilder struct {
+	generator       GeneratorInterface
+	strategy        string
+	lastTurns       int
+	summaryPlatform string
+	summaryModel    string
+}
+
+func NewContextBuilder(generator GeneratorInterface, strategy string, lastTurns int,
+	summaryPlatform string, summaryModel string) *ContextBuilder {
+	return &ContextBuilder{
+		generator:       generator,
+		strategy:        strategy,
+		lastTurns:       lastTurns,
+		summaryPlatform: summaryPlatform,
+		summaryModel:    summaryModel,
+	}
+}
+
+// BuildContext fits messages into the context window of the target model.
+// Leading system messages are always kept; the rest is handled in turns, each
+// starting at a user message, so a question is never sent without its answer.
+func (b *ContextBuilder) BuildContext(ctx context.Context, platform string, model string,
+	messages []models.Message) ([]models.Message, *ContextReport, error) {
+	info := catalog.Lookup(platform, model)
+	report := &ContextReport{Strategy: b.strategy, Budget: info.InputBudget()}
+
+	system, turns := splitTurns(messages)
+	if len(turns) == 0 {
+		return nil, nil, fmt.Errorf("conversation has no messages")
+	}
+
+	systemTokens := estimateMessages(info, system)
+	turnTokens := make([]int, len(turns))
+	total := systemTokens
+	for i, turn := range turns {
+		turnTokens[i] = estimateMessages(info, turn)
+		total += turnTokens[i]
+	}
+
+	if systemTokens+turnTokens[len(turns)-1] > report.Budget {
+		return nil, nil, fmt.Errorf("message is too long for model %s: about %d tokens, limit is %d",
+			model, systemTokens+turnTokens[len(turns)-1], report.Budget)
+	}
+
+	start := 0
+	if b.strategy == ContextLastTurns && len(turns) > b.lastTurns {
+		start = len(turns) - b.lastTurns
+		for _, tokens := range turnTokens[:start] {
+			total -= tokens
+		}
+	}
+	for total > report.Budget {
+		total -= turnTokens[start]
+		start++
+	}
+
+	if start == 0 {
+		report.EstimatedTokens = total
+		return messages, report, nil
+	}
+
+	report.Trimmed = true
+	for _, turn := range turns[:start] {
+		report.DroppedMessages += len(turn)
+	}
+
+	kept := append([]models.Message{}, system...)
+	if b.strategy == ContextSummarize {
+		summary, tokens, ok := b.summarize(ctx, info, turns[:start], report.Budget-total)
+		if ok {
+			kept = append(kept, summary)
+			total += tokens
+			report.Summarized = true
+		}
+	}
+	for _, turn := range turns[start:] {
+		kept = append(kept, turn...)
+	}
+
+	report.EstimatedTokens = total
+	return kept, report, nil
+}
+
+// summarize condenses the dropped turns into a single system message. It
+// reports false when the summary could not be produced or does not fit in the
+// remaining budget, in which case the turns are simply dropped.
+func (b *ContextBuilder) summarize(ctx context.Context, info catalog.ModelInfo,
+	turns [][]models.Message, room int) (models.Message, int, bool) {
+	var dropped []models.Message
+	for _, turn := range turns {
+		dropped = append(dropped, turn...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, summaryGenTimeout)
+	defer cancel()
+
+	var sb strings.Builder
+	err := b.generator.GenerateResponse(ctx, b.summaryPlatform, b.summaryModel,
+		fmt.Sprintf(summaryPrompt, transcript(dropped)), func(chunk string) {
+			sb.WriteString(chunk)
+		})
+	if err != nil {
+		log.Printf("Context summary failed, dropping older turns instead: %v", err)
+		return models.Message{}, 0, false
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return models.Message{}, 0, false
+	}
+	summary := models.Message{
+		Role: "system",
+		Text: summaryPreamble + text,
+	}
+	tokens := info.EstimateMessageTokens(summary.Text)
+	if tokens > room {
+		log.Printf("Context summary does not fit in the remaining budget, dropping older turns instead")
+		return models.Message{}, 0, false
+	}
+	return summary, tokens, true
+}
+
+func splitTurns(messages []models.Message) ([]models.Message, [][]models.Message) {
+	i := 0
+	for i < len(messages) && messages[i].Role == "system" {
+		i++
+	}
+
+	var turns [][]models.Message
+	for _, message := range messages[i:] {
+		if message.Role == "user" || len(turns) == 0 {
+			turns = append(turns, []models.Message{})
+		}
+		turns[len(turns)-1] = append(turns[len(turns)-1], message)
+	}
+	return messages[:i], turns
+}
+
+func estimateMessages(info catalog.ModelInfo, messages []models.Message) int {
+	total := 0
+	for _, message := range messages {
+		total += info.EstimateMessageTokens(message.Text)
+		for _, attachment := range message.Attachments {
+			if attachment.Kind == models.AttachmentKindImage {
+				total += catalog.ImageTokens
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// gpt-4 has an input budget of 4096 tokens; each of these messages is
+// estimated at 1004 tokens, so only the two most recent long turns fit.
+func longConversation(turns int) []models.Message {
+	text := strings.Repeat("a", 4000)
+	messages := []models.Message{{Text: "be helpful", Role: "system"}}
+	for i := 0; i < turns; i++ {
+		messages = append(messages,
+			models.Message{Text: text, Role: "user"},
+			models.Message{Text: text, Role: "assistant"},
+		)
+	}
+	return append(messages, models.Message{Text: "latest question", Role: "user"})
+}
+
+func TestContextBuilder_BuildContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+
+	tests := []struct {
+		name           string
+		strategy       string
+		lastTurns      int
+		messages       []models.Message
+		setup          func()
+		wantLen        int
+		wantTrimmed    bool
+		wantDropped    int
+		wantSummarized bool
+		wantErr        bool
+	}{
+		{
+			name:     "fits without trimming",
+			strategy: service.ContextDropOldest,
+			messages: longConversation(1),
+			setup:    func() {},
+			wantLen:  4,
+		},
+		{
+			name:        "drop oldest turns",
+			strategy:    service.ContextDropOldest,
+			messages:    longConversation(5),
+			setup:       func() {},
+			wantLen:     6,
+			wantTrimmed: true,
+			wantDropped: 6,
+		},
+		{
+			name:        "keep last turns",
+			strategy:    service.ContextLastTurns,
+			lastTurns:   1,
+			messages:    longConversation(1),
+			setup:       func() {},
+			wantLen:     2,
+			wantTrimmed: true,
+			wantDropped: 2,
+		},
+		{
+			name:     "summarize older turns",
+			strategy: service.ContextSummarize,
+			messages: longConversation(5),
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+						callback("They talked about a lot of a's.")
+						return nil
+					})
+			},
+			wantLen:        7,
+			wantTrimmed:    true,
+			wantDropped:    6,
+			wantSummarized: true,
+		},
+		{
+			name:     "summary failure falls back to dropping",
+			strategy: service.ContextSummarize,
+			messages: longConversation(5),
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("provider down"))
+			},
+			wantLen:     6,
+			wantTrimmed: true,
+			wantDropped: 6,
+		},
+		{
+			name:     "latest message too long",
+			strategy: service.ContextDropOldest,
+			messages: []models.Message{{Text: strings.Repeat("a", 20000), Role: "user"}},
+			setup:    func() {},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			builder := service.NewContextBuilder(mockGenerator, tt.strategy, tt.lastTurns, "openai", "gpt-4o-mini")
+
+			got, report, err := builder.BuildContext(context.Background(), "openai", "gpt-4", tt.messages)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLen, len(got))
+			assert.Equal(t, "system", got[0].Role)
+			assert.Equal(t, tt.messages[len(tt.messages)-1], got[len(got)-1])
+			assert.Equal(t, tt.wantTrimmed, report.Trimmed)
+			assert.Equal(t, tt.wantDropped, report.DroppedMessages)
+			assert.Equal(t, tt.wantSummarized, report.Summarized)
+			assert.LessOrEqual(t, report.EstimatedTokens, report.Budget)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+// MaxEmbeddingInputs is the number of inputs accepted in one request. They
+// are split into as many provider requests as the model's limits require.
+const MaxEmbeddingInputs = 2048
+
+type EmbeddingService struct {
+	geminiRepo repository.EmbeddingRepositoryInterface
+	openaiRepo repository.EmbeddingRepositoryInterface
+}
+
+func NewEmbeddingService(geminiRepo repository.EmbeddingRepositoryInterface,
+	openaiRepo repository.EmbeddingRepositoryInterface) *EmbeddingService {
+	return &EmbeddingService{
+		geminiRepo: geminiRepo,
+		openaiRepo: openaiRepo,
+	}
+}
+
+// ValidateEmbeddingRequest checks a request against the model's limits. An
+// input longer than the model accepts is rejected rather than truncated.
+func ValidateEmbeddingRequest(platform string, req *models.EmbeddingRequest) error {
+	info, err := validateEmbeddingModel(platform, req.Model, req.Dimensions)
+	if err != nil {
+		return err
+	}
+	if len(req.Input) == 0 {
+		return fmt.Errorf("input is required")
+	}
+	if len(req.Input) > MaxEmbeddingInputs {
+		return fmt.Errorf("at most %d inputs can be embedded at once", MaxEmbeddingInputs)
+	}
+	for i, input := range req.Input {
+		if strings.TrimSpace(input) == "" {
+			return fmt.Errorf("input %d is empty", i)
+		}
+		if tokens := info.EstimateTokens(input); tokens > info.MaxInputTokens {
+			return fmt.Errorf("input %d is about %d tokens, more than the %d %s accepts; split it into smaller pieces",
+				i, tokens, info.MaxInputTokens, req.Model)
+		}
+	}
+	return nil
+}
+
+func validateEmbeddingModel(platform string, model string, dimensions int) (catalog.EmbeddingModelInfo, error) {
+	info, ok := catalog.LookupEmbedding(platform, model)
+	switch {
+	case !ok:
+		return info, fmt.Errorf("platform %s does not offer embeddings", platform)
+	case model == "":
+		return info, fmt.Errorf("model is required")
+	case dimensions < 0:
+		return info, fmt.Errorf("dimensions must be positive")
+	case dimensions > 0 && !info.Shortenable:
+		return info, fmt.Errorf("model %s does not support choosing dimensions", model)
+	case dimensions > info.Dimensions:
+		return info, fmt.Errorf("model %s returns at most %d dimensions", model, info.Dimensions)
+	}
+	return info, nil
+}
+
+// CreateEmbeddings embeds every input, sending them to the provider in
+// batches the model accepts. Indexes in the response refer to req.Input.
+func (s *EmbeddingService) CreateEmbeddings(ctx context.Context, platform string,
+	req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	if err := ValidateEmbeddingRequest(platform, req); err != nil {
+		return nil, err
+	}
+
+	var repo repository.EmbeddingRepositoryInterface
+	switch platform {
+	case "gemini":
+		repo = s.geminiRepo
+	case "openai":
+		repo = s.openaiRepo
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("%s repository not initialized", platform)
+	}
+
+	info, _ := catalog.LookupEmbedding(platform, req.Model)
+	result := &models.EmbeddingResponse{
+		Platform: platform,
+		Model:    req.Model,
+		Data:     make([]models.Embedding, 0, len(req.Input)),
+	}
+	for _, batch := range embeddingBatches(info, req.Input) {
+		inputs := req.Input[batch[0]:batch[1]]
+		response, err := repo.CreateEmbeddings(ctx, &models.EmbeddingRequest{
+			Model:      req.Model,
+			Input:      inputs,
+			Dimensions: req.Dimensions,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Data) != len(inputs) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(response.Data))
+		}
+
+		for _, embedding := range response.Data {
+			embedding.Index += batch[0]
+			result.Data = append(result.Data, embedding)
+		}
+		if response.Model != "" {
+			result.Model = response.Model
+		}
+		if response.Usage.PromptTokens == 0 {
+			for _, input := range inputs {
+				response.Usage.PromptTokens += info.EstimateTokens(input)
+			}
+			response.Usage.TotalTokens = response.Usage.PromptTokens
+			result.Usage.Estimated = true
+		}
+		result.Usage.PromptTokens += response.Usage.PromptTokens
+		result.Usage.TotalTokens += response.Usage.TotalTokens
+	}
+
+	sort.Slice(result.Data, func(i, j int) bool {
+		return result.Data[i].Index < result.Data[j].Index
+	})
+	return result, nil
+}
+
+// embeddingBatches splits the inputs into [start, end) ranges that stay
+// within the model's batch size and token limits.
+func embeddingBatches(info catalog.EmbeddingModelInfo, inputs []string) [][2]int {
+	var batches [][2]int
+	start, tokens := 0, 0
+	for i, input := range inputs {
+		estimate := info.EstimateTokens(input)
+		full := i-start == info.MaxBatch ||
+			(info.MaxBatchTokens > 0 && tokens+estimate > info.MaxBatchTokens)
+		if i > start && full {
+			batches = append(batches, [2]int{start, i})
+			start, tokens = i, 0
+		}
+		tokens += estimate
+	}
+	return append(batches, [2]int{start, len(inputs)})
+}
@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func fakeEmbeddings(req *models.EmbeddingRequest, tokens int) *models.EmbeddingResponse {
+	response := &models.EmbeddingResponse{Model: req.Model, Usage: models.EmbeddingUsage{PromptTokens: tokens, TotalTokens: tokens}}
+	for i, input := range req.Input {
+		response.Data = append(response.Data, models.Embedding{Index: i, Embedding: []float32{float32(len(input))}})
+	}
+	return response
+}
+
+func TestEmbeddingService_CreateEmbeddings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGemini := mocks.NewMockEmbeddingRepositoryInterface(ctrl)
+	mockOpenAI := mocks.NewMockEmbeddingRepositoryInterface(ctrl)
+	embeddingService := service.NewEmbeddingService(mockGemini, mockOpenAI)
+
+	t.Run("openai usage is reported", func(t *testing.T) {
+		mockOpenAI.EXPECT().CreateEmbeddings(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+				assert.Equal(t, 256, req.Dimensions)
+				return fakeEmbeddings(req, 5), nil
+			})
+
+		response, err := embeddingService.CreateEmbeddings(context.Background(), "openai", &models.EmbeddingRequest{
+			Model: "text-embedding-3-small", Input: []string{"a", "bb"}, Dimensions: 256,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "openai", response.Platform)
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, 1, response.Data[1].Index)
+		assert.Equal(t, 5, response.Usage.PromptTokens)
+		assert.False(t, response.Usage.Estimated)
+	})
+
+	t.Run("large batches are split", func(t *testing.T) {
+		inputs := make([]string, 250)
+		for i := range inputs {
+			inputs[i] = strings.Repeat("x", i+1)
+		}
+		var sizes []int
+		mockGemini.EXPECT().CreateEmbeddings(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+				sizes = append(sizes, len(req.Input))
+				return fakeEmbeddings(req, 0), nil
+			}).Times(3)
+
+		response, err := embeddingService.CreateEmbeddings(context.Background(), "gemini", &models.EmbeddingRequest{
+			Model: "text-embedding-004", Input: inputs,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{100, 100, 50}, sizes)
+		require.Len(t, response.Data, 250)
+		for i, embedding := range response.Data {
+			assert.Equal(t, i, embedding.Index)
+			assert.Equal(t, float32(i+1), embedding.Embedding[0], "vector %d belongs to input %d", i, i)
+		}
+		assert.True(t, response.Usage.Estimated)
+		assert.Positive(t, response.Usage.PromptTokens)
+	})
+
+	t.Run("provider errors are returned", func(t *testing.T) {
+		mockOpenAI.EXPECT().CreateEmbeddings(gomock.Any(), gomock.Any()).Return(nil, errors.New("rate limited"))
+
+		_, err := embeddingService.CreateEmbeddings(context.Background(), "openai", &models.EmbeddingRequest{
+			Model: "text-embedding-3-small", Input: []string{"a"},
+		})
+		assert.EqualError(t, err, "rate limited")
+	})
+}
+
+func TestValidateEmbeddingRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		req      *models.EmbeddingRequest
+		wantErr  string
+	}{
+		{name: "valid", platform: "openai", req: &models.EmbeddingRequest{Model: "text-embedding-3-large", Input: []string{"a"}, Dimensions: 1024}},
+		{name: "no embeddings", platform: "deepseek", req: &models.EmbeddingRequest{Model: "deepseek-chat", Input: []string{"a"}}, wantErr: "does not offer embeddings"},
+		{name: "no model", platform: "openai", req: &models.EmbeddingRequest{Input: []string{"a"}}, wantErr: "model is required"},
+		{name: "no input", platform: "openai", req: &models.EmbeddingRequest{Model: "text-embedding-3-small"}, wantErr: "input is required"},
+		{name: "empty input", platform: "openai", req: &models.EmbeddingRequest{Model: "text-embedding-3-small", Input: []string{"a", " "}}, wantErr: "input 1 is empty"},
+		{
+			name:     "input too large",
+			platform: "gemini",
+			req:      &models.EmbeddingRequest{Model: "text-embedding-004", Input: []string{strings.Repeat("word ", 2000)}},
+			wantErr:  "input 0 is about 2500 tokens",
+		},
+		{name: "fixed dimensions", platform: "openai", req: &models.EmbeddingRequest{Model: "text-embedding-ada-002", Input: []string{"a"}, Dimensions: 256}, wantErr: "does not support choosing dimensions"},
+		{name: "too many dimensions", platform: "gemini", req: &models.EmbeddingRequest{Model: "text-embedding-004", Input: []string{"a"}, Dimensions: 1024}, wantErr: "at most 768 dimensions"},
+		{name: "too many inputs", platform: "openai", req: &models.EmbeddingRequest{Model: "text-embedding-3-small", Input: make([]string, service.MaxEmbeddingInputs+1)}, wantErr: "at most 2048 inputs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateEmbeddingRequest(tt.platform, tt.req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
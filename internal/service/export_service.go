@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+type ExportFormat struct {
+	ContentType string
+	Extension   string
+}
+
+var ExportFormats = map[string]ExportFormat{
+	"markdown": {ContentType: "text/markdown; charset=utf-8", Extension: "md"},
+	"json":     {ContentType: "application/json", Extension: "json"},
+	"jsonl":    {ContentType: "application/jsonl", Extension: "jsonl"},
+}
+
+type ExportService struct {
+	chatRepo repository.ChatRepositoryInterface
+}
+
+func NewExportService(chatRepo repository.ChatRepositoryInterface) *ExportService {
+	return &ExportService{
+		chatRepo: chatRepo,
+	}
+}
+
+func (s *ExportService) ExportChat(ctx context.Context, w io.Writer, chat *models.Chat, format string) error {
+	switch format {
+	case "markdown":
+		return writeMarkdown(w, chat)
+	case "json":
+		return json.NewEncoder(w).Encode(chat)
+	case "jsonl":
+		return writeFineTuningLine(w, chat)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+func (s *ExportService) ExportUserChats(ctx context.Context, w io.Writer, userID string, format string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if _, ok := ExportFormats[format]; !ok {
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	count := 0
+	if format == "json" {
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+	}
+
+	err := s.chatRepo.ForEachUserChat(ctx, userID, func(chat *models.Chat) error {
+		var err error
+		switch format {
+		case "markdown":
+			if count > 0 {
+				if _, err = io.WriteString(w, "\n---\n\n"); err != nil {
+					return err
+				}
+			}
+			err = writeMarkdown(w, chat)
+		case "json":
+			if count > 0 {
+				if _, err = io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			err = json.NewEncoder(w).Encode(chat)
+		case "jsonl":
+			err = writeFineTuningLine(w, chat)
+		}
+		count++
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		_, err = io.WriteString(w, "]\n")
+	}
+	return err
+}
+
+func writeMarkdown(w io.Writer, chat *models.Chat) error {
+	title := chat.Title
+	if title == "" {
+		title = "Untitled chat"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	fmt.Fprintf(&sb, "_Created %s_\n\n", chat.CreatedAt.UTC().Format(time.RFC3339))
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+
+	for _, message := range chat.Messages {
+		sb.Reset()
+		heading := roleHeading(message.Role)
+		if message.AI != "" {
+			heading = fmt.Sprintf("%s (%s)", heading, message.AI)
+		}
+		fmt.Fprintf(&sb, "### %s\n\n%s\n\n", heading, strings.TrimSpace(message.Text))
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func roleHeading(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "system":
+		return "System"
+	case "tool":
+		return "Tool"
+	default:
+		return role
+	}
+}
+
+type fineTuningMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// writeFineTuningLine writes the chat in OpenAI's chat fine-tuning format.
+// Chats without an assistant reply are skipped since they cannot be used as
+// training examples.
+func writeFineTuningLine(w io.Writer, chat *models.Chat) error {
+	messages := make([]fineTuningMessage, 0, len(chat.Messages))
+	hasAnswer := false
+	for _, message := range chat.Messages {
+		switch message.Role {
+		case "user", "system":
+		case "assistant":
+			hasAnswer = true
+		default:
+			continue
+		}
+		messages = append(messages, fineTuningMessage{Role: message.Role, Content: message.Text})
+	}
+	if !hasAnswer {
+		return nil
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		Messages []fineTuningMessage `json:"messages"`
+	}{Messages: messages})
+}
@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func exportTestChats() []*models.Chat {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []*models.Chat{
+		{
+			ID:        "chat-1",
+			User:      "user-123",
+			Title:     "Channels",
+			CreatedAt: created,
+			Messages: []models.Message{
+				{ID: "msg-1", Text: "How do channels work?", Role: "user"},
+				{ID: "msg-2", Text: "They pass values between goroutines.", Role: "assistant", AI: "gpt-4o"},
+			},
+		},
+		{
+			ID:        "chat-2",
+			User:      "user-123",
+			CreatedAt: created,
+			Messages: []models.Message{
+				{ID: "msg-3", Text: "Unanswered", Role: "user"},
+			},
+		},
+	}
+}
+
+func TestExportService_ExportChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exportService := service.NewExportService(mocks.NewMockChatRepositoryInterface(ctrl))
+	chat := exportTestChats()[0]
+
+	tests := []struct {
+		name    string
+		format  string
+		check   func(t *testing.T, out string)
+		wantErr bool
+	}{
+		{
+			name:   "markdown",
+			format: "markdown",
+			check: func(t *testing.T, out string) {
+				assert.Contains(t, out, "# Channels\n")
+				assert.Contains(t, out, "### User\n\nHow do channels work?")
+				assert.Contains(t, out, "### Assistant (gpt-4o)\n\nThey pass values between goroutines.")
+			},
+		},
+		{
+			name:   "json",
+			format: "json",
+			check: func(t *testing.T, out string) {
+				var decoded models.Chat
+				require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+				assert.Equal(t, chat.ID, decoded.ID)
+				assert.Len(t, decoded.Messages, 2)
+			},
+		},
+		{
+			name:   "jsonl",
+			format: "jsonl",
+			check: func(t *testing.T, out string) {
+				assert.JSONEq(t, `{"messages":[
+					{"role":"user","content":"How do channels work?"},
+					{"role":"assistant","content":"They pass values between goroutines."}
+				]}`, out)
+			},
+		},
+		{
+			name:    "unsupported",
+			format:  "pdf",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := exportService.ExportChat(context.Background(), &buf, chat, tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+func TestExportService_ExportUserChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	exportService := service.NewExportService(mockRepo)
+
+	streamChats := func(ctx context.Context, userID string, fn func(*models.Chat) error) error {
+		for _, chat := range exportTestChats() {
+			if err := fn(chat); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("json array", func(t *testing.T) {
+		mockRepo.EXPECT().ForEachUserChat(gomock.Any(), "user-123", gomock.Any()).DoAndReturn(streamChats)
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportUserChats(context.Background(), &buf, "user-123", "json"))
+
+		var decoded []models.Chat
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Len(t, decoded, 2)
+	})
+
+	t.Run("jsonl skips chats without answers", func(t *testing.T) {
+		mockRepo.EXPECT().ForEachUserChat(gomock.Any(), "user-123", gomock.Any()).DoAndReturn(streamChats)
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportUserChats(context.Background(), &buf, "user-123", "jsonl"))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 1)
+	})
+
+	t.Run("markdown separates chats", func(t *testing.T) {
+		mockRepo.EXPECT().ForEachUserChat(gomock.Any(), "user-123", gomock.Any()).DoAndReturn(streamChats)
+
+		var buf bytes.Buffer
+		require.NoError(t, exportService.ExportUserChats(context.Background(), &buf, "user-123", "markdown"))
+
+		assert.Equal(t, 1, strings.Count(buf.String(), "\n---\n"))
+		assert.Contains(t, buf.String(), "# Untitled chat")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Error(t, exportService.ExportUserChats(context.Background(), &buf, "user-123", "pdf"))
+	})
+}
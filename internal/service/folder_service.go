@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+type FolderService struct {
+	folderRepo repository.FolderRepositoryInterface
+	chatRepo   repository.ChatRepositoryInterface
+}
+
+func NewFolderService(folderRepo repository.FolderRepositoryInterface,
+	chatRepo repository.ChatRepositoryInterface) *FolderService {
+	return &FolderService{
+		folderRepo: folderRepo,
+		chatRepo:   chatRepo,
+	}
+}
+
+func (s *FolderService) CreateFolder(ctx context.Context, folder *models.Folder) error {
+	folder.Name = strings.TrimSpace(folder.Name)
+	if folder.Name == "" {
+		return fmt.Errorf("folder name is required")
+	}
+	if folder.User == "" {
+		return fmt.Errorf("user ID is required")
+	}
+
+	folder.ID = idgen.GenerateWithPrefix("folder")
+	folder.CreatedAt = time.Now()
+	folder.UpdatedAt = time.Now()
+
+	return s.folderRepo.CreateFolder(ctx, folder)
+}
+
+func (s *FolderService) GetFolder(ctx context.Context, id string) (*models.Folder, error) {
+	if id == "" {
+		return nil, fmt.Errorf("folder ID is required")
+	}
+
+	return s.folderRepo.GetFolder(ctx, id)
+}
+
+func (s *FolderService) ListFolders(ctx context.Context, userID string) ([]*models.Folder, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	return s.folderRepo.ListFolders(ctx, userID)
+}
+
+func (s *FolderService) RenameFolder(ctx context.Context, id string, name string) (*models.Folder, error) {
+	name = strings.TrimSpace(name)
+	if id == "" {
+		return nil, fmt.Errorf("folder ID is required")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("folder name is required")
+	}
+
+	folder, err := s.folderRepo.GetFolder(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	folder.Name = name
+	folder.UpdatedAt = time.Now()
+	if err := s.folderRepo.UpdateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (s *FolderService) DeleteFolder(ctx context.Context, userID string, id string) error {
+	if id == "" {
+		return fmt.Errorf("folder ID is required")
+	}
+
+	if err := s.folderRepo.DeleteFolder(ctx, id); err != nil {
+		return err
+	}
+	return s.chatRepo.ClearFolder(ctx, userID, id)
+}
+
+// MoveChats moves the user's chats into a folder, or out of any folder when
+// folderID is empty. A non-zero version restricts the move to chats still at
+// that version.
+func (s *FolderService) MoveChats(ctx context.Context, userID string, folderID string, chatIDs []string,
+	version int64) (int64, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("user ID is required")
+	}
+	if len(chatIDs) == 0 {
+		return 0, fmt.Errorf("at least one chat ID is required")
+	}
+
+	if folderID != "" {
+		folder, err := s.folderRepo.GetFolder(ctx, folderID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get folder: %w", err)
+		}
+		if folder.User != userID {
+			return 0, fmt.Errorf("folder not found")
+		}
+	}
+
+	return chatCount(s.chatRepo.UpdateChats(ctx, userID, chatIDs, models.ChatUpdate{FolderID: &folderID, Version: version}))
+}
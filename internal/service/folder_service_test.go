@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFolderService_CreateFolder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFolderRepo := mocks.NewMockFolderRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	folderService := service.NewFolderService(mockFolderRepo, mockChatRepo)
+
+	tests := []struct {
+		name    string
+		folder  *models.Folder
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name:   "successful creation",
+			folder: &models.Folder{Name: "  Work  ", User: "user-123"},
+			setup: func() {
+				mockFolderRepo.EXPECT().
+					CreateFolder(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "empty name",
+			folder:  &models.Folder{Name: "   ", User: "user-123"},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "missing user",
+			folder:  &models.Folder{Name: "Work"},
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := folderService.CreateFolder(context.Background(), tt.folder)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, tt.folder.ID, "folder_")
+			assert.Equal(t, "Work", tt.folder.Name)
+		})
+	}
+}
+
+func TestFolderService_DeleteFolder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFolderRepo := mocks.NewMockFolderRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	folderService := service.NewFolderService(mockFolderRepo, mockChatRepo)
+
+	gomock.InOrder(
+		mockFolderRepo.EXPECT().DeleteFolder(gomock.Any(), "folder-1").Return(nil),
+		mockChatRepo.EXPECT().ClearFolder(gomock.Any(), "user-123", "folder-1").Return(nil),
+	)
+
+	require.NoError(t, folderService.DeleteFolder(context.Background(), "user-123", "folder-1"))
+}
+
+func TestFolderService_MoveChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFolderRepo := mocks.NewMockFolderRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	folderService := service.NewFolderService(mockFolderRepo, mockChatRepo)
+
+	tests := []struct {
+		name     string
+		folderID string
+		chatIDs  []string
+		setup    func()
+		want     int64
+		wantErr  bool
+	}{
+		{
+			name:     "move into folder",
+			folderID: "folder-1",
+			chatIDs:  []string{"chat-1", "chat-2"},
+			setup: func() {
+				mockFolderRepo.EXPECT().
+					GetFolder(gomock.Any(), "folder-1").
+					Return(&models.Folder{ID: "folder-1", User: "user-123"}, nil)
+				mockChatRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1", "chat-2"}, gomock.Any()).
+					Return([]*models.Chat{{ID: "chat-1"}, {ID: "chat-2"}}, nil)
+			},
+			want: 2,
+		},
+		{
+			name:     "move to root",
+			folderID: "",
+			chatIDs:  []string{"chat-1"},
+			setup: func() {
+				mockChatRepo.EXPECT().
+					UpdateChats(gomock.Any(), "user-123", []string{"chat-1"}, gomock.Any()).
+					Return([]*models.Chat{{ID: "chat-1"}}, nil)
+			},
+			want: 1,
+		},
+		{
+			name:     "folder owned by someone else",
+			folderID: "folder-2",
+			chatIDs:  []string{"chat-1"},
+			setup: func() {
+				mockFolderRepo.EXPECT().
+					GetFolder(gomock.Any(), "folder-2").
+					Return(&models.Folder{ID: "folder-2", User: "user-456"}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "missing folder",
+			folderID: "nonexistent",
+			chatIDs:  []string{"chat-1"},
+			setup: func() {
+				mockFolderRepo.EXPECT().
+					GetFolder(gomock.Any(), "nonexistent").
+					Return(nil, fmt.Errorf("folder not found"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "no chats",
+			folderID: "folder-1",
+			setup:    func() {},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := folderService.MoveChats(context.Background(), "user-123", tt.folderID, tt.chatIDs, 0)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
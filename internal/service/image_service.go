@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+const maxImagePrompt = 4000
+
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ValidateImageRequest checks a request against what the image model
+// supports. An empty size or count means the model's default.
+func ValidateImageRequest(platform string, req *models.ImageRequest) error {
+	info, ok := catalog.LookupImage(platform, req.Model)
+	if !ok {
+		return fmt.Errorf("platform %s does not generate images", platform)
+	}
+	if req.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if strings.TrimSpace(req.Prompt) == "" {
+		return fmt.Errorf("prompt is required")
+	}
+	if utf8.RuneCountInString(req.Prompt) > maxImagePrompt {
+		return fmt.Errorf("prompt must be at most %d characters", maxImagePrompt)
+	}
+	if req.Size != "" && !slices.Contains(info.Sizes, req.Size) {
+		return fmt.Errorf("%s does not support size %s; use one of %s", req.Model, req.Size,
+			strings.Join(info.Sizes, ", "))
+	}
+	if req.Count < 0 || req.Count > info.MaxCount {
+		return fmt.Errorf("count must be between 1 and %d for %s", info.MaxCount, req.Model)
+	}
+	if req.Style != "" && !info.PromptStyle && !slices.Contains(info.Styles, req.Style) {
+		if len(info.Styles) == 0 {
+			return fmt.Errorf("%s does not support styles", req.Model)
+		}
+		return fmt.Errorf("%s does not support style %s; use one of %s", req.Model, req.Style,
+			strings.Join(info.Styles, ", "))
+	}
+	return nil
+}
+
+type ImageService struct {
+	geminiRepo        repository.ImageRepositoryInterface
+	openaiRepo        repository.ImageRepositoryInterface
+	chatService       ChatServiceInterface
+	attachmentService AttachmentServiceInterface
+}
+
+func NewImageService(geminiRepo repository.ImageRepositoryInterface, openaiRepo repository.ImageRepositoryInterface,
+	chatService ChatServiceInterface, attachmentService AttachmentServiceInterface) *ImageService {
+	return &ImageService{
+		geminiRepo:        geminiRepo,
+		openaiRepo:        openaiRepo,
+		chatService:       chatService,
+		attachmentService: attachmentService,
+	}
+}
+
+// GenerateImages fills in the model's default size and count and asks the
+// provider for the images. Styles of models without a style parameter are
+// added to the prompt.
+func (s *ImageService) GenerateImages(ctx context.Context, platform string,
+	req *models.ImageRequest) ([]models.GeneratedImage, error) {
+	if err := ValidateImageRequest(platform, req); err != nil {
+		return nil, err
+	}
+	info, _ := catalog.LookupImage(platform, req.Model)
+
+	request := *req
+	if request.Size == "" {
+		request.Size = info.Sizes[0]
+	}
+	if request.Count == 0 {
+		request.Count = 1
+	}
+	if request.Style != "" && info.PromptStyle {
+		request.Prompt += "\n\nStyle: " + request.Style
+		request.Style = ""
+	}
+
+	var repo repository.ImageRepositoryInterface
+	switch platform {
+	case "gemini":
+		repo = s.geminiRepo
+	case "openai":
+		repo = s.openaiRepo
+	}
+	return repo.GenerateImages(ctx, &request)
+}
+
+// SaveImages stores generated images as attachments of an assistant reply to
+// the prompt. Without a chat, a new one is created for the user. It returns
+// the updated chat and where each image can be downloaded.
+func (s *ImageService) SaveImages(ctx context.Context, chat *models.Chat, userID string, platform string,
+	req *models.ImageRequest, images []models.GeneratedImage) (*models.Chat, *models.ImageResponse, error) {
+	attachments := make([]models.Attachment, 0, len(images))
+	for i, image := range images {
+		name := fmt.Sprintf("image-%d%s", i+1, imageExtensions[image.MimeType])
+		attachment, err := s.attachmentService.NewAttachment(userID, name, image.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if attachment.Kind != models.AttachmentKindImage {
+			return nil, nil, fmt.Errorf("provider returned %s instead of an image", image.MimeType)
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	if chat == nil {
+		chat = &models.Chat{User: userID, Title: truncateTitle(req.Prompt)}
+		if err := s.chatService.CreateChat(ctx, chat); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.attachmentService.SaveAttachments(ctx, chat.ID, attachments); err != nil {
+		return nil, nil, err
+	}
+
+	reply := models.Message{
+		Text:        imageReplyText(req.Prompt, images),
+		Role:        "assistant",
+		AI:          req.Model,
+		Generation:  &models.GenerationInfo{Platform: platform, Model: req.Model},
+		Attachments: attachments,
+	}
+	updated, err := s.chatService.AddMessages(ctx, chat.ID,
+		models.Message{Text: req.Prompt, Role: "user", UserID: userID}, reply)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &models.ImageResponse{
+		ChatID:    updated.ID,
+		MessageID: updated.Messages[len(updated.Messages)-1].ID,
+		Platform:  platform,
+		Model:     req.Model,
+		Images:    make([]models.ImageResult, 0, len(attachments)),
+	}
+	for i, attachment := range attachments {
+		response.Images = append(response.Images, models.ImageResult{
+			ID:            attachment.ID,
+			URL:           fmt.Sprintf("/api/v1/chats/%s/attachments/%s", updated.ID, attachment.ID),
+			MimeType:      attachment.MimeType,
+			Size:          attachment.Size,
+			RevisedPrompt: images[i].RevisedPrompt,
+		})
+	}
+	return updated, response, nil
+}
+
+// imageReplyText describes the images in the reply's text, which is all a
+// chat model sees of them later in the chat.
+func imageReplyText(prompt string, images []models.GeneratedImage) string {
+	lines := make([]string, 0, len(images))
+	for i, image := range images {
+		description := image.RevisedPrompt
+		if description == "" {
+			description = prompt
+		}
+		lines = append(lines, fmt.Sprintf("Generated image %d: %s", i+1, description))
+	}
+	return strings.Join(lines, "\n")
+}
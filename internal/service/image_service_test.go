@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidateImageRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		req      models.ImageRequest
+		wantErr  string
+	}{
+		{
+			name:     "defaults",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "dall-e-3", Prompt: "a fox"},
+		},
+		{
+			name:     "openai style",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "dall-e-3", Prompt: "a fox", Size: "1792x1024", Style: "natural"},
+		},
+		{
+			name:     "imagen takes any style",
+			platform: "gemini",
+			req:      models.ImageRequest{Model: "imagen-3.0-generate-002", Prompt: "a fox", Size: "16:9", Count: 4, Style: "watercolor"},
+		},
+		{
+			name:     "unsupported platform",
+			platform: "deepseek",
+			req:      models.ImageRequest{Model: "deepseek-chat", Prompt: "a fox"},
+			wantErr:  "platform deepseek does not generate images",
+		},
+		{
+			name:     "missing prompt",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "dall-e-3", Prompt: " "},
+			wantErr:  "prompt is required",
+		},
+		{
+			name:     "unsupported size",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "dall-e-2", Prompt: "a fox", Size: "1792x1024"},
+			wantErr:  "dall-e-2 does not support size 1792x1024; use one of 1024x1024, 512x512, 256x256",
+		},
+		{
+			name:     "too many images",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "dall-e-3", Prompt: "a fox", Count: 2},
+			wantErr:  "count must be between 1 and 1 for dall-e-3",
+		},
+		{
+			name:     "unknown style",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "dall-e-3", Prompt: "a fox", Style: "watercolor"},
+			wantErr:  "dall-e-3 does not support style watercolor; use one of vivid, natural",
+		},
+		{
+			name:     "model without styles",
+			platform: "openai",
+			req:      models.ImageRequest{Model: "gpt-image-1", Prompt: "a fox", Style: "vivid"},
+			wantErr:  "gpt-image-1 does not support styles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateImageRequest(tt.platform, &tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestImageService_GenerateImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGemini := mocks.NewMockImageRepositoryInterface(ctrl)
+	mockOpenAI := mocks.NewMockImageRepositoryInterface(ctrl)
+	imageService := service.NewImageService(mockGemini, mockOpenAI, nil, nil)
+
+	t.Run("defaults are filled in", func(t *testing.T) {
+		mockOpenAI.EXPECT().GenerateImages(gomock.Any(), &models.ImageRequest{
+			Model: "dall-e-3", Prompt: "a fox", Size: "1024x1024", Count: 1, Style: "vivid",
+		}).Return([]models.GeneratedImage{{MimeType: "image/png"}}, nil)
+
+		images, err := imageService.GenerateImages(context.Background(), "openai",
+			&models.ImageRequest{Model: "dall-e-3", Prompt: "a fox", Style: "vivid"})
+		require.NoError(t, err)
+		assert.Len(t, images, 1)
+	})
+
+	t.Run("imagen styles go in the prompt", func(t *testing.T) {
+		mockGemini.EXPECT().GenerateImages(gomock.Any(), &models.ImageRequest{
+			Model: "imagen-3.0-generate-002", Prompt: "a fox\n\nStyle: watercolor", Size: "1:1", Count: 2,
+		}).Return([]models.GeneratedImage{{}, {}}, nil)
+
+		_, err := imageService.GenerateImages(context.Background(), "gemini",
+			&models.ImageRequest{Model: "imagen-3.0-generate-002", Prompt: "a fox", Count: 2, Style: "watercolor"})
+		require.NoError(t, err)
+	})
+}
+
+func TestImageService_SaveImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatService := mocks.NewMockChatServiceInterface(ctrl)
+	mockAttachments := mocks.NewMockAttachmentServiceInterface(ctrl)
+	imageService := service.NewImageService(nil, nil, mockChatService, mockAttachments)
+
+	req := &models.ImageRequest{Model: "dall-e-3", Prompt: "a fox in the snow"}
+	images := []models.GeneratedImage{{Data: []byte("png"), MimeType: "image/png", RevisedPrompt: "A red fox in deep snow"}}
+
+	t.Run("a new chat is created", func(t *testing.T) {
+		mockAttachments.EXPECT().NewAttachment("user1", "image-1.png", []byte("png")).
+			Return(&models.Attachment{ID: "att1", Kind: models.AttachmentKindImage, MimeType: "image/png", Size: 3}, nil)
+		mockChatService.EXPECT().CreateChat(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, chat *models.Chat) error {
+				assert.Equal(t, "user1", chat.User)
+				assert.Equal(t, "a fox in the snow", chat.Title)
+				chat.ID = "chat1"
+				return nil
+			})
+		mockAttachments.EXPECT().SaveAttachments(gomock.Any(), "chat1", gomock.Len(1)).Return(nil)
+		mockChatService.EXPECT().AddMessages(gomock.Any(), "chat1", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id string, messages ...models.Message) (*models.Chat, error) {
+				require.Len(t, messages, 2)
+				assert.Equal(t, "user", messages[0].Role)
+				reply := messages[1]
+				assert.Equal(t, "assistant", reply.Role)
+				assert.Equal(t, "Generated image 1: A red fox in deep snow", reply.Text)
+				assert.Equal(t, "openai", reply.Generation.Platform)
+				require.Len(t, reply.Attachments, 1)
+				reply.ID = "msg2"
+				return &models.Chat{ID: "chat1", Messages: []models.Message{messages[0], reply}}, nil
+			})
+
+		chat, response, err := imageService.SaveImages(context.Background(), nil, "user1", "openai", req, images)
+		require.NoError(t, err)
+		assert.Equal(t, "chat1", chat.ID)
+		assert.Equal(t, "msg2", response.MessageID)
+		require.Len(t, response.Images, 1)
+		assert.Equal(t, "/api/v1/chats/chat1/attachments/att1", response.Images[0].URL)
+		assert.Equal(t, "A red fox in deep snow", response.Images[0].RevisedPrompt)
+	})
+
+	t.Run("non-image data is rejected", func(t *testing.T) {
+		mockAttachments.EXPECT().NewAttachment("user1", "image-1", gomock.Any()).
+			Return(&models.Attachment{Kind: models.AttachmentKindDocument}, nil)
+
+		_, _, err := imageService.SaveImages(context.Background(), &models.Chat{ID: "chat1"}, "user1", "openai", req,
+			[]models.GeneratedImage{{Data: []byte("text"), MimeType: "text/plain"}})
+		assert.EqualError(t, err, "provider returned text/plain instead of an image")
+	})
+}
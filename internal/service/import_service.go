@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+type ImportService struct {
+	chatRepo repository.ChatRepositoryInterface
+}
+
+func NewImportService(chatRepo repository.ChatRepositoryInterface) *ImportService {
+	return &ImportService{
+		chatRepo: chatRepo,
+	}
+}
+
+func (s *ImportService) ImportChats(ctx context.Context, userID string, format string, r io.Reader) ([]*models.Chat, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	var decode func(json.RawMessage) (*models.Chat, error)
+	switch format {
+	case "json":
+		decode = decodeNativeChat
+	case "chatgpt":
+		decode = decodeChatGPTConversation
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+
+	imported := []*models.Chat{}
+	err := forEachJSONValue(r, func(raw json.RawMessage) error {
+		chat, err := decode(raw)
+		if err != nil {
+			return err
+		}
+		if chat == nil {
+			return nil
+		}
+
+		prepareImportedChat(chat, userID)
+		if err := s.chatRepo.CreateChat(ctx, chat); err != nil {
+			return err
+		}
+		imported = append(imported, chat)
+		return nil
+	})
+	if err != nil {
+		s.rollback(ctx, userID, imported)
+		return nil, err
+	}
+	return imported, nil
+}
+
+// rollback removes the chats created by a failed import, so an import either
+// succeeds as a whole or leaves nothing behind.
+func (s *ImportService) rollback(ctx context.Context, userID string, chats []*models.Chat) {
+	ctx = context.WithoutCancel(ctx)
+	for _, chat := range chats {
+		_, err := s.chatRepo.DeleteChat(ctx, chat.ID, 0)
+		if err == nil {
+			err = s.chatRepo.PurgeChat(ctx, userID, chat.ID)
+		}
+		if err != nil {
+			log.Printf("Error rolling back imported chat %s: %v", chat.ID, err)
+		}
+	}
+}
+
+// forEachJSONValue calls fn for every element when the input is a JSON array,
+// or once with the whole value otherwise, without loading the full array into
+// memory.
+func forEachJSONValue(r io.Reader, fn func(json.RawMessage) error) error {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return fmt.Errorf("invalid import file: %w", err)
+	}
+
+	dec := json.NewDecoder(br)
+	if first != '[' {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid import file: %w", err)
+		}
+		return fn(raw)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("invalid import file: %w", err)
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid import file: %w", err)
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("invalid import file: %w", err)
+	}
+	return nil
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+func prepareImportedChat(chat *models.Chat, userID string) {
+	now := time.Now()
+	chat.ID = generateID()
+	chat.User = userID
+	chat.FolderID = ""
+	chat.Summary = nil
+	chat.AssistantID = ""
+	chat.KnowledgeBaseIDs = nil
+	chat.ForkedFrom = nil
+	chat.Members = nil
+	chat.Version = 1
+	chat.DeletedAt = nil
+	if chat.CreatedAt.IsZero() {
+		chat.CreatedAt = now
+	}
+	if chat.UpdatedAt.IsZero() {
+		chat.UpdatedAt = chat.CreatedAt
+	}
+	messages := make([]models.Message, 0, len(chat.Messages))
+	for _, message := range chat.Messages {
+		// Tool results are dropped with the calls they answer.
+		if message.Role == "tool" {
+			continue
+		}
+		message.ID = generateID()
+		// Attachment IDs in an export point at files the importer may not own,
+		// and the rest describes who sent the message and how it was made,
+		// which the export cannot vouch for.
+		message.Attachments = nil
+		message.UserID = ""
+		message.Generation = nil
+		message.ToolCalls = nil
+		message.ToolCallID = ""
+		message.ToolName = ""
+		message.Citations = nil
+		message.Usage = nil
+		if message.Role == "assistant" && message.Text == "" {
+			continue
+		}
+		if message.SentAt.IsZero() {
+			message.SentAt = chat.CreatedAt
+		}
+		messages = append(messages, message)
+	}
+	chat.Messages = messages
+}
+
+func decodeNativeChat(raw json.RawMessage) (*models.Chat, error) {
+	var chat models.Chat
+	if err := json.Unmarshal(raw, &chat); err != nil {
+		return nil, fmt.Errorf("invalid chat: %w", err)
+	}
+	for _, message := range chat.Messages {
+		if message.Role == "" {
+			return nil, fmt.Errorf("invalid chat %q: message without role", chat.Title)
+		}
+	}
+	return &chat, nil
+}
+
+type chatGPTConversation struct {
+	Title       string                 `json:"title"`
+	CreateTime  float64                `json:"create_time"`
+	UpdateTime  float64                `json:"update_time"`
+	CurrentNode string                 `json:"current_node"`
+	Mapping     map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	ID      string          `json:"id"`
+	Parent  string          `json:"parent"`
+	Message *chatGPTMessage `json:"message"`
+}
+
+type chatGPTMessage struct {
+	Author struct {
+		Role string `json:"role"`
+	} `json:"author"`
+	CreateTime float64 `json:"create_time"`
+	Content    struct {
+		ContentType string `json:"content_type"`
+		Parts       []any  `json:"parts"`
+	} `json:"content"`
+	Metadata struct {
+		ModelSlug string `json:"model_slug"`
+	} `json:"metadata"`
+}
+
+// decodeChatGPTConversation converts one entry of ChatGPT's conversations.json.
+// Conversations are stored as a tree of edits and regenerations; only the
+// branch ending at current_node, which is what the user last saw, is kept.
+func decodeChatGPTConversation(raw json.RawMessage) (*models.Chat, error) {
+	var conversation chatGPTConversation
+	if err := json.Unmarshal(raw, &conversation); err != nil {
+		return nil, fmt.Errorf("invalid ChatGPT conversation: %w", err)
+	}
+
+	var branch []*chatGPTMessage
+	visited := make(map[string]bool)
+	for id := conversation.CurrentNode; id != "" && !visited[id]; {
+		visited[id] = true
+		node, ok := conversation.Mapping[id]
+		if !ok {
+			break
+		}
+		if node.Message != nil {
+			branch = append(branch, node.Message)
+		}
+		id = node.Parent
+	}
+
+	chat := &models.Chat{
+		Title:     conversation.Title,
+		CreatedAt: unixSeconds(conversation.CreateTime),
+		UpdatedAt: unixSeconds(conversation.UpdateTime),
+		Messages:  []models.Message{},
+	}
+	for i := len(branch) - 1; i >= 0; i-- {
+		message := branch[i]
+		role := message.Author.Role
+		if role != "user" && role != "assistant" {
+			continue
+		}
+		if message.Content.ContentType != "text" {
+			continue
+		}
+
+		var parts []string
+		for _, part := range message.Content.Parts {
+			if text, ok := part.(string); ok && text != "" {
+				parts = append(parts, text)
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+
+		imported := models.Message{
+			Text:   strings.Join(parts, "\n"),
+			Role:   role,
+			SentAt: unixSeconds(message.CreateTime),
+		}
+		if role == "assistant" {
+			imported.AI = message.Metadata.ModelSlug
+		}
+		chat.Messages = append(chat.Messages, imported)
+	}
+
+	if len(chat.Messages) == 0 {
+		return nil, nil
+	}
+	return chat, nil
+}
+
+func unixSeconds(seconds float64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9))
+}
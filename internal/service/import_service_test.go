@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const chatGPTExport = `[
+  {
+    "title": "Go generics",
+    "create_time": 1700000000.5,
+    "update_time": 1700000100.0,
+    "current_node": "n4",
+    "mapping": {
+      "root": {"id": "root", "parent": "", "message": null},
+      "n1": {"id": "n1", "parent": "root", "message": {"author": {"role": "system"}, "content": {"content_type": "text", "parts": [""]}}},
+      "n2": {"id": "n2", "parent": "n1", "message": {"author": {"role": "user"}, "create_time": 1700000001, "content": {"content_type": "text", "parts": ["What are generics?"]}}},
+      "n3": {"id": "n3", "parent": "n2", "message": {"author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["An old answer"]}, "metadata": {"model_slug": "gpt-4"}}},
+      "n4": {"id": "n4", "parent": "n2", "message": {"author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["Type parameters."]}, "metadata": {"model_slug": "gpt-4o"}}}
+    }
+  },
+  {
+    "title": "Empty",
+    "create_time": 1700000000,
+    "current_node": "root",
+    "mapping": {"root": {"id": "root", "parent": "", "message": null}}
+  }
+]`
+
+func TestImportService_ImportChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	importService := service.NewImportService(mockRepo)
+
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		setup   func()
+		check   func(t *testing.T, chats []*models.Chat)
+		wantErr bool
+	}{
+		{
+			name:   "chatgpt follows the current branch",
+			format: "chatgpt",
+			input:  chatGPTExport,
+			setup: func() {
+				mockRepo.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			check: func(t *testing.T, chats []*models.Chat) {
+				require.Len(t, chats, 1)
+				chat := chats[0]
+				assert.Equal(t, "Go generics", chat.Title)
+				assert.Equal(t, "user-123", chat.User)
+				require.Len(t, chat.Messages, 2)
+				assert.Equal(t, "What are generics?", chat.Messages[0].Text)
+				assert.Equal(t, "Type parameters.", chat.Messages[1].Text)
+				assert.Equal(t, "gpt-4o", chat.Messages[1].AI)
+				assert.Equal(t, int64(1700000000), chat.CreatedAt.Unix())
+			},
+		},
+		{
+			name:   "native single chat gets new IDs",
+			format: "json",
+			input: `{"id": "chat_1", "user": "someone-else", "title": "Mine", "folder_id": "folder_9",
+				"messages": [{"id": "chat_2", "text": "hi", "role": "user", "user_id": "someone-else",
+					"attachments": [{"id": "att_1", "kind": "document", "name": "secret.md"}]},
+					{"id": "chat_3", "role": "assistant", "tool_calls": [{"id": "call-1", "name": "search_chats"}]},
+					{"id": "chat_4", "role": "tool", "text": "private results", "tool_call_id": "call-1"},
+					{"id": "chat_5", "role": "assistant", "text": "Hello", "ai": "gpt-4o",
+						"citations": [{"number": 1, "knowledge_base_id": "kb_9", "document_id": "kdoc_1"}],
+						"generation": {"platform": "openai", "model": "gpt-4o"}}]}`,
+			setup: func() {
+				mockRepo.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			check: func(t *testing.T, chats []*models.Chat) {
+				require.Len(t, chats, 1)
+				chat := chats[0]
+				assert.NotEqual(t, "chat_1", chat.ID)
+				assert.Equal(t, "user-123", chat.User)
+				assert.Empty(t, chat.FolderID)
+				require.Len(t, chat.Messages, 2, "tool calls and their results are dropped")
+				assert.NotEqual(t, "chat_2", chat.Messages[0].ID)
+				assert.False(t, chat.Messages[0].SentAt.IsZero())
+				assert.Empty(t, chat.Messages[0].Attachments)
+				assert.Empty(t, chat.Messages[0].UserID)
+				assert.Equal(t, "Hello", chat.Messages[1].Text)
+				assert.Equal(t, "gpt-4o", chat.Messages[1].AI)
+				assert.Empty(t, chat.Messages[1].Citations)
+				assert.Nil(t, chat.Messages[1].Generation)
+			},
+		},
+		{
+			name:   "native array",
+			format: "json",
+			input:  ` [{"title": "A"}, {"title": "B"}]`,
+			setup: func() {
+				mockRepo.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			check: func(t *testing.T, chats []*models.Chat) {
+				assert.Len(t, chats, 2)
+			},
+		},
+		{
+			name:    "malformed JSON",
+			format:  "json",
+			input:   `[{"title": "A"`,
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			format:  "csv",
+			input:   `{}`,
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:   "repository error",
+			format: "json",
+			input:  `{"title": "A"}`,
+			setup: func() {
+				mockRepo.EXPECT().CreateChat(gomock.Any(), gomock.Any()).Return(fmt.Errorf("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:   "failed imports are rolled back",
+			format: "json",
+			input:  `[{"title": "A"}, {"title": "B", "messages": [{"text": "no role"}]}]`,
+			setup: func() {
+				var created string
+				mockRepo.EXPECT().CreateChat(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, chat *models.Chat) error {
+						created = chat.ID
+						return nil
+					})
+				mockRepo.EXPECT().DeleteChat(gomock.Any(), gomock.Any(), int64(0)).
+					DoAndReturn(func(ctx context.Context, chatID string, version int64) (*models.Chat, error) {
+						assert.Equal(t, created, chatID)
+						return &models.Chat{ID: chatID}, nil
+					})
+				mockRepo.EXPECT().PurgeChat(gomock.Any(), "user-123", gomock.Any()).Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			chats, err := importService.ImportChats(context.Background(), "user-123", tt.format, strings.NewReader(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, chats)
+		})
+	}
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+// ErrDocumentNotFound and ErrDocumentIndexing are returned by ReindexDocument
+// when the document is gone or another indexing run holds it.
+var (
+	ErrDocumentNotFound = repository.ErrDocumentNotFound
+	ErrDocumentIndexing = repository.ErrDocumentIndexing
+)
+
+const (
+	knowledgeChunkSize    = 2000
+	knowledgeChunkOverlap = 200
+	maxChatKnowledgeBases = 10
+	maxKnowledgeBaseName  = 100
+	// indexingTimeout is how long a document may stay in indexing before it
+	// is considered abandoned, e.g. by a restart, and can be indexed again.
+	indexingTimeout = 30 * time.Minute
+)
+
+const knowledgePreamble = `Use the numbered sources below when they help to answer. Cite each source you
+use by its number in square brackets, such as [1].`
+
+type KnowledgeService struct {
+	knowledgeRepo    repository.KnowledgeRepositoryInterface
+	chatRepo         repository.ChatRepositoryInterface
+	embeddingService EmbeddingServiceInterface
+	topK             int
+}
+
+func NewKnowledgeService(knowledgeRepo repository.KnowledgeRepositoryInterface,
+	chatRepo repository.ChatRepositoryInterface, embeddingService EmbeddingServiceInterface,
+	topK int) *KnowledgeService {
+	return &KnowledgeService{
+		knowledgeRepo:    knowledgeRepo,
+		chatRepo:         chatRepo,
+		embeddingService: embeddingService,
+		topK:             topK,
+	}
+}
+
+// CreateKnowledgeBase fixes the embedding model of a new knowledge base. It
+// cannot be changed later since stored vectors are only comparable with
+// vectors from the same model.
+func (s *KnowledgeService) CreateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error {
+	if knowledgeBase.User == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	name, err := cleanKnowledgeBaseName(knowledgeBase.Name)
+	if err != nil {
+		return err
+	}
+	if _, err := validateEmbeddingModel(knowledgeBase.Platform, knowledgeBase.Model, knowledgeBase.Dimensions); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	knowledgeBase.ID = idgen.GenerateWithPrefix("kb")
+	knowledgeBase.Name = name
+	knowledgeBase.CreatedAt = now
+	knowledgeBase.UpdatedAt = now
+	return s.knowledgeRepo.CreateKnowledgeBase(ctx, knowledgeBase)
+}
+
+func (s *KnowledgeService) GetKnowledgeBase(ctx context.Context, userID string, id string) (*models.KnowledgeBase, error) {
+	return s.knowledgeRepo.GetKnowledgeBase(ctx, userID, id)
+}
+
+func (s *KnowledgeService) ListKnowledgeBases(ctx context.Context, userID string) ([]*models.KnowledgeBase, error) {
+	return s.knowledgeRepo.ListKnowledgeBases(ctx, userID)
+}
+
+// UpdateKnowledgeBase renames or redescribes a knowledge base.
+func (s *KnowledgeService) UpdateKnowledgeBase(ctx context.Context, userID string, id string,
+	name string, description string) (*models.KnowledgeBase, error) {
+	knowledgeBase, err := s.knowledgeRepo.GetKnowledgeBase(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if knowledgeBase.Name, err = cleanKnowledgeBaseName(name); err != nil {
+		return nil, err
+	}
+	knowledgeBase.Description = strings.TrimSpace(description)
+	knowledgeBase.UpdatedAt = time.Now()
+	if err := s.knowledgeRepo.UpdateKnowledgeBase(ctx, knowledgeBase); err != nil {
+		return nil, err
+	}
+	return knowledgeBase, nil
+}
+
+func (s *KnowledgeService) DeleteKnowledgeBase(ctx context.Context, userID string, id string) error {
+	return s.knowledgeRepo.DeleteKnowledgeBase(ctx, userID, id)
+}
+
+func cleanKnowledgeBaseName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	if len([]rune(name)) > maxKnowledgeBaseName {
+		return "", fmt.Errorf("name must be at most %d characters", maxKnowledgeBaseName)
+	}
+	return name, nil
+}
+
+// AddDocument stores the extracted text of a document attachment in the
+// knowledge base. The document starts out indexing; IndexDocument embeds it.
+func (s *KnowledgeService) AddDocument(ctx context.Context, userID string, knowledgeBaseID string,
+	attachment *models.Attachment) (*models.KnowledgeDocument, error) {
+	if attachment.Kind != models.AttachmentKindDocument {
+		return nil, fmt.Errorf("%s is not a document", attachment.Name)
+	}
+	if strings.TrimSpace(attachment.Text) == "" {
+		return nil, fmt.Errorf("%s has no text", attachment.Name)
+	}
+	if _, err := s.knowledgeRepo.GetKnowledgeBase(ctx, userID, knowledgeBaseID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	document := &models.KnowledgeDocument{
+		ID:              idgen.GenerateWithPrefix("kdoc"),
+		User:            userID,
+		KnowledgeBaseID: knowledgeBaseID,
+		Name:            attachment.Name,
+		MimeType:        attachment.MimeType,
+		Size:            attachment.Size,
+		Text:            attachment.Text,
+		Status:          models.DocumentStatusIndexing,
+		CreatedAt:       now,
+		IndexingAt:      &now,
+	}
+	if err := s.knowledgeRepo.CreateDocument(ctx, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func (s *KnowledgeService) ListDocuments(ctx context.Context, userID string, knowledgeBaseID string) ([]*models.KnowledgeDocument, error) {
+	if _, err := s.knowledgeRepo.GetKnowledgeBase(ctx, userID, knowledgeBaseID); err != nil {
+		return nil, err
+	}
+	return s.knowledgeRepo.ListDocuments(ctx, userID, knowledgeBaseID)
+}
+
+func (s *KnowledgeService) GetDocument(ctx context.Context, userID string, knowledgeBaseID string,
+	documentID string) (*models.KnowledgeDocument, error) {
+	document, err := s.knowledgeRepo.GetDocument(ctx, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if document.KnowledgeBaseID != knowledgeBaseID {
+		return nil, repository.ErrDocumentNotFound
+	}
+	return document, nil
+}
+
+func (s *KnowledgeService) DeleteDocument(ctx context.Context, userID string, knowledgeBaseID string, documentID string) error {
+	if _, err := s.GetDocument(ctx, userID, knowledgeBaseID, documentID); err != nil {
+		return err
+	}
+	return s.knowledgeRepo.DeleteDocument(ctx, userID, documentID)
+}
+
+// ReindexDocument marks a document for indexing and returns it with its text
+// for IndexDocument. A document still being indexed is refused unless the run
+// started more than indexingTimeout ago and was lost.
+func (s *KnowledgeService) ReindexDocument(ctx context.Context, userID string, knowledgeBaseID string,
+	documentID string) (*models.KnowledgeDocument, error) {
+	document, err := s.GetDocument(ctx, userID, knowledgeBaseID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.knowledgeRepo.StartIndexing(ctx, userID, documentID, now, now.Add(-indexingTimeout)); err != nil {
+		return nil, err
+	}
+	document.Status = models.DocumentStatusIndexing
+	document.Error = ""
+	document.IndexingAt = &now
+	return document, nil
+}
+
+// IndexDocument splits the document into overlapping chunks, embeds them and
+// replaces its stored chunks. The outcome is recorded on the document; when
+// embedding fails the previous chunks are kept.
+func (s *KnowledgeService) IndexDocument(ctx context.Context, document *models.KnowledgeDocument) error {
+	knowledgeBase, err := s.knowledgeRepo.GetKnowledgeBase(ctx, document.User, document.KnowledgeBaseID)
+	if err != nil {
+		return err
+	}
+
+	chunks, err := s.embedDocument(ctx, knowledgeBase, document)
+	if err == nil {
+		err = s.knowledgeRepo.ReplaceChunks(ctx, document.ID, chunks)
+	}
+	if err != nil {
+		document.Status = models.DocumentStatusFailed
+		document.Error = err.Error()
+	} else {
+		now := time.Now()
+		document.Status = models.DocumentStatusReady
+		document.Error = ""
+		document.ChunkCount = len(chunks)
+		document.IndexedAt = &now
+	}
+
+	if updateErr := s.knowledgeRepo.UpdateDocument(ctx, document); updateErr != nil {
+		if errors.Is(updateErr, repository.ErrDocumentNotFound) {
+			// The document was deleted while it was being indexed.
+			s.knowledgeRepo.ReplaceChunks(ctx, document.ID, nil)
+		}
+		return updateErr
+	}
+	return err
+}
+
+func (s *KnowledgeService) embedDocument(ctx context.Context, knowledgeBase *models.KnowledgeBase,
+	document *models.KnowledgeDocument) ([]*models.KnowledgeChunk, error) {
+	pieces := ChunkText(document.Text, knowledgeChunkSize, knowledgeChunkOverlap)
+	chunks := make([]*models.KnowledgeChunk, 0, len(pieces))
+	for start := 0; start < len(pieces); start += MaxEmbeddingInputs {
+		batch := pieces[start:min(start+MaxEmbeddingInputs, len(pieces))]
+		inputs := make([]string, 0, len(batch))
+		for _, piece := range batch {
+			inputs = append(inputs, piece.Text)
+		}
+
+		response, err := s.embeddingService.CreateEmbeddings(ctx, knowledgeBase.Platform, &models.EmbeddingRequest{
+			Model:      knowledgeBase.Model,
+			Input:      inputs,
+			Dimensions: knowledgeBase.Dimensions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed document: %w", err)
+		}
+		for _, embedding := range response.Data {
+			piece := batch[embedding.Index]
+			chunks = append(chunks, &models.KnowledgeChunk{
+				ID:              idgen.GenerateWithPrefix("chunk"),
+				KnowledgeBaseID: knowledgeBase.ID,
+				DocumentID:      document.ID,
+				DocumentName:    document.Name,
+				Index:           start + embedding.Index,
+				Text:            piece.Text,
+				Start:           piece.Start,
+				End:             piece.End,
+				Embedding:       embedding.Embedding,
+			})
+		}
+	}
+	return chunks, nil
+}
+
+// TextChunk is a piece of a document. Start and End are character offsets
+// into the document's text.
+type TextChunk struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// chunkBreaks are the places a chunk may end, from most to least preferred:
+// paragraphs, lines, sentences and words.
+var chunkBreaks = [][]string{{"\n\n"}, {"\n"}, {". ", "? ", "! "}, {" "}}
+
+// ChunkText splits text into pieces of at most size characters that overlap
+// by about overlap characters. Pieces end at the best break in their second
+// half. Offsets count characters, not bytes, and exclude surrounding
+// whitespace.
+func ChunkText(text string, size int, overlap int) []TextChunk {
+	runes := []rune(text)
+	var chunks []TextChunk
+	for start := 0; start < len(runes); {
+		end := min(start+size, len(runes))
+		if end < len(runes) {
+			end = chunkBreak(runes, start, end)
+		}
+
+		from, to := start, end
+		for from < to && unicode.IsSpace(runes[from]) {
+			from++
+		}
+		for to > from && unicode.IsSpace(runes[to-1]) {
+			to--
+		}
+		if from < to {
+			chunks = append(chunks, TextChunk{Text: string(runes[from:to]), Start: from, End: to})
+		}
+		if end == len(runes) {
+			break
+		}
+
+		// The next piece starts at a word boundary inside the overlap.
+		next := max(end-overlap, start+1)
+		for boundary := next; boundary < end; boundary++ {
+			if unicode.IsSpace(runes[boundary-1]) {
+				next = boundary
+				break
+			}
+		}
+		start = next
+	}
+	return chunks
+}
+
+func chunkBreak(runes []rune, start int, end int) int {
+	lowest := start + (end-start)/2
+	for _, separators := range chunkBreaks {
+		for i := end; i > lowest; i-- {
+			for _, separator := range separators {
+				if hasRunePrefix(runes[:i], separator) {
+					return i
+				}
+			}
+		}
+	}
+	return end
+}
+
+// hasRunePrefix reports whether runes ends with separator.
+func hasRunePrefix(runes []rune, separator string) bool {
+	sep := []rune(separator)
+	if len(runes) < len(sep) {
+		return false
+	}
+	return string(runes[len(runes)-len(sep):]) == separator
+}
+
+// SetChatKnowledgeBases attaches the user's knowledge bases to one of their
+// chats, replacing the ones it had. An empty list detaches them all. A
+// non-zero version restricts it to the chat still at that version.
+func (s *KnowledgeService) SetChatKnowledgeBases(ctx context.Context, userID string, chatID string,
+	knowledgeBaseIDs []string, version int64) (int64, error) {
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, id := range knowledgeBaseIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := s.knowledgeRepo.GetKnowledgeBase(ctx, userID, id); err != nil {
+			return 0, fmt.Errorf("knowledge base %s not found", id)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > maxChatKnowledgeBases {
+		return 0, fmt.Errorf("a chat can use at most %d knowledge bases", maxChatKnowledgeBases)
+	}
+
+	return chatCount(s.chatRepo.UpdateChats(ctx, userID, []string{chatID}, models.ChatUpdate{KnowledgeBaseIDs: ids, Version: version}))
+}
+
+// Retrieve returns the chunks most similar to the query from the user's
+// knowledge bases, best first and numbered from 1. Knowledge bases that no
+// longer exist are skipped. The query is embedded once for each embedding
+// model in use and compared with every chunk by cosine similarity.
+func (s *KnowledgeService) Retrieve(ctx context.Context, userID string, knowledgeBaseIDs []string,
+	query string) ([]models.Citation, error) {
+	if s.topK <= 0 || len(knowledgeBaseIDs) == 0 || strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	knowledgeBases, err := s.knowledgeRepo.ListKnowledgeBases(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	type embeddingSpace struct {
+		platform   string
+		model      string
+		dimensions int
+	}
+	spaces := make(map[embeddingSpace][]string)
+	for _, knowledgeBase := range knowledgeBases {
+		for _, id := range knowledgeBaseIDs {
+			if knowledgeBase.ID == id {
+				space := embeddingSpace{knowledgeBase.Platform, knowledgeBase.Model, knowledgeBase.Dimensions}
+				spaces[space] = append(spaces[space], id)
+			}
+		}
+	}
+
+	var best []models.Citation
+	for space, ids := range spaces {
+		info, _ := validateEmbeddingModel(space.platform, space.model, space.dimensions)
+		input := []rune(query)
+		if limit := int(float64(info.MaxInputTokens) * info.CharsPerToken); len(input) > limit {
+			input = input[:limit]
+		}
+		response, err := s.embeddingService.CreateEmbeddings(ctx, space.platform, &models.EmbeddingRequest{
+			Model:      space.model,
+			Input:      []string{string(input)},
+			Dimensions: space.dimensions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed query: %w", err)
+		}
+		vector := response.Data[0].Embedding
+
+		err = s.knowledgeRepo.ForEachChunk(ctx, ids, func(chunk *models.KnowledgeChunk) error {
+			score, ok := cosineSimilarity(vector, chunk.Embedding)
+			if !ok || (len(best) == s.topK && score <= best[len(best)-1].Score) {
+				return nil
+			}
+			best = append(best, models.Citation{
+				KnowledgeBaseID: chunk.KnowledgeBaseID,
+				DocumentID:      chunk.DocumentID,
+				DocumentName:    chunk.DocumentName,
+				ChunkID:         chunk.ID,
+				Start:           chunk.Start,
+				End:             chunk.End,
+				Score:           score,
+				Text:            chunk.Text,
+			})
+			sort.SliceStable(best, func(i, j int) bool { return best[i].Score > best[j].Score })
+			if len(best) > s.topK {
+				best = best[:s.topK]
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range best {
+		best[i].Number = i + 1
+	}
+	return best, nil
+}
+
+func cosineSimilarity(a []float32, b []float32) (float64, bool) {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0, false
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, false
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB)), true
+}
+
+// KnowledgePrompt is the system message that gives the model the retrieved
+// sources, numbered as they are cited.
+func KnowledgePrompt(citations []models.Citation) string {
+	var sb strings.Builder
+	sb.WriteString(knowledgePreamble)
+	for _, citation := range citations {
+		fmt.Fprintf(&sb, "\n\n<source id=\"%d\" document=%q>\n%s\n</source>",
+			citation.Number, citation.DocumentName, citation.Text)
+	}
+	return sb.String()
+}
+
+// citationMarker matches the markers a model cites sources with, such as [2]
+// or [1, 3].
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// maxPendingMarker bounds how much of a chunk's tail is held back in case a
+// marker continues in the next chunk.
+const maxPendingMarker = 16
+
+// CitationTracker finds the sources a streamed reply cites.
+type CitationTracker struct {
+	citations []models.Citation
+	cited     []models.Citation
+	seen      map[int]bool
+	pending   string
+}
+
+func NewCitationTracker(citations []models.Citation) *CitationTracker {
+	return &CitationTracker{citations: citations, seen: make(map[int]bool)}
+}
+
+// Scan reads the next chunk of the reply and returns the sources it cites for
+// the first time. Markers split across chunks are found once complete.
+func (t *CitationTracker) Scan(chunk string) []models.Citation {
+	text := t.pending + chunk
+	var cited []models.Citation
+	end := 0
+	for _, match := range citationMarker.FindAllStringSubmatchIndex(text, -1) {
+		end = match[1]
+		for _, field := range strings.Split(text[match[2]:match[3]], ",") {
+			number, _ := strconv.Atoi(strings.TrimSpace(field))
+			if number < 1 || number > len(t.citations) || t.seen[number] {
+				continue
+			}
+			t.seen[number] = true
+			cited = append(cited, t.citations[number-1])
+		}
+	}
+
+	t.pending = ""
+	if open := strings.LastIndexByte(text[end:], '['); open >= 0 && len(text)-end-open <= maxPendingMarker {
+		t.pending = text[end+open:]
+	}
+	t.cited = append(t.cited, cited...)
+	return cited
+}
+
+// Cited returns every source cited so far, in the order they were cited.
+func (t *CitationTracker) Cited() []models.Citation {
+	return t.cited
+}
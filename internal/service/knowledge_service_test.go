@@ -0,0 +1,284 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChunkText(t *testing.T) {
+	t.Run("short text is one chunk", func(t *testing.T) {
+		chunks := service.ChunkText("  Hello there.  ", 100, 10)
+		require.Len(t, chunks, 1)
+		assert.Equal(t, service.TextChunk{Text: "Hello there.", Start: 2, End: 14}, chunks[0])
+	})
+
+	t.Run("chunks end at paragraphs and overlap", func(t *testing.T) {
+		text := strings.Repeat("word ", 12) + "\n\n" + strings.Repeat("more ", 12)
+		chunks := service.ChunkText(text, 80, 20)
+		require.Len(t, chunks, 2)
+		assert.Equal(t, strings.TrimSpace(strings.Repeat("word ", 12)), chunks[0].Text)
+		assert.Less(t, chunks[1].Start, chunks[0].End)
+		assert.True(t, strings.HasSuffix(chunks[1].Text, "more"))
+		for _, chunk := range chunks {
+			assert.Equal(t, chunk.Text, string([]rune(text)[chunk.Start:chunk.End]))
+		}
+	})
+
+	t.Run("offsets count characters", func(t *testing.T) {
+		text := strings.Repeat("é ", 100)
+		chunks := service.ChunkText(text, 50, 10)
+		require.Greater(t, len(chunks), 1)
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, len([]rune(chunk.Text)), 50)
+			assert.Equal(t, chunk.Text, string([]rune(text)[chunk.Start:chunk.End]))
+		}
+		assert.Equal(t, 199, chunks[len(chunks)-1].End)
+	})
+
+	t.Run("text without breaks is cut at the size", func(t *testing.T) {
+		chunks := service.ChunkText(strings.Repeat("x", 250), 100, 20)
+		require.Len(t, chunks, 3)
+		assert.Equal(t, 100, chunks[0].End)
+		assert.Equal(t, 80, chunks[1].Start)
+	})
+}
+
+func TestKnowledgeService_ReindexDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKnowledgeRepo := mocks.NewMockKnowledgeRepositoryInterface(ctrl)
+	knowledgeService := service.NewKnowledgeService(mockKnowledgeRepo, nil, nil, 3)
+
+	tests := []struct {
+		name     string
+		startErr error
+		wantErr  error
+	}{
+		{name: "document is claimed"},
+		{name: "indexing right now", startErr: repository.ErrDocumentIndexing, wantErr: service.ErrDocumentIndexing},
+		{name: "deleted meanwhile", startErr: repository.ErrDocumentNotFound, wantErr: service.ErrDocumentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockKnowledgeRepo.EXPECT().GetDocument(gomock.Any(), "user1", "doc1").Return(&models.KnowledgeDocument{
+				ID: "doc1", User: "user1", KnowledgeBaseID: "kb1", Status: models.DocumentStatusFailed, Error: "old",
+			}, nil)
+			mockKnowledgeRepo.EXPECT().StartIndexing(gomock.Any(), "user1", "doc1", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, userID, documentID string, startedAt, staleBefore time.Time) error {
+					assert.WithinDuration(t, time.Now(), startedAt, time.Second)
+					assert.Equal(t, 30*time.Minute, startedAt.Sub(staleBefore))
+					return tt.startErr
+				})
+
+			document, err := knowledgeService.ReindexDocument(context.Background(), "user1", "kb1", "doc1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.DocumentStatusIndexing, document.Status)
+			assert.Empty(t, document.Error)
+			require.NotNil(t, document.IndexingAt)
+			assert.WithinDuration(t, time.Now(), *document.IndexingAt, time.Second)
+		})
+	}
+}
+
+func TestKnowledgeService_IndexDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKnowledgeRepo := mocks.NewMockKnowledgeRepositoryInterface(ctrl)
+	mockEmbedding := mocks.NewMockEmbeddingServiceInterface(ctrl)
+	knowledgeService := service.NewKnowledgeService(mockKnowledgeRepo, nil, mockEmbedding, 3)
+
+	knowledgeBase := &models.KnowledgeBase{ID: "kb1", User: "user1", Platform: "openai", Model: "text-embedding-3-small"}
+	newDocument := func() *models.KnowledgeDocument {
+		return &models.KnowledgeDocument{
+			ID: "doc1", User: "user1", KnowledgeBaseID: "kb1", Name: "notes.md",
+			Text: strings.Repeat("A sentence about Go. ", 200), Status: models.DocumentStatusIndexing,
+		}
+	}
+
+	t.Run("chunks are embedded and stored", func(t *testing.T) {
+		document := newDocument()
+		mockKnowledgeRepo.EXPECT().GetKnowledgeBase(gomock.Any(), "user1", "kb1").Return(knowledgeBase, nil)
+		mockEmbedding.EXPECT().CreateEmbeddings(gomock.Any(), "openai", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, platform string, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+				assert.Equal(t, "text-embedding-3-small", req.Model)
+				return fakeEmbeddings(req, 0), nil
+			})
+		var stored []*models.KnowledgeChunk
+		mockKnowledgeRepo.EXPECT().ReplaceChunks(gomock.Any(), "doc1", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, documentID string, chunks []*models.KnowledgeChunk) error {
+				stored = chunks
+				return nil
+			})
+		mockKnowledgeRepo.EXPECT().UpdateDocument(gomock.Any(), document).Return(nil)
+
+		require.NoError(t, knowledgeService.IndexDocument(context.Background(), document))
+		assert.Equal(t, models.DocumentStatusReady, document.Status)
+		assert.NotNil(t, document.IndexedAt)
+		require.Len(t, stored, 3)
+		assert.Equal(t, document.ChunkCount, len(stored))
+		for i, chunk := range stored {
+			assert.Equal(t, i, chunk.Index)
+			assert.Equal(t, "kb1", chunk.KnowledgeBaseID)
+			assert.Equal(t, "notes.md", chunk.DocumentName)
+			assert.NotEmpty(t, chunk.Embedding)
+		}
+	})
+
+	t.Run("embedding errors mark the document failed", func(t *testing.T) {
+		document := newDocument()
+		mockKnowledgeRepo.EXPECT().GetKnowledgeBase(gomock.Any(), "user1", "kb1").Return(knowledgeBase, nil)
+		mockEmbedding.EXPECT().CreateEmbeddings(gomock.Any(), "openai", gomock.Any()).
+			Return(nil, errors.New("rate limited"))
+		mockKnowledgeRepo.EXPECT().UpdateDocument(gomock.Any(), document).Return(nil)
+
+		err := knowledgeService.IndexDocument(context.Background(), document)
+		require.Error(t, err)
+		assert.Equal(t, models.DocumentStatusFailed, document.Status)
+		assert.Contains(t, document.Error, "rate limited")
+	})
+
+	t.Run("chunks of a deleted document are removed", func(t *testing.T) {
+		document := newDocument()
+		mockKnowledgeRepo.EXPECT().GetKnowledgeBase(gomock.Any(), "user1", "kb1").Return(knowledgeBase, nil)
+		mockEmbedding.EXPECT().CreateEmbeddings(gomock.Any(), "openai", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, platform string, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+				return fakeEmbeddings(req, 0), nil
+			})
+		gomock.InOrder(
+			mockKnowledgeRepo.EXPECT().ReplaceChunks(gomock.Any(), "doc1", gomock.Not(gomock.Nil())).Return(nil),
+			mockKnowledgeRepo.EXPECT().UpdateDocument(gomock.Any(), document).Return(repository.ErrDocumentNotFound),
+			mockKnowledgeRepo.EXPECT().ReplaceChunks(gomock.Any(), "doc1", gomock.Nil()).Return(nil),
+		)
+
+		assert.Error(t, knowledgeService.IndexDocument(context.Background(), document))
+	})
+
+	t.Run("chunks are kept when the update fails otherwise", func(t *testing.T) {
+		document := newDocument()
+		mockKnowledgeRepo.EXPECT().GetKnowledgeBase(gomock.Any(), "user1", "kb1").Return(knowledgeBase, nil)
+		mockEmbedding.EXPECT().CreateEmbeddings(gomock.Any(), "openai", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, platform string, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+				return fakeEmbeddings(req, 0), nil
+			})
+		mockKnowledgeRepo.EXPECT().ReplaceChunks(gomock.Any(), "doc1", gomock.Not(gomock.Nil())).Return(nil)
+		mockKnowledgeRepo.EXPECT().UpdateDocument(gomock.Any(), document).Return(errors.New("connection reset"))
+
+		assert.Error(t, knowledgeService.IndexDocument(context.Background(), document))
+	})
+}
+
+func TestKnowledgeService_Retrieve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKnowledgeRepo := mocks.NewMockKnowledgeRepositoryInterface(ctrl)
+	mockEmbedding := mocks.NewMockEmbeddingServiceInterface(ctrl)
+	knowledgeService := service.NewKnowledgeService(mockKnowledgeRepo, nil, mockEmbedding, 2)
+
+	mockKnowledgeRepo.EXPECT().ListKnowledgeBases(gomock.Any(), "user1").Return([]*models.KnowledgeBase{
+		{ID: "kb1", Platform: "openai", Model: "text-embedding-3-small"},
+		{ID: "kb2", Platform: "openai", Model: "text-embedding-3-small"},
+		{ID: "kb3", Platform: "openai", Model: "text-embedding-3-small"},
+	}, nil)
+	mockEmbedding.EXPECT().CreateEmbeddings(gomock.Any(), "openai", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, platform string, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+			assert.Equal(t, []string{"what is go?"}, req.Input)
+			return &models.EmbeddingResponse{Data: []models.Embedding{{Embedding: []float32{1, 0}}}}, nil
+		})
+	mockKnowledgeRepo.EXPECT().ForEachChunk(gomock.Any(), []string{"kb1", "kb2"}, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, ids []string, fn func(*models.KnowledgeChunk) error) error {
+			for _, chunk := range []*models.KnowledgeChunk{
+				{ID: "c1", DocumentID: "doc1", Embedding: []float32{0, 1}},
+				{ID: "c2", DocumentID: "doc1", Embedding: []float32{1, 1}},
+				{ID: "c3", DocumentID: "doc2", Embedding: []float32{1, 0.1}},
+				{ID: "c4", DocumentID: "doc2", Embedding: []float32{1}},
+			} {
+				if err := fn(chunk); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	citations, err := knowledgeService.Retrieve(context.Background(), "user1", []string{"kb1", "kb2", "gone"}, "what is go?")
+	require.NoError(t, err)
+	require.Len(t, citations, 2)
+	assert.Equal(t, "c3", citations[0].ChunkID)
+	assert.Equal(t, 1, citations[0].Number)
+	assert.Equal(t, "c2", citations[1].ChunkID)
+	assert.Equal(t, 2, citations[1].Number)
+	assert.Greater(t, citations[0].Score, citations[1].Score)
+}
+
+func TestKnowledgeService_SetChatKnowledgeBases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKnowledgeRepo := mocks.NewMockKnowledgeRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	knowledgeService := service.NewKnowledgeService(mockKnowledgeRepo, mockChatRepo, nil, 5)
+
+	t.Run("ids are deduplicated", func(t *testing.T) {
+		mockKnowledgeRepo.EXPECT().GetKnowledgeBase(gomock.Any(), "user1", "kb1").Return(&models.KnowledgeBase{ID: "kb1"}, nil)
+		mockChatRepo.EXPECT().UpdateChats(gomock.Any(), "user1", []string{"chat1"},
+			models.ChatUpdate{KnowledgeBaseIDs: []string{"kb1"}}).Return([]*models.Chat{{ID: "chat1"}}, nil)
+
+		affected, err := knowledgeService.SetChatKnowledgeBases(context.Background(), "user1", "chat1", []string{"kb1", "kb1"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected)
+	})
+
+	t.Run("an empty list detaches them all", func(t *testing.T) {
+		mockChatRepo.EXPECT().UpdateChats(gomock.Any(), "user1", []string{"chat1"},
+			models.ChatUpdate{KnowledgeBaseIDs: []string{}}).Return([]*models.Chat{{ID: "chat1"}}, nil)
+
+		_, err := knowledgeService.SetChatKnowledgeBases(context.Background(), "user1", "chat1", nil, 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("other users' knowledge bases are rejected", func(t *testing.T) {
+		mockKnowledgeRepo.EXPECT().GetKnowledgeBase(gomock.Any(), "user1", "kb9").Return(nil, errors.New("knowledge base not found"))
+
+		_, err := knowledgeService.SetChatKnowledgeBases(context.Background(), "user1", "chat1", []string{"kb9"}, 0)
+		assert.EqualError(t, err, "knowledge base kb9 not found")
+	})
+}
+
+func TestCitationTracker(t *testing.T) {
+	citations := []models.Citation{
+		{Number: 1, DocumentID: "doc1"},
+		{Number: 2, DocumentID: "doc2"},
+		{Number: 3, DocumentID: "doc3"},
+	}
+	tracker := service.NewCitationTracker(citations)
+
+	assert.Empty(t, tracker.Scan("Go is compiled [2"))
+	assert.Equal(t, []models.Citation{citations[1]}, tracker.Scan("]. It has"))
+	assert.Equal(t, []models.Citation{citations[0], citations[2]}, tracker.Scan(" goroutines [1, 3] and [2] and [7]."))
+	assert.Equal(t, []models.Citation{citations[1], citations[0], citations[2]}, tracker.Cited())
+}
+
+func TestKnowledgePrompt(t *testing.T) {
+	prompt := service.KnowledgePrompt([]models.Citation{{Number: 1, DocumentName: "a.md", Text: "Alpha"}})
+	assert.Contains(t, prompt, "[1]")
+	assert.Contains(t, prompt, "<source id=\"1\" document=\"a.md\">\nAlpha\n</source>")
+}
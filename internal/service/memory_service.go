@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+const (
+	maxMemoryLength        = 200
+	maxExtractedMemories   = 5
+	memoryExtractionBatch  = 20
+	memoryExtractTimeout   = 30 * time.Second
+	memoryRetryBackoff     = time.Hour
+	maxMemoryAttempts      = 3
+	memoryPreamble         = "Things you know about the user from earlier conversations:\n"
+	noMemoriesToExtractTag = "NONE"
+)
+
+const memoryExtractionPrompt = `You keep a list of short, durable facts about a user, such as their
+preferences, their role or the projects they work on. Read the conversation
+below and list new facts worth remembering for future conversations. Skip
+anything already known, anything temporary and anything about other people.
+
+Known facts:
+%s
+
+Conversation:
+%s
+
+Reply with one fact per line, each starting with "- ", or with NONE if there
+is nothing new.`
+
+type MemoryService struct {
+	memoryRepo  repository.MemoryRepositoryInterface
+	chatRepo    repository.ChatRepositoryInterface
+	generator   GeneratorInterface
+	platform    string
+	model       string
+	tokenBudget int
+}
+
+func NewMemoryService(memoryRepo repository.MemoryRepositoryInterface, chatRepo repository.ChatRepositoryInterface,
+	generator GeneratorInterface, platform string, model string, tokenBudget int) *MemoryService {
+	return &MemoryService{
+		memoryRepo:  memoryRepo,
+		chatRepo:    chatRepo,
+		generator:   generator,
+		platform:    platform,
+		model:       model,
+		tokenBudget: tokenBudget,
+	}
+}
+
+func (s *MemoryService) AddMemory(ctx context.Context, memory *models.Memory) error {
+	if memory.User == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	text, err := cleanMemory(memory.Text)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	memory.ID = idgen.GenerateWithPrefix("mem")
+	memory.Text = text
+	if memory.Source == "" {
+		memory.Source = models.MemorySourceUser
+	}
+	memory.Enabled = true
+	memory.CreatedAt = now
+	memory.UpdatedAt = now
+
+	return s.memoryRepo.CreateMemory(ctx, memory)
+}
+
+func (s *MemoryService) GetMemory(ctx context.Context, userID string, id string) (*models.Memory, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("memory ID is required")
+	}
+
+	return s.memoryRepo.GetMemory(ctx, userID, id)
+}
+
+func (s *MemoryService) ListMemories(ctx context.Context, userID string) ([]*models.Memory, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	return s.memoryRepo.ListMemories(ctx, userID, false)
+}
+
+func (s *MemoryService) UpdateMemory(ctx context.Context, userID string, id string, update models.MemoryUpdate) (*models.Memory, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("memory ID is required")
+	}
+
+	memory, err := s.memoryRepo.GetMemory(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Text != nil {
+		text, err := cleanMemory(*update.Text)
+		if err != nil {
+			return nil, err
+		}
+		memory.Text = text
+	}
+	if update.Enabled != nil {
+		memory.Enabled = *update.Enabled
+	}
+
+	memory.UpdatedAt = time.Now()
+	if err := s.memoryRepo.UpdateMemory(ctx, memory); err != nil {
+		return nil, err
+	}
+	return memory, nil
+}
+
+func (s *MemoryService) DeleteMemory(ctx context.Context, userID string, id string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if id == "" {
+		return fmt.Errorf("memory ID is required")
+	}
+
+	return s.memoryRepo.DeleteMemory(ctx, userID, id)
+}
+
+// MemoryPrompt returns the system prompt section listing the user's enabled
+// memories, most recently updated first, cut off once the token budget for
+// the target model is spent. It is empty when there is nothing to inject.
+func (s *MemoryService) MemoryPrompt(ctx context.Context, userID string, platform string, model string) (string, error) {
+	if s.tokenBudget <= 0 {
+		return "", nil
+	}
+
+	memories, err := s.memoryRepo.ListMemories(ctx, userID, true)
+	if err != nil {
+		return "", err
+	}
+	if len(memories) == 0 {
+		return "", nil
+	}
+
+	info := catalog.Lookup(platform, model)
+	used := info.EstimateMessageTokens(memoryPreamble)
+
+	var sb strings.Builder
+	sb.WriteString(memoryPreamble)
+	included := 0
+	for _, memory := range memories {
+		line := "- " + memory.Text + "\n"
+		tokens := info.EstimateTokens(line)
+		if used+tokens > s.tokenBudget {
+			break
+		}
+		used += tokens
+		sb.WriteString(line)
+		included++
+	}
+	if included == 0 {
+		return "", nil
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// ExtractFromIdleChats runs the extraction pass over chats that have not
+// changed since idle ago and returns how many memories were added.
+func (s *MemoryService) ExtractFromIdleChats(ctx context.Context, idle time.Duration) (int, error) {
+	chats, err := s.chatRepo.ListChatsPendingMemory(ctx, time.Now().Add(-idle), memoryExtractionBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, chat := range chats {
+		count, err := s.extractFromChat(ctx, chat)
+		added += count
+		if err != nil {
+			log.Printf("Error extracting memories from chat %s: %v", chat.ID, err)
+			s.recordFailure(ctx, chat)
+		}
+	}
+	return added, nil
+}
+
+// recordFailure holds a chat back from extraction for longer after each
+// failed attempt, so chats that keep failing do not fill every batch. After
+// maxMemoryAttempts its pending messages are skipped.
+func (s *MemoryService) recordFailure(ctx context.Context, chat *models.Chat) {
+	failures := chat.MemoryFailures + 1
+	if failures >= maxMemoryAttempts {
+		log.Printf("Giving up extracting memories from chat %s after %d attempts", chat.ID, failures)
+		if err := s.chatRepo.SetMemoryExtractedAt(ctx, chat.ID, time.Now()); err != nil {
+			log.Printf("Error skipping memory extraction for chat %s: %v", chat.ID, err)
+		}
+		return
+	}
+	retryAt := time.Now().Add(memoryRetryBackoff << (failures - 1))
+	if err := s.chatRepo.RecordMemoryFailure(ctx, chat.ID, retryAt); err != nil {
+		log.Printf("Error recording memory extraction failure for chat %s: %v", chat.ID, err)
+	}
+}
+
+func (s *MemoryService) extractFromChat(ctx context.Context, chat *models.Chat) (int, error) {
+	var messages []models.Message
+	ownerTurn := true
+	for _, message := range chat.Messages {
+		// In shared chats only the owner's own turns, with the replies to
+		// them, say something about the owner.
+		if message.Role == "user" {
+			ownerTurn = message.UserID == "" || message.UserID == chat.User
+		}
+		if !ownerTurn {
+			continue
+		}
+		if chat.MemoryExtractedAt != nil && !message.SentAt.After(*chat.MemoryExtractedAt) {
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	added := 0
+	if len(messages) > 0 {
+		existing, err := s.memoryRepo.ListMemories(ctx, chat.User, false)
+		if err != nil {
+			return 0, err
+		}
+
+		facts, err := s.askForFacts(ctx, existing, messages)
+		if err != nil {
+			return 0, err
+		}
+
+		known := make(map[string]bool, len(existing))
+		for _, memory := range existing {
+			known[strings.ToLower(memory.Text)] = true
+		}
+		for _, fact := range facts {
+			if known[strings.ToLower(fact)] || added == maxExtractedMemories {
+				continue
+			}
+			known[strings.ToLower(fact)] = true
+
+			memory := &models.Memory{
+				User:   chat.User,
+				Text:   fact,
+				Source: models.MemorySourceExtracted,
+				ChatID: chat.ID,
+			}
+			if err := s.AddMemory(ctx, memory); err != nil {
+				return added, err
+			}
+			added++
+		}
+	}
+
+	return added, s.chatRepo.SetMemoryExtractedAt(ctx, chat.ID, time.Now())
+}
+
+func (s *MemoryService) askForFacts(ctx context.Context, existing []*models.Memory,
+	messages []models.Message) ([]string, error) {
+	var known strings.Builder
+	for _, memory := range existing {
+		fmt.Fprintf(&known, "- %s\n", memory.Text)
+	}
+	if known.Len() == 0 {
+		known.WriteString("(none)\n")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, memoryExtractTimeout)
+	defer cancel()
+
+	var sb strings.Builder
+	err := s.generator.GenerateResponse(ctx, s.platform, s.model,
+		fmt.Sprintf(memoryExtractionPrompt, known.String(), transcript(messages)),
+		func(chunk string) {
+			sb.WriteString(chunk)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var facts []string
+	for _, line := range strings.Split(sb.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.EqualFold(line, noMemoriesToExtractTag) {
+			continue
+		}
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		if fact, err := cleanMemory(strings.TrimPrefix(line, "- ")); err == nil {
+			facts = append(facts, fact)
+		}
+	}
+	return facts, nil
+}
+
+func cleanMemory(text string) (string, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return "", fmt.Errorf("memory text is required")
+	}
+	if utf8.RuneCountInString(text) > maxMemoryLength {
+		return "", fmt.Errorf("memory text must be at most %d characters", maxMemoryLength)
+	}
+	return text, nil
+}
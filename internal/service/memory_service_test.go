@@ -0,0 +1,288 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMemoryService_AddMemory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMemoryRepo := mocks.NewMockMemoryRepositoryInterface(ctrl)
+	memoryService := service.NewMemoryService(mockMemoryRepo, mocks.NewMockChatRepositoryInterface(ctrl),
+		mocks.NewMockGeneratorInterface(ctrl), "openai", "gpt-4o-mini", 500)
+
+	tests := []struct {
+		name     string
+		memory   *models.Memory
+		setup    func()
+		wantText string
+		wantErr  bool
+	}{
+		{
+			name:   "successful creation",
+			memory: &models.Memory{User: "user-123", Text: "  Prefers   Go  "},
+			setup: func() {
+				mockMemoryRepo.EXPECT().CreateMemory(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantText: "Prefers Go",
+		},
+		{
+			name:    "empty text",
+			memory:  &models.Memory{User: "user-123", Text: "   "},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "text too long",
+			memory:  &models.Memory{User: "user-123", Text: strings.Repeat("a", 201)},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "missing user",
+			memory:  &models.Memory{Text: "Prefers Go"},
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := memoryService.AddMemory(context.Background(), tt.memory)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantText, tt.memory.Text)
+			assert.True(t, strings.HasPrefix(tt.memory.ID, "mem"))
+			assert.Equal(t, models.MemorySourceUser, tt.memory.Source)
+			assert.True(t, tt.memory.Enabled)
+		})
+	}
+}
+
+func TestMemoryService_UpdateMemory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMemoryRepo := mocks.NewMockMemoryRepositoryInterface(ctrl)
+	memoryService := service.NewMemoryService(mockMemoryRepo, mocks.NewMockChatRepositoryInterface(ctrl),
+		mocks.NewMockGeneratorInterface(ctrl), "openai", "gpt-4o-mini", 500)
+
+	disabled := false
+	text := "Works on the billing service"
+	tests := []struct {
+		name    string
+		update  models.MemoryUpdate
+		setup   func()
+		want    *models.Memory
+		wantErr bool
+	}{
+		{
+			name:   "disables and edits a memory",
+			update: models.MemoryUpdate{Text: &text, Enabled: &disabled},
+			setup: func() {
+				mockMemoryRepo.EXPECT().GetMemory(gomock.Any(), "user-123", "mem-1").
+					Return(&models.Memory{ID: "mem-1", User: "user-123", Text: "Old", Enabled: true}, nil)
+				mockMemoryRepo.EXPECT().UpdateMemory(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			want: &models.Memory{ID: "mem-1", User: "user-123", Text: text, Enabled: false},
+		},
+		{
+			name:   "memory of another user",
+			update: models.MemoryUpdate{Enabled: &disabled},
+			setup: func() {
+				mockMemoryRepo.EXPECT().GetMemory(gomock.Any(), "user-123", "mem-1").
+					Return(nil, fmt.Errorf("memory not found"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			memory, err := memoryService.UpdateMemory(context.Background(), "user-123", "mem-1", tt.update)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Text, memory.Text)
+			assert.Equal(t, tt.want.Enabled, memory.Enabled)
+		})
+	}
+}
+
+func TestMemoryService_MemoryPrompt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMemoryRepo := mocks.NewMockMemoryRepositoryInterface(ctrl)
+	memories := []*models.Memory{
+		{ID: "mem-2", User: "user-123", Text: "Prefers Go", Enabled: true},
+		{ID: "mem-1", User: "user-123", Text: strings.Repeat("long fact ", 19), Enabled: true},
+	}
+
+	tests := []struct {
+		name   string
+		budget int
+		setup  func()
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "includes memories within the budget",
+			budget: 500,
+			setup: func() {
+				mockMemoryRepo.EXPECT().ListMemories(gomock.Any(), "user-123", true).Return(memories, nil)
+			},
+			want: []string{"- Prefers Go", "- long fact"},
+		},
+		{
+			name:   "stops when the budget is spent",
+			budget: 30,
+			setup: func() {
+				mockMemoryRepo.EXPECT().ListMemories(gomock.Any(), "user-123", true).Return(memories, nil)
+			},
+			want:   []string{"- Prefers Go"},
+			absent: []string{"long fact"},
+		},
+		{
+			name:   "no enabled memories",
+			budget: 500,
+			setup: func() {
+				mockMemoryRepo.EXPECT().ListMemories(gomock.Any(), "user-123", true).Return([]*models.Memory{}, nil)
+			},
+		},
+		{
+			name:   "disabled by a zero budget",
+			budget: 0,
+			setup:  func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			memoryService := service.NewMemoryService(mockMemoryRepo, mocks.NewMockChatRepositoryInterface(ctrl),
+				mocks.NewMockGeneratorInterface(ctrl), "openai", "gpt-4o-mini", tt.budget)
+
+			prompt, err := memoryService.MemoryPrompt(context.Background(), "user-123", "openai", "gpt-4o")
+			require.NoError(t, err)
+			if len(tt.want) == 0 {
+				assert.Empty(t, prompt)
+				return
+			}
+			for _, want := range tt.want {
+				assert.Contains(t, prompt, want)
+			}
+			for _, absent := range tt.absent {
+				assert.NotContains(t, prompt, absent)
+			}
+		})
+	}
+}
+
+func TestMemoryService_ExtractFromIdleChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMemoryRepo := mocks.NewMockMemoryRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	memoryService := service.NewMemoryService(mockMemoryRepo, mockChatRepo, mockGenerator, "openai", "gpt-4o-mini", 500)
+
+	extractedAt := time.Now().Add(-time.Hour)
+	chat := &models.Chat{
+		ID:                "chat-1",
+		User:              "user-123",
+		MemoryExtractedAt: &extractedAt,
+		Messages: []models.Message{
+			{ID: "msg-1", Role: "user", Text: "old question", SentAt: extractedAt.Add(-time.Minute)},
+			{ID: "msg-2", Role: "user", Text: "I maintain the billing service in Go", SentAt: extractedAt.Add(time.Minute)},
+			{ID: "msg-3", Role: "assistant", Text: "Sure", SentAt: extractedAt.Add(2 * time.Minute)},
+			{ID: "msg-4", Role: "user", UserID: "editor-1", Text: "I live in Lisbon", SentAt: extractedAt.Add(3 * time.Minute)},
+			{ID: "msg-5", Role: "assistant", Text: "Lisbon is lovely", SentAt: extractedAt.Add(4 * time.Minute)},
+		},
+	}
+
+	mockChatRepo.EXPECT().ListChatsPendingMemory(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*models.Chat{chat}, nil)
+	mockMemoryRepo.EXPECT().ListMemories(gomock.Any(), "user-123", false).
+		Return([]*models.Memory{{ID: "mem-1", User: "user-123", Text: "Prefers Go"}}, nil)
+	mockGenerator.EXPECT().
+		GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+			assert.Contains(t, prompt, "billing service")
+			assert.NotContains(t, prompt, "old question")
+			assert.NotContains(t, prompt, "Lisbon")
+			assert.Contains(t, prompt, "- Prefers Go")
+			callback("- prefers go\n- Maintains the billing service\nnot a fact line\n")
+			return nil
+		})
+
+	var created []*models.Memory
+	mockMemoryRepo.EXPECT().CreateMemory(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, memory *models.Memory) error {
+			created = append(created, memory)
+			return nil
+		})
+	mockChatRepo.EXPECT().SetMemoryExtractedAt(gomock.Any(), "chat-1", gomock.Any()).Return(nil)
+
+	added, err := memoryService.ExtractFromIdleChats(context.Background(), 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	require.Len(t, created, 1)
+	assert.Equal(t, "Maintains the billing service", created[0].Text)
+	assert.Equal(t, models.MemorySourceExtracted, created[0].Source)
+	assert.Equal(t, "chat-1", created[0].ChatID)
+	assert.Equal(t, "user-123", created[0].User)
+}
+
+func TestMemoryService_ExtractFromIdleChatsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMemoryRepo := mocks.NewMockMemoryRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	memoryService := service.NewMemoryService(mockMemoryRepo, mockChatRepo, mockGenerator, "openai", "gpt-4o-mini", 500)
+
+	messages := []models.Message{{ID: "msg-1", Role: "user", Text: "I work on payments", SentAt: time.Now()}}
+	fresh := &models.Chat{ID: "chat-1", User: "user-123", Messages: messages}
+	retried := &models.Chat{ID: "chat-2", User: "user-123", Messages: messages, MemoryFailures: 2}
+
+	mockChatRepo.EXPECT().ListChatsPendingMemory(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*models.Chat{fresh, retried}, nil)
+	mockMemoryRepo.EXPECT().ListMemories(gomock.Any(), "user-123", false).Return(nil, nil).Times(2)
+	mockGenerator.EXPECT().
+		GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("provider unavailable")).Times(2)
+
+	before := time.Now()
+	mockChatRepo.EXPECT().RecordMemoryFailure(gomock.Any(), "chat-1", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, chatID string, retryAt time.Time) error {
+			assert.True(t, retryAt.After(before.Add(59*time.Minute)), "the chat is held back")
+			return nil
+		})
+	mockChatRepo.EXPECT().SetMemoryExtractedAt(gomock.Any(), "chat-2", gomock.Any()).Return(nil)
+
+	added, err := memoryService.ExtractFromIdleChats(context.Background(), 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/catalog"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+// promptCacheMargin is how close to expiring a cache may be and still be
+// used. A cache that expires mid-request fails the generation.
+const promptCacheMargin = time.Minute
+
+// PromptCacheScope is who a cached prompt prefix belongs to: the assistant
+// whose system prompt the chat uses, shared by all its chats, or the chat.
+func PromptCacheScope(chat *models.Chat, assistant *models.Assistant) string {
+	if assistant != nil && chat.Settings.SystemPrompt == "" {
+		return "assistant:" + assistant.ID
+	}
+	return "chat:" + chat.ID
+}
+
+// PromptCacheManager keeps provider-side caches of the prompt prefixes marked
+// with CacheControl, one per scope and model. OpenAI and DeepSeek cache
+// prefixes by themselves, so only Gemini caches are managed.
+type PromptCacheManager struct {
+	cacheRepo  repository.PromptCacheRepositoryInterface
+	geminiRepo repository.CachedContentRepositoryInterface
+	ttl        time.Duration
+}
+
+func NewPromptCacheManager(cacheRepo repository.PromptCacheRepositoryInterface,
+	geminiRepo repository.CachedContentRepositoryInterface, ttl time.Duration) *PromptCacheManager {
+	return &PromptCacheManager{
+		cacheRepo:  cacheRepo,
+		geminiRepo: geminiRepo,
+		ttl:        ttl,
+	}
+}
+
+// Prepare points req at a cache of its marked prefix, creating the cache when
+// the prefix is new or has changed. Using a cache extends its lifetime once
+// less than half of the TTL is left, so caches in use never expire. Prefixes
+// smaller than the model's minimum and requests with tools are not cached.
+// On error req is left as it was and can be sent without a cache.
+func (m *PromptCacheManager) Prepare(ctx context.Context, platform string, scope string,
+	req *models.GenerationRequest) error {
+	if m.ttl <= 0 || platform != "gemini" || len(req.Tools) > 0 {
+		return nil
+	}
+	length := models.CachedPrefixLength(req.Messages)
+	if length == 0 {
+		return nil
+	}
+	prefix := req.Messages[:length]
+	info := catalog.Lookup(platform, req.Model)
+	tokens := estimateMessages(info, prefix)
+	if tokens < info.CacheMinTokens {
+		return nil
+	}
+	hash := promptPrefixHash(prefix)
+	now := time.Now()
+
+	cache, err := m.cacheRepo.GetPromptCache(ctx, scope, platform, req.Model)
+	switch {
+	case errors.Is(err, repository.ErrPromptCacheNotFound):
+	case err != nil:
+		return err
+	case cache.Hash == hash && cache.ExpiresAt.After(now.Add(promptCacheMargin)):
+		if cache.ExpiresAt.Sub(now) < m.ttl/2 {
+			expiresAt, err := m.geminiRepo.RefreshCachedContent(ctx, cache.Name, m.ttl)
+			if err != nil {
+				return err
+			}
+			cache.ExpiresAt = expiresAt
+		}
+		cache.LastUsedAt = now
+		if err := m.cacheRepo.UpdatePromptCache(ctx, cache); err != nil {
+			log.Printf("Error updating prompt cache %s: %v", cache.ID, err)
+		}
+		req.CachedContent = cache.Name
+		return nil
+	default:
+		// The prefix has changed or the cache is about to expire.
+		m.remove(ctx, cache)
+	}
+
+	name, expiresAt, err := m.geminiRepo.CreateCachedContent(ctx, req.Model, prefix, m.ttl)
+	if err != nil {
+		return err
+	}
+	cache = &models.PromptCache{
+		ID:         idgen.GenerateWithPrefix("pcache"),
+		Scope:      scope,
+		Platform:   platform,
+		Model:      req.Model,
+		Name:       name,
+		Hash:       hash,
+		Tokens:     tokens,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if err := m.cacheRepo.CreatePromptCache(ctx, cache); err != nil {
+		// A concurrent request tracked its own cache first.
+		if err := m.geminiRepo.DeleteCachedContent(ctx, name); err != nil {
+			log.Printf("Error deleting untracked cached content %s: %v", name, err)
+		}
+		return err
+	}
+	req.CachedContent = name
+	return nil
+}
+
+// DeleteExpired forgets the caches that have expired. Gemini deletes them by
+// itself, so only the records are removed.
+func (m *PromptCacheManager) DeleteExpired(ctx context.Context) (int, error) {
+	caches, err := m.cacheRepo.ListExpiredPromptCaches(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, cache := range caches {
+		if err := m.cacheRepo.DeletePromptCache(ctx, cache.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// remove deletes a cache that is no longer wanted. A failed provider
+// deletion only leaves the cache to expire on its own.
+func (m *PromptCacheManager) remove(ctx context.Context, cache *models.PromptCache) {
+	if cache.ExpiresAt.After(time.Now()) {
+		if err := m.geminiRepo.DeleteCachedContent(ctx, cache.Name); err != nil {
+			log.Printf("Error deleting cached content %s: %v", cache.Name, err)
+		}
+	}
+	if err := m.cacheRepo.DeletePromptCache(ctx, cache.ID); err != nil {
+		log.Printf("Error deleting prompt cache %s: %v", cache.ID, err)
+	}
+}
+
+// promptPrefixHash identifies the content of a prefix, so a cache is
+// replaced when a system prompt is edited.
+func promptPrefixHash(messages []models.Message) string {
+	hash := sha256.New()
+	for _, message := range messages {
+		hash.Write([]byte(message.Role))
+		hash.Write([]byte{0})
+		hash.Write([]byte(message.Text))
+		hash.Write([]byte{0})
+		for _, attachment := range message.Attachments {
+			hash.Write([]byte(attachment.ID))
+			hash.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
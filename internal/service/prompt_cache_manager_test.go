@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func cacheableRequest(systemPrompt string) *models.GenerationRequest {
+	return &models.GenerationRequest{
+		Model: "gemini-2.0-flash",
+		Messages: []models.Message{
+			{Text: systemPrompt, Role: "system", CacheControl: true},
+			{Text: "Remember that I prefer metric units.", Role: "system"},
+			{Text: "How far is Lisbon from Porto?", Role: "user"},
+		},
+	}
+}
+
+func TestPromptCacheManager_Prepare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCacheRepo := mocks.NewMockPromptCacheRepositoryInterface(ctrl)
+	mockGemini := mocks.NewMockCachedContentRepositoryInterface(ctrl)
+	manager := service.NewPromptCacheManager(mockCacheRepo, mockGemini, time.Hour)
+
+	ctx := context.Background()
+	systemPrompt := strings.Repeat("You are a travel assistant. ", 1000)
+	var tracked *models.PromptCache
+
+	t.Run("a new prefix is cached", func(t *testing.T) {
+		req := cacheableRequest(systemPrompt)
+		mockCacheRepo.EXPECT().GetPromptCache(ctx, "assistant:asst1", "gemini", "gemini-2.0-flash").
+			Return(nil, repository.ErrPromptCacheNotFound)
+		mockGemini.EXPECT().CreateCachedContent(ctx, "gemini-2.0-flash", req.Messages[:1], time.Hour).
+			Return("cachedContents/abc", time.Now().Add(time.Hour), nil)
+		mockCacheRepo.EXPECT().CreatePromptCache(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, cache *models.PromptCache) error {
+				tracked = cache
+				return nil
+			})
+
+		require.NoError(t, manager.Prepare(ctx, "gemini", "assistant:asst1", req))
+		assert.Equal(t, "cachedContents/abc", req.CachedContent)
+		require.NotNil(t, tracked)
+		assert.Equal(t, "assistant:asst1", tracked.Scope)
+		assert.Greater(t, tracked.Tokens, 4096)
+	})
+
+	t.Run("a cached prefix is reused", func(t *testing.T) {
+		req := cacheableRequest(systemPrompt)
+		mockCacheRepo.EXPECT().GetPromptCache(ctx, "assistant:asst1", "gemini", "gemini-2.0-flash").
+			Return(tracked, nil)
+		mockCacheRepo.EXPECT().UpdatePromptCache(ctx, tracked).Return(nil)
+
+		require.NoError(t, manager.Prepare(ctx, "gemini", "assistant:asst1", req))
+		assert.Equal(t, "cachedContents/abc", req.CachedContent)
+	})
+
+	t.Run("a cache past half its lifetime is refreshed", func(t *testing.T) {
+		req := cacheableRequest(systemPrompt)
+		expiring := *tracked
+		expiring.ExpiresAt = time.Now().Add(10 * time.Minute)
+		refreshed := time.Now().Add(time.Hour)
+		mockCacheRepo.EXPECT().GetPromptCache(ctx, "assistant:asst1", "gemini", "gemini-2.0-flash").
+			Return(&expiring, nil)
+		mockGemini.EXPECT().RefreshCachedContent(ctx, "cachedContents/abc", time.Hour).Return(refreshed, nil)
+		mockCacheRepo.EXPECT().UpdatePromptCache(ctx, &expiring).Return(nil)
+
+		require.NoError(t, manager.Prepare(ctx, "gemini", "assistant:asst1", req))
+		assert.Equal(t, refreshed, expiring.ExpiresAt)
+	})
+
+	t.Run("an edited prefix replaces the cache", func(t *testing.T) {
+		req := cacheableRequest(systemPrompt + "Answer in Portuguese.")
+		mockCacheRepo.EXPECT().GetPromptCache(ctx, "assistant:asst1", "gemini", "gemini-2.0-flash").
+			Return(tracked, nil)
+		mockGemini.EXPECT().DeleteCachedContent(ctx, "cachedContents/abc").Return(nil)
+		mockCacheRepo.EXPECT().DeletePromptCache(ctx, tracked.ID).Return(nil)
+		mockGemini.EXPECT().CreateCachedContent(ctx, "gemini-2.0-flash", req.Messages[:1], time.Hour).
+			Return("cachedContents/def", time.Now().Add(time.Hour), nil)
+		mockCacheRepo.EXPECT().CreatePromptCache(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, manager.Prepare(ctx, "gemini", "assistant:asst1", req))
+		assert.Equal(t, "cachedContents/def", req.CachedContent)
+	})
+
+	t.Run("requests that are not cached", func(t *testing.T) {
+		short := cacheableRequest("You are a travel assistant.")
+		withTools := cacheableRequest(systemPrompt)
+		withTools.Tools = []models.ToolDefinition{{Name: "get_weather"}}
+		unmarked := cacheableRequest(systemPrompt)
+		unmarked.Messages[0].CacheControl = false
+
+		require.NoError(t, manager.Prepare(ctx, "openai", "chat:chat1", cacheableRequest(systemPrompt)))
+		require.NoError(t, manager.Prepare(ctx, "gemini", "chat:chat1", short))
+		require.NoError(t, manager.Prepare(ctx, "gemini", "chat:chat1", withTools))
+		require.NoError(t, manager.Prepare(ctx, "gemini", "chat:chat1", unmarked))
+		assert.Empty(t, short.CachedContent)
+		assert.Empty(t, withTools.CachedContent)
+		assert.Empty(t, unmarked.CachedContent)
+	})
+}
+
+func TestPromptCacheManager_DeleteExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCacheRepo := mocks.NewMockPromptCacheRepositoryInterface(ctrl)
+	manager := service.NewPromptCacheManager(mockCacheRepo, nil, time.Hour)
+
+	mockCacheRepo.EXPECT().ListExpiredPromptCaches(gomock.Any(), gomock.Any()).
+		Return([]*models.PromptCache{{ID: "pcache1"}, {ID: "pcache2"}}, nil)
+	mockCacheRepo.EXPECT().DeletePromptCache(gomock.Any(), "pcache1").Return(nil)
+	mockCacheRepo.EXPECT().DeletePromptCache(gomock.Any(), "pcache2").Return(nil)
+
+	deleted, err := manager.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+}
+
+func TestPromptCacheScope(t *testing.T) {
+	chat := &models.Chat{ID: "chat1"}
+	assistant := &models.Assistant{ID: "asst1"}
+
+	assert.Equal(t, "assistant:asst1", service.PromptCacheScope(chat, assistant))
+	assert.Equal(t, "chat:chat1", service.PromptCacheScope(chat, nil))
+
+	chat.Settings.SystemPrompt = "Be brief."
+	assert.Equal(t, "chat:chat1", service.PromptCacheScope(chat, assistant))
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/lutefd/ai-router-go/internal/models"
 )
@@ -13,6 +15,19 @@ type AIServiceInterface interface {
 		callback func(string)) error
 	GenerateDeepSeekResponse(ctx context.Context, model string, prompt string,
 		callback func(string)) error
+	GenerateChatResponse(ctx context.Context, req *models.GenerationRequest,
+		callback func(string)) error
+	GenerateOpenAIChatResponse(ctx context.Context, req *models.GenerationRequest,
+		callback func(string)) error
+	GenerateDeepSeekChatResponse(ctx context.Context, req *models.GenerationRequest,
+		callback func(string)) error
+}
+
+type GeneratorInterface interface {
+	GenerateResponse(ctx context.Context, platform string, model string,
+		prompt string, callback func(string)) error
+	GenerateChatResponse(ctx context.Context, platform string,
+		req *models.GenerationRequest, callback func(string)) error
 }
 
 type AuthServiceInterface interface {
@@ -27,9 +42,156 @@ type ChatServiceInterface interface {
 	CreateChat(ctx context.Context, chat *models.Chat) error
 	GetChat(ctx context.Context, id string) (*models.Chat, error)
 	UpdateChat(ctx context.Context, chat *models.Chat) error
-	DeleteChat(ctx context.Context, id string) error
+	DeleteChat(ctx context.Context, id string, version int64) error
+	AddMessages(ctx context.Context, id string, messages ...models.Message) (*models.Chat, error)
+	UpdateChats(ctx context.Context, userID string, chatIDs []string, update models.ChatUpdate) (int64, error)
+	DeleteChats(ctx context.Context, userID string, chatIDs []string) (int64, error)
+	ListTrash(ctx context.Context, userID string) ([]*models.UserChat, error)
+	RestoreChat(ctx context.Context, userID string, id string) error
+	PurgeChat(ctx context.Context, userID string, id string) error
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	ResetSummary(ctx context.Context, id string) error
+	UpdateSettings(ctx context.Context, id string, version int64, settings models.ChatSettings) (*models.Chat, error)
+	ForkChat(ctx context.Context, userID string, id string, messageID string) (*models.Chat, error)
+}
+
+type FolderServiceInterface interface {
+	CreateFolder(ctx context.Context, folder *models.Folder) error
+	GetFolder(ctx context.Context, id string) (*models.Folder, error)
+	ListFolders(ctx context.Context, userID string) ([]*models.Folder, error)
+	RenameFolder(ctx context.Context, id string, name string) (*models.Folder, error)
+	DeleteFolder(ctx context.Context, userID string, id string) error
+	MoveChats(ctx context.Context, userID string, folderID string, chatIDs []string, version int64) (int64, error)
+}
+
+type TitleServiceInterface interface {
+	GenerateTitle(ctx context.Context, chat *models.Chat) (string, error)
+}
+
+type SummaryServiceInterface interface {
+	UpdateSummary(ctx context.Context, chat *models.Chat) error
+}
+
+type ContextBuilderInterface interface {
+	BuildContext(ctx context.Context, platform string, model string,
+		messages []models.Message) ([]models.Message, *ContextReport, error)
+}
+
+type ExportServiceInterface interface {
+	ExportChat(ctx context.Context, w io.Writer, chat *models.Chat, format string) error
+	ExportUserChats(ctx context.Context, w io.Writer, userID string, format string) error
+}
+
+type ImportServiceInterface interface {
+	ImportChats(ctx context.Context, userID string, format string, r io.Reader) ([]*models.Chat, error)
+}
+
+type ShareServiceInterface interface {
+	CreateShare(ctx context.Context, chat *models.Chat, opts ShareOptions) (*models.Share, error)
+	GetShare(ctx context.Context, shareID string) (*models.Share, error)
+	ListShares(ctx context.Context, chatID string) ([]*models.Share, error)
+	RevokeShare(ctx context.Context, shareID string) error
+	GetSharedChat(ctx context.Context, token string) (*models.SharedChat, error)
 }
 
 type UserServiceInterface interface {
-	GetUsersChatList(ctx context.Context, userID string) ([]*models.UserChat, error)
+	GetUsersChatList(ctx context.Context, userID string, filter models.ChatListFilter) ([]*models.UserChat, error)
+}
+
+type TemplateServiceInterface interface {
+	CreateTemplate(ctx context.Context, tmpl *models.PromptTemplate) error
+	GetTemplate(ctx context.Context, id string) (*models.PromptTemplate, error)
+	ListTemplates(ctx context.Context, userID string) ([]*models.PromptTemplate, error)
+	UpdateTemplate(ctx context.Context, id string, update models.PromptTemplateUpdate) (*models.PromptTemplate, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	ListVersions(ctx context.Context, id string) ([]*models.PromptTemplateVersion, error)
+	RenderTemplate(ctx context.Context, tmpl *models.PromptTemplate, version int, variables map[string]any) (string, error)
+}
+
+type AssistantServiceInterface interface {
+	CreateAssistant(ctx context.Context, assistant *models.Assistant) error
+	GetAssistant(ctx context.Context, id string) (*models.Assistant, error)
+	ListAssistants(ctx context.Context, userID string) ([]*models.Assistant, error)
+	UpdateAssistant(ctx context.Context, id string, update models.AssistantUpdate) (*models.Assistant, error)
+	DeleteAssistant(ctx context.Context, id string) error
+	SetChatAssistant(ctx context.Context, userID string, assistantID string, chatIDs []string, version int64) (int64, error)
+}
+
+type MemoryServiceInterface interface {
+	AddMemory(ctx context.Context, memory *models.Memory) error
+	GetMemory(ctx context.Context, userID string, id string) (*models.Memory, error)
+	ListMemories(ctx context.Context, userID string) ([]*models.Memory, error)
+	UpdateMemory(ctx context.Context, userID string, id string, update models.MemoryUpdate) (*models.Memory, error)
+	DeleteMemory(ctx context.Context, userID string, id string) error
+	MemoryPrompt(ctx context.Context, userID string, platform string, model string) (string, error)
+	ExtractFromIdleChats(ctx context.Context, idle time.Duration) (int, error)
+}
+
+type ChatMemberServiceInterface interface {
+	ListMembers(ctx context.Context, chatID string) ([]models.ChatMember, error)
+	AddMember(ctx context.Context, chatID string, email string, role string) (*models.ChatMember, error)
+	UpdateMemberRole(ctx context.Context, chatID string, userID string, role string) (*models.ChatMember, error)
+	RemoveMember(ctx context.Context, chatID string, userID string) error
+}
+
+type EventBrokerInterface interface {
+	Publish(ctx context.Context, event *models.ChatEvent) error
+	Subscribe(ctx context.Context, userID string, resumeToken string) (<-chan *models.ChatEvent, error)
+}
+
+type AttachmentServiceInterface interface {
+	NewAttachment(userID string, name string, data []byte) (*models.Attachment, error)
+	SaveAttachments(ctx context.Context, chatID string, attachments []models.Attachment) error
+	GetAttachment(ctx context.Context, id string) (*models.Attachment, error)
+	ReadAttachment(ctx context.Context, id string) ([]byte, error)
+	ListAttachments(ctx context.Context, chatID string) ([]*models.Attachment, error)
+	LoadAttachmentData(ctx context.Context, chat *models.Chat, messages []models.Message, images bool) ([]models.Message, error)
+}
+
+type ToolRunnerInterface interface {
+	Tools() []models.ToolDefinition
+	ServerTools(names []string) ([]models.ToolDefinition, error)
+	Generate(ctx context.Context, platform string, req *models.GenerationRequest, scope ToolScope,
+		callback func(string), onRun func(*models.ToolRun)) ([]models.Message, error)
+	ListToolRuns(ctx context.Context, userID string, chatID string) ([]*models.ToolRun, error)
+}
+
+type StructuredOutputServiceInterface interface {
+	Generate(ctx context.Context, platform string, req *models.GenerationRequest) (string, error)
+}
+
+type EmbeddingServiceInterface interface {
+	CreateEmbeddings(ctx context.Context, platform string, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error)
+}
+
+type KnowledgeServiceInterface interface {
+	CreateKnowledgeBase(ctx context.Context, knowledgeBase *models.KnowledgeBase) error
+	GetKnowledgeBase(ctx context.Context, userID string, id string) (*models.KnowledgeBase, error)
+	ListKnowledgeBases(ctx context.Context, userID string) ([]*models.KnowledgeBase, error)
+	UpdateKnowledgeBase(ctx context.Context, userID string, id string, name string, description string) (*models.KnowledgeBase, error)
+	DeleteKnowledgeBase(ctx context.Context, userID string, id string) error
+	AddDocument(ctx context.Context, userID string, knowledgeBaseID string, attachment *models.Attachment) (*models.KnowledgeDocument, error)
+	ListDocuments(ctx context.Context, userID string, knowledgeBaseID string) ([]*models.KnowledgeDocument, error)
+	GetDocument(ctx context.Context, userID string, knowledgeBaseID string, documentID string) (*models.KnowledgeDocument, error)
+	DeleteDocument(ctx context.Context, userID string, knowledgeBaseID string, documentID string) error
+	ReindexDocument(ctx context.Context, userID string, knowledgeBaseID string, documentID string) (*models.KnowledgeDocument, error)
+	IndexDocument(ctx context.Context, document *models.KnowledgeDocument) error
+	SetChatKnowledgeBases(ctx context.Context, userID string, chatID string, knowledgeBaseIDs []string, version int64) (int64, error)
+	Retrieve(ctx context.Context, userID string, knowledgeBaseIDs []string, query string) ([]models.Citation, error)
+}
+
+type ImageServiceInterface interface {
+	GenerateImages(ctx context.Context, platform string, req *models.ImageRequest) ([]models.GeneratedImage, error)
+	SaveImages(ctx context.Context, chat *models.Chat, userID string, platform string,
+		req *models.ImageRequest, images []models.GeneratedImage) (*models.Chat, *models.ImageResponse, error)
+}
+
+type AudioServiceInterface interface {
+	Transcribe(ctx context.Context, platform string, req *models.TranscriptionRequest) (*models.Transcription, error)
+	Speak(ctx context.Context, platform string, req *models.SpeechRequest) (io.ReadCloser, error)
+}
+
+type PromptCacheManagerInterface interface {
+	Prepare(ctx context.Context, platform string, scope string, req *models.GenerationRequest) error
+	DeleteExpired(ctx context.Context) (int, error)
 }
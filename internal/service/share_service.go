@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+type ShareOptions struct {
+	MessageID  string
+	ExpiresIn  time.Duration
+	HideModels bool
+}
+
+type ShareService struct {
+	shareRepo repository.ShareRepositoryInterface
+	chatRepo  repository.ChatRepositoryInterface
+}
+
+func NewShareService(shareRepo repository.ShareRepositoryInterface,
+	chatRepo repository.ChatRepositoryInterface) *ShareService {
+	return &ShareService{
+		shareRepo: shareRepo,
+		chatRepo:  chatRepo,
+	}
+}
+
+func (s *ShareService) CreateShare(ctx context.Context, chat *models.Chat, opts ShareOptions) (*models.Share, error) {
+	if opts.ExpiresIn < 0 {
+		return nil, fmt.Errorf("expiry must be in the future")
+	}
+
+	messages, err := messagesUpTo(chat.Messages, opts.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]models.Message, len(messages))
+	copy(snapshot, messages)
+	if opts.HideModels {
+		for i := range snapshot {
+			snapshot[i].AI = ""
+		}
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	share := &models.Share{
+		ID:         idgen.GenerateWithPrefix("share"),
+		Token:      token,
+		ChatID:     chat.ID,
+		User:       chat.User,
+		Title:      chat.Title,
+		Messages:   snapshot,
+		HideModels: opts.HideModels,
+		CreatedAt:  time.Now(),
+	}
+	if opts.ExpiresIn > 0 {
+		expiresAt := share.CreatedAt.Add(opts.ExpiresIn)
+		share.ExpiresAt = &expiresAt
+	}
+
+	if err := s.shareRepo.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (s *ShareService) GetShare(ctx context.Context, shareID string) (*models.Share, error) {
+	if shareID == "" {
+		return nil, fmt.Errorf("share ID is required")
+	}
+
+	return s.shareRepo.GetShare(ctx, shareID)
+}
+
+func (s *ShareService) ListShares(ctx context.Context, chatID string) ([]*models.Share, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat ID is required")
+	}
+
+	return s.shareRepo.ListShares(ctx, chatID)
+}
+
+func (s *ShareService) RevokeShare(ctx context.Context, shareID string) error {
+	if shareID == "" {
+		return fmt.Errorf("share ID is required")
+	}
+
+	return s.shareRepo.DeleteShare(ctx, shareID)
+}
+
+func (s *ShareService) GetSharedChat(ctx context.Context, token string) (*models.SharedChat, error) {
+	if token == "" {
+		return nil, fmt.Errorf("share not found")
+	}
+
+	share, err := s.shareRepo.GetShareByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if share.ExpiresAt != nil && time.Now().After(*share.ExpiresAt) {
+		return nil, fmt.Errorf("share not found")
+	}
+	// Shares of a trashed chat stay hidden until it is restored.
+	if _, err := s.chatRepo.GetChat(ctx, share.ChatID); err != nil {
+		return nil, fmt.Errorf("share not found")
+	}
+
+	shared := &models.SharedChat{
+		Title:     share.Title,
+		Messages:  make([]models.SharedMessage, 0, len(share.Messages)),
+		SharedAt:  share.CreatedAt,
+		ExpiresAt: share.ExpiresAt,
+	}
+	for _, message := range share.Messages {
+		shared.Messages = append(shared.Messages, models.SharedMessage{
+			Text:   message.Text,
+			Role:   message.Role,
+			AI:     message.AI,
+			SentAt: message.SentAt,
+		})
+	}
+	return shared, nil
+}
+
+func messagesUpTo(messages []models.Message, messageID string) ([]models.Message, error) {
+	if messageID == "" {
+		return messages, nil
+	}
+	for i, message := range messages {
+		if message.ID == messageID {
+			return messages[:i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("message not found in chat")
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
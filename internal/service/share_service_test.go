@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestShareService_CreateShare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepositoryInterface(ctrl)
+	shareService := service.NewShareService(mockRepo, nil)
+
+	chat := &models.Chat{
+		ID:    "chat-123",
+		User:  "user-123",
+		Title: "Shared",
+		Messages: []models.Message{
+			{ID: "msg-1", Text: "Question", Role: "user"},
+			{ID: "msg-2", Text: "Answer", Role: "assistant", AI: "gpt-4o"},
+			{ID: "msg-3", Text: "Follow-up", Role: "user"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		opts    service.ShareOptions
+		setup   func()
+		check   func(t *testing.T, share *models.Share)
+		wantErr bool
+	}{
+		{
+			name: "snapshot up to message with hidden models",
+			opts: service.ShareOptions{MessageID: "msg-2", HideModels: true, ExpiresIn: time.Hour},
+			setup: func() {
+				mockRepo.EXPECT().CreateShare(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			check: func(t *testing.T, share *models.Share) {
+				require.Len(t, share.Messages, 2)
+				assert.Empty(t, share.Messages[1].AI)
+				assert.Equal(t, "gpt-4o", chat.Messages[1].AI, "the original chat is left untouched")
+				require.NotNil(t, share.ExpiresAt)
+				assert.NotContains(t, share.Token, chat.ID)
+				assert.GreaterOrEqual(t, len(share.Token), 32)
+			},
+		},
+		{
+			name: "whole chat without expiry",
+			opts: service.ShareOptions{},
+			setup: func() {
+				mockRepo.EXPECT().CreateShare(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			check: func(t *testing.T, share *models.Share) {
+				assert.Len(t, share.Messages, 3)
+				assert.Nil(t, share.ExpiresAt)
+			},
+		},
+		{
+			name:    "unknown message",
+			opts:    service.ShareOptions{MessageID: "msg-9"},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "negative expiry",
+			opts:    service.ShareOptions{ExpiresIn: -time.Hour},
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			share, err := shareService.CreateShare(context.Background(), chat, tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, share)
+		})
+	}
+}
+
+func TestShareService_GetSharedChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockShareRepositoryInterface(ctrl)
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	shareService := service.NewShareService(mockRepo, mockChatRepo)
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		token   string
+		setup   func()
+		wantErr bool
+	}{
+		{
+			name:  "active share",
+			token: "active",
+			setup: func() {
+				mockRepo.EXPECT().GetShareByToken(gomock.Any(), "active").Return(&models.Share{
+					ChatID:    "chat-123",
+					Title:     "Shared",
+					Messages:  []models.Message{{ID: "msg-1", Text: "Hi", Role: "user"}},
+					ExpiresAt: &future,
+				}, nil)
+				mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-123").Return(&models.Chat{ID: "chat-123"}, nil)
+			},
+		},
+		{
+			name:  "trashed chat",
+			token: "trashed",
+			setup: func() {
+				mockRepo.EXPECT().GetShareByToken(gomock.Any(), "trashed").Return(&models.Share{ChatID: "chat-456"}, nil)
+				mockChatRepo.EXPECT().GetChat(gomock.Any(), "chat-456").Return(nil, fmt.Errorf("chat not found"))
+			},
+			wantErr: true,
+		},
+		{
+			name:  "expired share",
+			token: "expired",
+			setup: func() {
+				mockRepo.EXPECT().GetShareByToken(gomock.Any(), "expired").Return(&models.Share{ExpiresAt: &past}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:  "unknown token",
+			token: "missing",
+			setup: func() {
+				mockRepo.EXPECT().GetShareByToken(gomock.Any(), "missing").Return(nil, fmt.Errorf("share not found"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			shared, err := shareService.GetSharedChat(context.Background(), tt.token)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "Shared", shared.Title)
+			require.Len(t, shared.Messages, 1)
+			assert.Equal(t, "Hi", shared.Messages[0].Text)
+		})
+	}
+}
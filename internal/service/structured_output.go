@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lutefd/ai-router-go/internal/jsonschema"
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const defaultResponseSchemaName = "response"
+
+const schemaRepairPrompt = `Your reply does not match the required JSON Schema: %s
+Reply again with only the corrected JSON document.`
+
+// ValidateResponseSchema checks that a response schema compiles and that its
+// name, when given, is one every provider accepts.
+func ValidateResponseSchema(schema *models.ResponseSchema) error {
+	if schema.Name != "" && !toolNamePattern.MatchString(schema.Name) {
+		return fmt.Errorf("invalid response schema name %q", schema.Name)
+	}
+	if _, err := jsonschema.Compile(schema.Schema); err != nil {
+		return fmt.Errorf("invalid response schema: %w", err)
+	}
+	return nil
+}
+
+type StructuredOutputService struct {
+	generator GeneratorInterface
+	retries   int
+}
+
+func NewStructuredOutputService(generator GeneratorInterface, retries int) *StructuredOutputService {
+	return &StructuredOutputService{
+		generator: generator,
+		retries:   retries,
+	}
+}
+
+// Generate returns the model's complete reply to a request with a response
+// schema, compacted, once it validates. An invalid reply is sent back to the
+// model with the validation error up to the configured number of times. When
+// every attempt fails the error wraps the last *jsonschema.ValidationError.
+func (s *StructuredOutputService) Generate(ctx context.Context, platform string,
+	req *models.GenerationRequest) (string, error) {
+	schema, err := jsonschema.Compile(req.ResponseSchema.Schema)
+	if err != nil {
+		return "", fmt.Errorf("invalid response schema: %w", err)
+	}
+
+	request := *req
+	request.Messages = append([]models.Message(nil), req.Messages...)
+	if request.ResponseSchema.Name == "" {
+		request.ResponseSchema = &models.ResponseSchema{Name: defaultResponseSchemaName, Schema: req.ResponseSchema.Schema}
+	}
+
+	var validationErr error
+	for attempt := 0; attempt <= s.retries; attempt++ {
+		var sb strings.Builder
+		err := s.generator.GenerateChatResponse(ctx, platform, &request, func(chunk string) {
+			sb.WriteString(chunk)
+		})
+		if err != nil {
+			return "", err
+		}
+
+		output := jsonOutput(sb.String())
+		validationErr = schema.Validate([]byte(output))
+		if validationErr == nil {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, []byte(output)); err != nil {
+				return "", err
+			}
+			return compact.String(), nil
+		}
+
+		log.Printf("Reply of %s does not match the response schema (attempt %d): %v", req.Model, attempt+1, validationErr)
+		request.Messages = append(request.Messages,
+			models.Message{Text: output, Role: "assistant"},
+			models.Message{Text: fmt.Sprintf(schemaRepairPrompt, validationErr), Role: "user"},
+		)
+	}
+	return "", fmt.Errorf("reply does not match the response schema after %d attempts: %w",
+		s.retries+1, validationErr)
+}
+
+// jsonOutput strips the Markdown code fence some models wrap JSON in.
+func jsonOutput(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+		text = text[newline+1:]
+	} else {
+		text = strings.TrimPrefix(text, "```")
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
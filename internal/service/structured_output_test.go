@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/jsonschema"
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var cityResponseSchema = &models.ResponseSchema{Schema: json.RawMessage(`{
+	"type": "object",
+	"properties": {"city": {"type": "string"}, "population": {"type": "integer"}},
+	"required": ["city", "population"]
+}`)}
+
+func TestStructuredOutputService_Generate(t *testing.T) {
+	tests := []struct {
+		name       string
+		retries    int
+		replies    []string
+		want       string
+		wantCalls  int
+		wantPath   string
+		checkRetry func(t *testing.T, req *models.GenerationRequest)
+	}{
+		{
+			name:      "valid reply is compacted",
+			retries:   2,
+			replies:   []string{"{\n  \"city\": \"Lisbon\",\n  \"population\": 545000\n}"},
+			want:      `{"city":"Lisbon","population":545000}`,
+			wantCalls: 1,
+		},
+		{
+			name:      "code fence is stripped",
+			retries:   0,
+			replies:   []string{"```json\n{\"city\": \"Porto\", \"population\": 232000}\n```"},
+			want:      `{"city":"Porto","population":232000}`,
+			wantCalls: 1,
+		},
+		{
+			name:      "invalid reply is repaired",
+			retries:   2,
+			replies:   []string{`{"city": "Lisbon", "population": "545k"}`, `{"city": "Lisbon", "population": 545000}`},
+			want:      `{"city":"Lisbon","population":545000}`,
+			wantCalls: 2,
+			checkRetry: func(t *testing.T, req *models.GenerationRequest) {
+				require.Len(t, req.Messages, 3)
+				assert.Equal(t, "assistant", req.Messages[1].Role)
+				assert.Equal(t, "user", req.Messages[2].Role)
+				assert.Contains(t, req.Messages[2].Text, "$.population")
+			},
+		},
+		{
+			name:      "retries are exhausted",
+			retries:   1,
+			replies:   []string{`{"city": "Lisbon"}`, `not json`},
+			wantCalls: 2,
+			wantPath:  "$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+			structuredOutput := service.NewStructuredOutputService(mockGenerator, tt.retries)
+
+			calls := 0
+			mockGenerator.EXPECT().
+				GenerateChatResponse(gomock.Any(), "openai", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, platform string, req *models.GenerationRequest, callback func(string)) error {
+					assert.Equal(t, "response", req.ResponseSchema.Name)
+					if calls == 1 && tt.checkRetry != nil {
+						tt.checkRetry(t, req)
+					}
+					callback(tt.replies[calls])
+					calls++
+					return nil
+				}).Times(tt.wantCalls)
+
+			output, err := structuredOutput.Generate(context.Background(), "openai", &models.GenerationRequest{
+				Model:          "gpt-4o",
+				Messages:       []models.Message{{Role: "user", Text: "Largest city in Portugal?"}},
+				ResponseSchema: cityResponseSchema,
+			})
+			if tt.wantPath != "" {
+				var validationErr *jsonschema.ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Equal(t, tt.wantPath, validationErr.Path)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+}
+
+func TestStructuredOutputService_GenerateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	structuredOutput := service.NewStructuredOutputService(mockGenerator, 2)
+
+	mockGenerator.EXPECT().GenerateChatResponse(gomock.Any(), "gemini", gomock.Any(), gomock.Any()).
+		Return(errors.New("quota exceeded"))
+
+	_, err := structuredOutput.Generate(context.Background(), "gemini", &models.GenerationRequest{
+		Model:          "gemini-2.0-flash",
+		ResponseSchema: cityResponseSchema,
+	})
+	assert.EqualError(t, err, "quota exceeded")
+}
+
+func TestValidateResponseSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *models.ResponseSchema
+		wantErr bool
+	}{
+		{name: "valid", schema: cityResponseSchema},
+		{name: "named", schema: &models.ResponseSchema{Name: "city_info", Schema: cityResponseSchema.Schema}},
+		{name: "invalid name", schema: &models.ResponseSchema{Name: "city info", Schema: cityResponseSchema.Schema}, wantErr: true},
+		{name: "missing schema", schema: &models.ResponseSchema{Name: "city"}, wantErr: true},
+		{name: "invalid schema", schema: &models.ResponseSchema{Schema: json.RawMessage(`{"type":"map"}`)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateResponseSchema(tt.schema)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
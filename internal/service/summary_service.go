@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+const summaryPreamble = "Summary of the earlier conversation:\n"
+
+const rollingSummaryPrompt = `Update the summary of a conversation with the messages that follow it.
+Keep facts, decisions, names and open questions. Be concise and write in the
+language of the conversation. Reply with the updated summary only.
+
+Current summary:
+%s
+
+New messages:
+%s`
+
+type SummaryService struct {
+	chatRepo  repository.ChatRepositoryInterface
+	generator GeneratorInterface
+	platform  string
+	model     string
+	every     int
+	running   sync.Map
+}
+
+func NewSummaryService(chatRepo repository.ChatRepositoryInterface, generator GeneratorInterface,
+	platform string, model string, every int) *SummaryService {
+	return &SummaryService{
+		chatRepo:  chatRepo,
+		generator: generator,
+		platform:  platform,
+		model:     model,
+		every:     every,
+	}
+}
+
+// UpdateSummary folds older messages into the chat summary once at least
+// twice the configured interval is pending, always leaving the most recent
+// interval verbatim. It does nothing when summaries are disabled or another
+// update for the same chat is already running.
+func (s *SummaryService) UpdateSummary(ctx context.Context, chat *models.Chat) error {
+	if s.every <= 0 {
+		return nil
+	}
+	if _, busy := s.running.LoadOrStore(chat.ID, true); busy {
+		return nil
+	}
+	defer s.running.Delete(chat.ID)
+
+	start := summarizedCount(chat)
+	if len(chat.Messages)-start < 2*s.every {
+		return nil
+	}
+
+	end := len(chat.Messages) - s.every
+	for end > start && chat.Messages[end].Role != "user" {
+		end--
+	}
+	if end == start {
+		return nil
+	}
+
+	previous := "(none)"
+	if start > 0 {
+		previous = chat.Summary.Text
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, summaryGenTimeout)
+	defer cancel()
+
+	var sb strings.Builder
+	err := s.generator.GenerateResponse(ctx, s.platform, s.model,
+		fmt.Sprintf(rollingSummaryPrompt, previous, transcript(chat.Messages[start:end])),
+		func(chunk string) {
+			sb.WriteString(chunk)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return fmt.Errorf("model returned an empty summary")
+	}
+
+	summary := &models.Summary{
+		Text:          text,
+		LastMessageID: chat.Messages[end-1].ID,
+		MessageCount:  end,
+		Model:         s.model,
+		UpdatedAt:     time.Now(),
+	}
+	if _, err := s.chatRepo.SetSummary(ctx, chat.ID, summary); err != nil {
+		return err
+	}
+	chat.Summary = summary
+	log.Printf("Updated summary of chat %s to cover %d messages", chat.ID, end)
+	return nil
+}
+
+// ChatHistory returns the messages to send to a model for the chat, with the
+// summarized messages replaced by the summary.
+func ChatHistory(chat *models.Chat) []models.Message {
+	start := summarizedCount(chat)
+	history := make([]models.Message, 0, len(chat.Messages)-start+1)
+	if start > 0 {
+		history = append(history, models.Message{Role: "system", Text: summaryPreamble + chat.Summary.Text})
+	}
+	return append(history, chat.Messages[start:]...)
+}
+
+// summarizedCount returns how many leading messages the summary covers. A
+// summary pointing at a message that no longer exists is ignored.
+func summarizedCount(chat *models.Chat) int {
+	if chat.Summary == nil || chat.Summary.LastMessageID == "" {
+		return 0
+	}
+	for i, message := range chat.Messages {
+		if message.ID == chat.Summary.LastMessageID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func transcript(messages []models.Message) string {
+	var sb strings.Builder
+	for _, message := range messages {
+		fmt.Fprintf(&sb, "%s: %s\n\n", roleHeading(message.Role), message.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func exchanges(n int) []models.Message {
+	messages := make([]models.Message, 0, 2*n)
+	for i := 0; i < n; i++ {
+		messages = append(messages,
+			models.Message{ID: fmt.Sprintf("msg-%d", 2*i), Text: fmt.Sprintf("question %d", i), Role: "user"},
+			models.Message{ID: fmt.Sprintf("msg-%d", 2*i+1), Text: fmt.Sprintf("answer %d", i), Role: "assistant"},
+		)
+	}
+	return messages
+}
+
+func TestSummaryService_UpdateSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	summaryService := service.NewSummaryService(mockRepo, mockGenerator, "openai", "gpt-4o-mini", 4)
+
+	tests := []struct {
+		name        string
+		chat        *models.Chat
+		setup       func()
+		wantSummary *models.Summary
+		wantErr     bool
+	}{
+		{
+			name:  "not enough messages",
+			chat:  &models.Chat{ID: "chat-1", Messages: exchanges(3)},
+			setup: func() {},
+		},
+		{
+			name: "first summary keeps the latest messages",
+			chat: &models.Chat{ID: "chat-1", Messages: exchanges(4)},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+						assert.Contains(t, prompt, "question 1")
+						assert.NotContains(t, prompt, "question 2")
+						callback(" Two questions were asked. ")
+						return nil
+					})
+				mockRepo.EXPECT().SetSummary(gomock.Any(), "chat-1", gomock.Any()).Return(nil, nil)
+			},
+			wantSummary: &models.Summary{Text: "Two questions were asked.", LastMessageID: "msg-3", MessageCount: 4, Model: "gpt-4o-mini"},
+		},
+		{
+			name: "rolls the previous summary forward",
+			chat: &models.Chat{
+				ID:       "chat-1",
+				Messages: exchanges(6),
+				Summary:  &models.Summary{Text: "Earlier summary", LastMessageID: "msg-3", MessageCount: 4},
+			},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+						assert.Contains(t, prompt, "Earlier summary")
+						assert.NotContains(t, prompt, "question 1")
+						assert.Contains(t, prompt, "question 3")
+						callback("Four questions were asked.")
+						return nil
+					})
+				mockRepo.EXPECT().SetSummary(gomock.Any(), "chat-1", gomock.Any()).Return(nil, nil)
+			},
+			wantSummary: &models.Summary{Text: "Four questions were asked.", LastMessageID: "msg-7", MessageCount: 8, Model: "gpt-4o-mini"},
+		},
+		{
+			name: "generation error",
+			chat: &models.Chat{ID: "chat-1", Messages: exchanges(4)},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("provider down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			previous := tt.chat.Summary
+
+			err := summaryService.UpdateSummary(context.Background(), tt.chat)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.wantSummary == nil {
+				assert.Equal(t, previous, tt.chat.Summary)
+				return
+			}
+			require.NotNil(t, tt.chat.Summary)
+			tt.wantSummary.UpdatedAt = tt.chat.Summary.UpdatedAt
+			assert.Equal(t, tt.wantSummary, tt.chat.Summary)
+		})
+	}
+}
+
+func TestSummaryService_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	summaryService := service.NewSummaryService(mocks.NewMockChatRepositoryInterface(ctrl),
+		mocks.NewMockGeneratorInterface(ctrl), "openai", "gpt-4o-mini", 0)
+
+	err := summaryService.UpdateSummary(context.Background(), &models.Chat{ID: "chat-1", Messages: exchanges(50)})
+	assert.NoError(t, err)
+}
+
+func TestChatHistory(t *testing.T) {
+	messages := exchanges(3)
+
+	history := service.ChatHistory(&models.Chat{Messages: messages})
+	assert.Equal(t, messages, history)
+
+	history = service.ChatHistory(&models.Chat{
+		Messages: messages,
+		Summary:  &models.Summary{Text: "Earlier summary", LastMessageID: "msg-1"},
+	})
+	require.Len(t, history, 5)
+	assert.Equal(t, "system", history[0].Role)
+	assert.True(t, strings.HasSuffix(history[0].Text, "Earlier summary"))
+	assert.Equal(t, messages[2:], history[1:])
+
+	history = service.ChatHistory(&models.Chat{
+		Messages: messages,
+		Summary:  &models.Summary{Text: "Stale summary", LastMessageID: "gone"},
+	})
+	assert.Equal(t, messages, history)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+type TemplateService struct {
+	templateRepo repository.TemplateRepositoryInterface
+}
+
+func NewTemplateService(templateRepo repository.TemplateRepositoryInterface) *TemplateService {
+	return &TemplateService{
+		templateRepo: templateRepo,
+	}
+}
+
+func (s *TemplateService) CreateTemplate(ctx context.Context, tmpl *models.PromptTemplate) error {
+	tmpl.Name = strings.TrimSpace(tmpl.Name)
+	if tmpl.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if tmpl.User == "" {
+		return fmt.Errorf("user ID is required")
+	}
+
+	variables, err := templateVariables(tmpl.Body)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tmpl.ID = idgen.GenerateWithPrefix("tmpl")
+	tmpl.Variables = variables
+	tmpl.Version = 1
+	tmpl.CreatedAt = now
+	tmpl.UpdatedAt = now
+
+	if err := s.templateRepo.CreateTemplate(ctx, tmpl); err != nil {
+		return err
+	}
+	return s.templateRepo.CreateTemplateVersion(ctx, newTemplateVersion(tmpl))
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, id string) (*models.PromptTemplate, error) {
+	if id == "" {
+		return nil, fmt.Errorf("template ID is required")
+	}
+
+	return s.templateRepo.GetTemplate(ctx, id)
+}
+
+func (s *TemplateService) ListTemplates(ctx context.Context, userID string) ([]*models.PromptTemplate, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	return s.templateRepo.ListTemplates(ctx, userID)
+}
+
+// UpdateTemplate applies the update and, when the body changes, records it as
+// a new version. Earlier versions stay available for rendering.
+func (s *TemplateService) UpdateTemplate(ctx context.Context, id string, update models.PromptTemplateUpdate) (*models.PromptTemplate, error) {
+	if id == "" {
+		return nil, fmt.Errorf("template ID is required")
+	}
+
+	tmpl, err := s.templateRepo.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("template name is required")
+		}
+		tmpl.Name = name
+	}
+	if update.Description != nil {
+		tmpl.Description = *update.Description
+	}
+	if update.Shared != nil {
+		tmpl.Shared = *update.Shared
+	}
+
+	newVersion := update.Body != nil && *update.Body != tmpl.Body
+	if newVersion {
+		variables, err := templateVariables(*update.Body)
+		if err != nil {
+			return nil, err
+		}
+		tmpl.Body = *update.Body
+		tmpl.Variables = variables
+		tmpl.Version++
+	}
+
+	tmpl.UpdatedAt = time.Now()
+	if err := s.templateRepo.UpdateTemplate(ctx, tmpl); err != nil {
+		return nil, err
+	}
+	if newVersion {
+		if err := s.templateRepo.CreateTemplateVersion(ctx, newTemplateVersion(tmpl)); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("template ID is required")
+	}
+
+	return s.templateRepo.DeleteTemplate(ctx, id)
+}
+
+func (s *TemplateService) ListVersions(ctx context.Context, id string) ([]*models.PromptTemplateVersion, error) {
+	if id == "" {
+		return nil, fmt.Errorf("template ID is required")
+	}
+
+	return s.templateRepo.ListTemplateVersions(ctx, id)
+}
+
+// RenderTemplate renders the given version of the template, or the current
+// one when version is zero. Every variable used by the template must be
+// provided.
+func (s *TemplateService) RenderTemplate(ctx context.Context, tmpl *models.PromptTemplate, version int,
+	variables map[string]any) (string, error) {
+	body, required := tmpl.Body, tmpl.Variables
+	if version != 0 && version != tmpl.Version {
+		old, err := s.templateRepo.GetTemplateVersion(ctx, tmpl.ID, version)
+		if err != nil {
+			return "", err
+		}
+		body, required = old.Body, old.Variables
+	}
+
+	var missing []string
+	for _, name := range required {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+
+	parsed, err := template.New(tmpl.ID).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := parsed.Execute(&sb, variables); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+func newTemplateVersion(tmpl *models.PromptTemplate) *models.PromptTemplateVersion {
+	return &models.PromptTemplateVersion{
+		TemplateID: tmpl.ID,
+		Version:    tmpl.Version,
+		Body:       tmpl.Body,
+		Variables:  tmpl.Variables,
+		CreatedAt:  tmpl.UpdatedAt,
+	}
+}
+
+// templateVariables parses the body and returns the top-level variables it
+// references, such as .diff or $.diff. Fields read inside range and with
+// blocks belong to the nested value and are not variables of their own.
+func templateVariables(body string) ([]string, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("template body is required")
+	}
+
+	parsed, err := template.New("").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	found := make(map[string]bool)
+	var walk func(node parse.Node, root bool)
+	walk = func(node parse.Node, root bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, root)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, root)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, root)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, root)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, root)
+		case *parse.FieldNode:
+			if root {
+				found[n.Ident[0]] = true
+			}
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				found[n.Ident[1]] = true
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, root)
+			walk(n.List, root)
+			walk(n.ElseList, root)
+		case *parse.RangeNode:
+			walk(n.Pipe, root)
+			walk(n.List, false)
+			walk(n.ElseList, root)
+		case *parse.WithNode:
+			walk(n.Pipe, root)
+			walk(n.List, false)
+			walk(n.ElseList, root)
+		case *parse.TemplateNode:
+			walk(n.Pipe, root)
+		}
+	}
+	walk(parsed.Tree.Root, true)
+
+	variables := make([]string, 0, len(found))
+	for name := range found {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+	return variables, nil
+}
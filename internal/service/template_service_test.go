@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTemplateRepositoryInterface(ctrl)
+	templateService := service.NewTemplateService(mockRepo)
+
+	tests := []struct {
+		name          string
+		tmpl          *models.PromptTemplate
+		setup         func()
+		wantVariables []string
+		wantErr       bool
+	}{
+		{
+			name: "collects top-level variables",
+			tmpl: &models.PromptTemplate{
+				User: "user-123",
+				Name: "Review diff",
+				Body: "Review this {{.language}} diff:\n{{.diff}}\n{{range .focus}}- {{.}} {{.weight}}\n{{end}}{{if $.strict}}Be strict.{{end}}",
+			},
+			setup: func() {
+				mockRepo.EXPECT().CreateTemplate(gomock.Any(), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().
+					CreateTemplateVersion(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, version *models.PromptTemplateVersion) error {
+						assert.Equal(t, 1, version.Version)
+						return nil
+					})
+			},
+			wantVariables: []string{"diff", "focus", "language", "strict"},
+			wantErr:       false,
+		},
+		{
+			name:    "missing name",
+			tmpl:    &models.PromptTemplate{User: "user-123", Body: "{{.diff}}"},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "invalid syntax",
+			tmpl:    &models.PromptTemplate{User: "user-123", Name: "Broken", Body: "{{.diff"},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "empty body",
+			tmpl:    &models.PromptTemplate{User: "user-123", Name: "Empty", Body: "  "},
+			setup:   func() {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := templateService.CreateTemplate(context.Background(), tt.tmpl)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, tt.tmpl.ID)
+			assert.Equal(t, 1, tt.tmpl.Version)
+			assert.Equal(t, tt.wantVariables, tt.tmpl.Variables)
+		})
+	}
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTemplateRepositoryInterface(ctrl)
+	templateService := service.NewTemplateService(mockRepo)
+
+	current := func() *models.PromptTemplate {
+		return &models.PromptTemplate{
+			ID:        "tmpl-1",
+			Name:      "Summarize incident",
+			Body:      "Summarize {{.incident}}",
+			Variables: []string{"incident"},
+			Version:   2,
+		}
+	}
+	newName := "Incident summary"
+	newBody := "Summarize {{.incident}} for {{.audience}}"
+
+	tests := []struct {
+		name        string
+		update      models.PromptTemplateUpdate
+		setup       func()
+		wantVersion int
+		wantErr     bool
+	}{
+		{
+			name:   "rename keeps the version",
+			update: models.PromptTemplateUpdate{Name: &newName},
+			setup: func() {
+				mockRepo.EXPECT().GetTemplate(gomock.Any(), "tmpl-1").Return(current(), nil)
+				mockRepo.EXPECT().UpdateTemplate(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantVersion: 2,
+		},
+		{
+			name:   "new body creates a version",
+			update: models.PromptTemplateUpdate{Body: &newBody},
+			setup: func() {
+				mockRepo.EXPECT().GetTemplate(gomock.Any(), "tmpl-1").Return(current(), nil)
+				mockRepo.EXPECT().UpdateTemplate(gomock.Any(), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().
+					CreateTemplateVersion(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, version *models.PromptTemplateVersion) error {
+						assert.Equal(t, 3, version.Version)
+						assert.Equal(t, []string{"audience", "incident"}, version.Variables)
+						return nil
+					})
+			},
+			wantVersion: 3,
+		},
+		{
+			name:   "template not found",
+			update: models.PromptTemplateUpdate{Name: &newName},
+			setup: func() {
+				mockRepo.EXPECT().GetTemplate(gomock.Any(), "tmpl-1").Return(nil, fmt.Errorf("template not found"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			tmpl, err := templateService.UpdateTemplate(context.Background(), "tmpl-1", tt.update)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, tmpl.Version)
+		})
+	}
+}
+
+func TestTemplateService_RenderTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTemplateRepositoryInterface(ctrl)
+	templateService := service.NewTemplateService(mockRepo)
+
+	tmpl := &models.PromptTemplate{
+		ID:        "tmpl-1",
+		Body:      "Summarize {{.incident}} for {{.audience}}",
+		Variables: []string{"audience", "incident"},
+		Version:   2,
+	}
+
+	tests := []struct {
+		name      string
+		version   int
+		variables map[string]any
+		setup     func()
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "current version",
+			variables: map[string]any{"incident": "the outage", "audience": "executives"},
+			setup:     func() {},
+			want:      "Summarize the outage for executives",
+		},
+		{
+			name:      "earlier version",
+			version:   1,
+			variables: map[string]any{"incident": "the outage"},
+			setup: func() {
+				mockRepo.EXPECT().
+					GetTemplateVersion(gomock.Any(), "tmpl-1", 1).
+					Return(&models.PromptTemplateVersion{Body: "Summarize {{.incident}}", Variables: []string{"incident"}}, nil)
+			},
+			want: "Summarize the outage",
+		},
+		{
+			name:      "missing variable",
+			variables: map[string]any{"incident": "the outage"},
+			setup:     func() {},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown version",
+			version:   7,
+			variables: map[string]any{},
+			setup: func() {
+				mockRepo.EXPECT().
+					GetTemplateVersion(gomock.Any(), "tmpl-1", 7).
+					Return(nil, fmt.Errorf("template version not found"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := templateService.RenderTemplate(context.Background(), tmpl, tt.version, tt.variables)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const (
+	maxTitleLength  = 60
+	titleGenTimeout = 15 * time.Second
+	// defaultTitle names chats whose first prompt has no text, such as one
+	// that only sends attachments.
+	defaultTitle = "New chat"
+)
+
+const titlePrompt = `Write a short title (at most 6 words) for the conversation below.
+Reply with the title only, without quotes or trailing punctuation.
+
+User: %s
+
+Assistant: %s`
+
+type TitleService struct {
+	chatService ChatServiceInterface
+	generator   GeneratorInterface
+	platform    string
+	model       string
+}
+
+func NewTitleService(chatService ChatServiceInterface, generator GeneratorInterface,
+	platform string, model string) *TitleService {
+	return &TitleService{
+		chatService: chatService,
+		generator:   generator,
+		platform:    platform,
+		model:       model,
+	}
+}
+
+func (s *TitleService) GenerateTitle(ctx context.Context, chat *models.Chat) (string, error) {
+	prompt, answer, ok := firstExchange(chat.Messages)
+	if !ok {
+		return "", fmt.Errorf("chat has no user message to derive a title from")
+	}
+
+	title, err := s.askForTitle(ctx, prompt, answer)
+	if err != nil {
+		log.Printf("Title generation failed for chat %s, falling back to prompt: %v", chat.ID, err)
+		title = truncateTitle(prompt)
+		if title == "" {
+			title = defaultTitle
+		}
+	}
+
+	if err := s.chatService.UpdateChat(ctx, &models.Chat{ID: chat.ID, Title: title}); err != nil {
+		return "", fmt.Errorf("failed to update chat title: %w", err)
+	}
+	chat.Title = title
+	return title, nil
+}
+
+func (s *TitleService) askForTitle(ctx context.Context, prompt string, answer string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, titleGenTimeout)
+	defer cancel()
+
+	var sb strings.Builder
+	err := s.generator.GenerateResponse(ctx, s.platform, s.model,
+		fmt.Sprintf(titlePrompt, prompt, answer), func(chunk string) {
+			sb.WriteString(chunk)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	title := cleanTitle(sb.String())
+	if title == "" {
+		return "", fmt.Errorf("model returned an empty title")
+	}
+	return title, nil
+}
+
+// firstExchange returns the first user message and the reply to it. The
+// prompt may be blank when the user only sent attachments.
+func firstExchange(messages []models.Message) (string, string, bool) {
+	var prompt, answer string
+	found := false
+	for _, message := range messages {
+		switch {
+		case message.Role == "user" && !found:
+			prompt = message.Text
+			found = true
+		case message.Role == "assistant" && found && answer == "":
+			answer = message.Text
+		}
+	}
+	return prompt, answer, found
+}
+
+func cleanTitle(raw string) string {
+	title := strings.TrimSpace(raw)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = title[:i]
+	}
+	title = strings.Trim(title, " \t\"'`*#.")
+	return truncateTitle(title)
+}
+
+func truncateTitle(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxTitleLength {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxTitleLength-3])) + "..."
+}
@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTitleService_GenerateTitle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatService := mocks.NewMockChatServiceInterface(ctrl)
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	titleService := service.NewTitleService(mockChatService, mockGenerator, "openai", "gpt-4o-mini")
+
+	longPrompt := strings.Repeat("explain goroutines ", 10)
+
+	tests := []struct {
+		name      string
+		messages  []models.Message
+		setup     func()
+		wantTitle string
+		wantErr   bool
+	}{
+		{
+			name: "model title",
+			messages: []models.Message{
+				{Text: "How do channels work?", Role: "user"},
+				{Text: "Channels are typed conduits...", Role: "assistant"},
+			},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+						callback("\"Go Channels")
+						callback(" Explained\".\n")
+						return nil
+					})
+				mockChatService.EXPECT().
+					UpdateChat(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantTitle: "Go Channels Explained",
+		},
+		{
+			name: "falls back to truncated prompt",
+			messages: []models.Message{
+				{Text: longPrompt, Role: "user"},
+				{Text: "Sure.", Role: "assistant"},
+			},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("provider down"))
+				mockChatService.EXPECT().
+					UpdateChat(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantTitle: strings.TrimSpace(longPrompt[:57]) + "...",
+		},
+		{
+			name: "blank prompt falls back to a placeholder",
+			messages: []models.Message{
+				{Text: "  ", Role: "user", Attachments: []models.Attachment{{ID: "att1"}}},
+				{Text: "This is a bar chart of revenue.", Role: "assistant"},
+			},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), "openai", "gpt-4o-mini", gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("provider down"))
+				mockChatService.EXPECT().
+					UpdateChat(gomock.Any(), &models.Chat{ID: "chat-123", Title: "New chat"}).
+					Return(nil)
+			},
+			wantTitle: "New chat",
+		},
+		{
+			name:     "no user message",
+			messages: []models.Message{},
+			setup:    func() {},
+			wantErr:  true,
+		},
+		{
+			name: "update error",
+			messages: []models.Message{
+				{Text: "Hello", Role: "user"},
+			},
+			setup: func() {
+				mockGenerator.EXPECT().
+					GenerateResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, platform, model, prompt string, callback func(string)) error {
+						callback("Greeting")
+						return nil
+					})
+				mockChatService.EXPECT().
+					UpdateChat(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			chat := &models.Chat{ID: "chat-123", Messages: tt.messages}
+			title, err := titleService.GenerateTitle(context.Background(), chat)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, title)
+			assert.Equal(t, tt.wantTitle, chat.Title)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const maxTools = 128
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ValidateTools checks tool definitions against the rules every provider
+// shares: unique names of up to 64 letters, digits, underscores or dashes,
+// and parameters that are a JSON object.
+func ValidateTools(tools []models.ToolDefinition) error {
+	if len(tools) > maxTools {
+		return fmt.Errorf("at most %d tools can be defined", maxTools)
+	}
+
+	seen := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if !toolNamePattern.MatchString(tool.Name) {
+			return fmt.Errorf("invalid tool name %q", tool.Name)
+		}
+		if seen[tool.Name] {
+			return fmt.Errorf("tool %s is defined more than once", tool.Name)
+		}
+		seen[tool.Name] = true
+
+		if len(tool.Parameters) == 0 {
+			continue
+		}
+		var parameters map[string]any
+		if err := json.Unmarshal(tool.Parameters, &parameters); err != nil || parameters == nil {
+			return fmt.Errorf("parameters of tool %s must be a JSON Schema object", tool.Name)
+		}
+	}
+	return nil
+}
+
+// PendingToolCalls returns the tool calls of the chat's last assistant
+// message that are still waiting for results. Calls answered by the tool
+// messages after it, such as ones the server ran, are not pending.
+func PendingToolCalls(chat *models.Chat) []models.ToolCall {
+	answered := make(map[string]bool)
+	for i := len(chat.Messages) - 1; i >= 0; i-- {
+		message := chat.Messages[i]
+		switch message.Role {
+		case "tool":
+			answered[message.ToolCallID] = true
+			continue
+		case "assistant":
+			var pending []models.ToolCall
+			for _, call := range message.ToolCalls {
+				if !answered[call.ID] {
+					pending = append(pending, call)
+				}
+			}
+			return pending
+		}
+		return nil
+	}
+	return nil
+}
+
+// ToolResultMessages turns the results a client posted into tool messages.
+// Every pending call must be answered exactly once.
+func ToolResultMessages(pending []models.ToolCall, results []models.ToolResult,
+	userID string) ([]models.Message, error) {
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("the chat has no pending tool calls")
+	}
+
+	calls := make(map[string]models.ToolCall, len(pending))
+	for _, call := range pending {
+		calls[call.ID] = call
+	}
+
+	messages := make([]models.Message, 0, len(results))
+	for _, result := range results {
+		call, ok := calls[result.ToolCallID]
+		if !ok {
+			return nil, fmt.Errorf("unknown or repeated tool call %q", result.ToolCallID)
+		}
+		delete(calls, result.ToolCallID)
+		messages = append(messages, models.Message{
+			Text:       result.Content,
+			Role:       "tool",
+			UserID:     userID,
+			ToolCallID: call.ID,
+			ToolName:   call.Name,
+		})
+	}
+	for _, call := range pending {
+		if _, missing := calls[call.ID]; missing {
+			return nil, fmt.Errorf("missing result for tool call %s", call.ID)
+		}
+	}
+	return messages, nil
+}
+
+// PairToolCalls returns a copy of messages in which every tool call is
+// followed by its result and every result follows its call, as providers
+// require. Calls the client never answered and orphaned results, for example
+// ones whose call was folded into the summary, are left out.
+func PairToolCalls(messages []models.Message) []models.Message {
+	answered := make(map[string]bool)
+	for _, message := range messages {
+		if message.Role == "tool" {
+			answered[message.ToolCallID] = true
+		}
+	}
+
+	paired := make([]models.Message, 0, len(messages))
+	called := make(map[string]bool)
+	for _, message := range messages {
+		switch {
+		case message.Role == "tool":
+			if !called[message.ToolCallID] {
+				continue
+			}
+		case len(message.ToolCalls) > 0:
+			var calls []models.ToolCall
+			for _, call := range message.ToolCalls {
+				if answered[call.ID] {
+					calls = append(calls, call)
+					called[call.ID] = true
+				}
+			}
+			message.ToolCalls = calls
+			if len(calls) == 0 && message.Text == "" {
+				continue
+			}
+		}
+		paired = append(paired, message)
+	}
+	return paired
+}
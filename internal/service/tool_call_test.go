@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTools(t *testing.T) {
+	weather := models.ToolDefinition{
+		Name:        "get_weather",
+		Description: "Current weather for a city",
+		Parameters:  json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`),
+	}
+
+	tests := []struct {
+		name    string
+		tools   []models.ToolDefinition
+		wantErr bool
+	}{
+		{name: "no tools", tools: nil},
+		{name: "valid tool", tools: []models.ToolDefinition{weather}},
+		{name: "tool without parameters", tools: []models.ToolDefinition{{Name: "now"}}},
+		{name: "invalid name", tools: []models.ToolDefinition{{Name: "get weather"}}, wantErr: true},
+		{name: "name too long", tools: []models.ToolDefinition{{Name: strings.Repeat("a", 65)}}, wantErr: true},
+		{name: "duplicate name", tools: []models.ToolDefinition{weather, weather}, wantErr: true},
+		{
+			name:    "parameters not an object",
+			tools:   []models.ToolDefinition{{Name: "now", Parameters: json.RawMessage(`["city"]`)}},
+			wantErr: true,
+		},
+		{
+			name:    "parameters null",
+			tools:   []models.ToolDefinition{{Name: "now", Parameters: json.RawMessage(`null`)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateTools(tt.tools)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPendingToolCalls(t *testing.T) {
+	calls := []models.ToolCall{{ID: "call-1", Name: "get_weather", Arguments: `{"city":"Lisbon"}`}}
+
+	assert.Nil(t, service.PendingToolCalls(&models.Chat{}))
+	assert.Equal(t, calls, service.PendingToolCalls(&models.Chat{Messages: []models.Message{
+		{Role: "user", Text: "weather?"},
+		{Role: "assistant", ToolCalls: calls},
+	}}))
+	assert.Nil(t, service.PendingToolCalls(&models.Chat{Messages: []models.Message{
+		{Role: "assistant", ToolCalls: calls},
+		{Role: "tool", ToolCallID: "call-1", Text: "sunny"},
+	}}))
+	assert.Nil(t, service.PendingToolCalls(&models.Chat{Messages: []models.Message{
+		{Role: "assistant", ToolCalls: calls},
+		{Role: "user", Text: "never mind"},
+	}}))
+	assert.Equal(t, calls, service.PendingToolCalls(&models.Chat{Messages: []models.Message{
+		{Role: "assistant", ToolCalls: append([]models.ToolCall{{ID: "call-0", Name: "calculator"}}, calls...)},
+		{Role: "tool", ToolCallID: "call-0", Text: "4"},
+	}}), "calls the server answered are not pending")
+}
+
+func TestToolResultMessages(t *testing.T) {
+	pending := []models.ToolCall{
+		{ID: "call-1", Name: "get_weather"},
+		{ID: "call-2", Name: "get_time"},
+	}
+
+	tests := []struct {
+		name    string
+		pending []models.ToolCall
+		results []models.ToolResult
+		wantErr bool
+	}{
+		{
+			name:    "all answered",
+			pending: pending,
+			results: []models.ToolResult{
+				{ToolCallID: "call-2", Content: "10:00"},
+				{ToolCallID: "call-1", Content: "sunny"},
+			},
+		},
+		{
+			name:    "nothing pending",
+			results: []models.ToolResult{{ToolCallID: "call-1", Content: "sunny"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing result",
+			pending: pending,
+			results: []models.ToolResult{{ToolCallID: "call-1", Content: "sunny"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown call",
+			pending: pending,
+			results: []models.ToolResult{
+				{ToolCallID: "call-1", Content: "sunny"},
+				{ToolCallID: "call-2", Content: "10:00"},
+				{ToolCallID: "call-3", Content: "?"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "answered twice",
+			pending: pending,
+			results: []models.ToolResult{
+				{ToolCallID: "call-1", Content: "sunny"},
+				{ToolCallID: "call-1", Content: "rainy"},
+				{ToolCallID: "call-2", Content: "10:00"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := service.ToolResultMessages(tt.pending, tt.results, "user-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, messages, 2)
+			assert.Equal(t, models.Message{
+				Text: "10:00", Role: "tool", UserID: "user-123", ToolCallID: "call-2", ToolName: "get_time",
+			}, messages[0])
+			assert.Equal(t, "get_weather", messages[1].ToolName)
+		})
+	}
+}
+
+func TestPairToolCalls(t *testing.T) {
+	messages := []models.Message{
+		{Role: "tool", ToolCallID: "call-0", Text: "orphaned"},
+		{Role: "user", Text: "weather and time?"},
+		{Role: "assistant", ToolCalls: []models.ToolCall{{ID: "call-1"}, {ID: "call-2"}}},
+		{Role: "tool", ToolCallID: "call-1", Text: "sunny"},
+		{Role: "assistant", Text: "It is sunny."},
+		{Role: "user", Text: "and tomorrow?"},
+		{Role: "assistant", ToolCalls: []models.ToolCall{{ID: "call-3"}}},
+		{Role: "user", Text: "never mind"},
+	}
+
+	paired := service.PairToolCalls(messages)
+
+	require.Len(t, paired, 6)
+	assert.Equal(t, "weather and time?", paired[0].Text)
+	assert.Equal(t, []models.ToolCall{{ID: "call-1"}}, paired[1].ToolCalls)
+	assert.Equal(t, "call-1", paired[2].ToolCallID)
+	assert.Equal(t, "and tomorrow?", paired[4].Text)
+	assert.Equal(t, "never mind", paired[5].Text)
+	assert.Len(t, messages[2].ToolCalls, 2, "input messages are not modified")
+}
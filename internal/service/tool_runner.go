@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+	"github.com/lutefd/ai-router-go/internal/tools"
+	"github.com/lutefd/ai-router-go/pkg/idgen"
+)
+
+const toolRunListLimit = 100
+
+// ToolScope is who a generation runs server-side tools for, and which of
+// them the model may call. Shared is set when other users read the chat the
+// results are stored in; tools exposing the user's own data are then withheld.
+type ToolScope struct {
+	UserID      string
+	ChatID      string
+	Shared      bool
+	ServerTools []models.ToolDefinition
+}
+
+type ToolRunner struct {
+	registry    *tools.Registry
+	generator   GeneratorInterface
+	toolRunRepo repository.ToolRunRepositoryInterface
+	maxSteps    int
+}
+
+func NewToolRunner(registry *tools.Registry, generator GeneratorInterface,
+	toolRunRepo repository.ToolRunRepositoryInterface, maxSteps int) *ToolRunner {
+	return &ToolRunner{
+		registry:    registry,
+		generator:   generator,
+		toolRunRepo: toolRunRepo,
+		maxSteps:    maxSteps,
+	}
+}
+
+func (r *ToolRunner) Tools() []models.ToolDefinition {
+	return r.registry.Definitions()
+}
+
+// ServerTools returns the definitions of the named server-side tools.
+func (r *ToolRunner) ServerTools(names []string) ([]models.ToolDefinition, error) {
+	definitions := make([]models.ToolDefinition, 0, len(names))
+	available := r.registry.Definitions()
+	for _, name := range names {
+		found := false
+		for _, definition := range available {
+			if definition.Name == name {
+				definitions = append(definitions, definition)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown server tool %q", name)
+		}
+	}
+	return definitions, nil
+}
+
+// Generate runs the model and executes the server-side tools it calls,
+// feeding their results back until it answers without calling any. Calls to
+// the client's tools end the loop and are passed to req.OnToolCall. After
+// maxSteps rounds the server tools are withdrawn so the model has to answer.
+// The assistant and tool messages produced along the way are returned in
+// order, also when generation fails part way.
+func (r *ToolRunner) Generate(ctx context.Context, platform string, req *models.GenerationRequest,
+	scope ToolScope, callback func(string), onRun func(*models.ToolRun)) ([]models.Message, error) {
+	if scope.Shared {
+		scope.ServerTools = r.sharedTools(scope.ServerTools)
+	}
+	server := make(map[string]bool, len(scope.ServerTools))
+	for _, definition := range scope.ServerTools {
+		server[definition.Name] = true
+	}
+
+	messages := append([]models.Message(nil), req.Messages...)
+	var produced []models.Message
+	for step := 1; ; step++ {
+		final := step > r.maxSteps
+		available := req.Tools
+		if !final && len(scope.ServerTools) > 0 {
+			available = append(append([]models.ToolDefinition(nil), req.Tools...), scope.ServerTools...)
+		}
+
+		var text strings.Builder
+		var calls []models.ToolCall
+		err := r.generator.GenerateChatResponse(ctx, platform, &models.GenerationRequest{
+			Model:         req.Model,
+			Messages:      messages,
+			Sampling:      req.Sampling,
+			Tools:         available,
+			CachedContent: req.CachedContent,
+			OnUsage:       req.OnUsage,
+			OnToolCall: func(call models.ToolCall) {
+				if final && server[call.Name] {
+					return
+				}
+				calls = append(calls, call)
+			},
+		}, func(chunk string) {
+			text.WriteString(chunk)
+			callback(chunk)
+		})
+		if err != nil {
+			return produced, err
+		}
+
+		reply := models.Message{Text: text.String(), Role: "assistant", ToolCalls: calls}
+		produced = append(produced, reply)
+		messages = append(messages, reply)
+
+		var results []models.Message
+		clientCalls := false
+		for _, call := range calls {
+			if !server[call.Name] {
+				clientCalls = true
+				if req.OnToolCall != nil {
+					req.OnToolCall(call)
+				}
+				continue
+			}
+			results = append(results, r.runTool(ctx, step, call, scope, onRun))
+		}
+		produced = append(produced, results...)
+		messages = append(messages, results...)
+
+		if len(results) == 0 || clientCalls {
+			return produced, nil
+		}
+	}
+}
+
+// runTool executes one call and records it. Failures are reported to the
+// model as the result so it can recover.
+// sharedTools drops the tools whose results must not reach other users.
+func (r *ToolRunner) sharedTools(definitions []models.ToolDefinition) []models.ToolDefinition {
+	shared := make([]models.ToolDefinition, 0, len(definitions))
+	for _, definition := range definitions {
+		if !r.registry.Private(definition.Name) {
+			shared = append(shared, definition)
+		}
+	}
+	return shared
+}
+
+func (r *ToolRunner) runTool(ctx context.Context, step int, call models.ToolCall, scope ToolScope,
+	onRun func(*models.ToolRun)) models.Message {
+	run := &models.ToolRun{
+		ID:         idgen.GenerateWithPrefix("run"),
+		User:       scope.UserID,
+		ChatID:     scope.ChatID,
+		Step:       step,
+		ToolCallID: call.ID,
+		Name:       call.Name,
+		Arguments:  call.Arguments,
+		StartedAt:  time.Now(),
+	}
+
+	result, err := r.registry.Execute(ctx, call.Name, tools.Invocation{
+		UserID:    scope.UserID,
+		ChatID:    scope.ChatID,
+		Arguments: json.RawMessage(call.Arguments),
+	})
+	run.DurationMS = time.Since(run.StartedAt).Milliseconds()
+	content := result
+	if err != nil {
+		run.Error = err.Error()
+		content = "error: " + err.Error()
+	} else {
+		run.Result = result
+	}
+
+	if err := r.toolRunRepo.CreateToolRun(context.WithoutCancel(ctx), run); err != nil {
+		log.Printf("Error recording tool run %s: %v", run.ID, err)
+	}
+	if onRun != nil {
+		onRun(run)
+	}
+
+	return models.Message{
+		Text:       content,
+		Role:       "tool",
+		ToolCallID: call.ID,
+		ToolName:   call.Name,
+	}
+}
+
+func (r *ToolRunner) ListToolRuns(ctx context.Context, userID string, chatID string) ([]*models.ToolRun, error) {
+	return r.toolRunRepo.ListToolRuns(ctx, userID, chatID, toolRunListLimit)
+}
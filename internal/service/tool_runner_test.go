@@ -0,0 +1,248 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/service"
+	"github.com/lutefd/ai-router-go/internal/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type scriptedTurn struct {
+	text  string
+	calls []models.ToolCall
+}
+
+// scriptedGenerator answers each GenerateChatResponse call with the next
+// turn, recording the requests it was given.
+func scriptedGenerator(t *testing.T, generator *mocks.MockGeneratorInterface, turns []scriptedTurn,
+	requests *[]*models.GenerationRequest) {
+	t.Helper()
+	for _, turn := range turns {
+		turn := turn
+		generator.EXPECT().
+			GenerateChatResponse(gomock.Any(), "openai", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, platform string, req *models.GenerationRequest, callback func(string)) error {
+				*requests = append(*requests, req)
+				if turn.text != "" {
+					callback(turn.text)
+				}
+				for _, call := range turn.calls {
+					req.OnToolCall(call)
+				}
+				return nil
+			})
+	}
+}
+
+func TestToolRunner_Generate(t *testing.T) {
+	registry, err := tools.NewRegistry(tools.NewCalculator())
+	require.NoError(t, err)
+	calculator := registry.Definitions()
+	clientTool := models.ToolDefinition{Name: "get_location"}
+
+	tests := []struct {
+		name         string
+		maxSteps     int
+		clientTools  []models.ToolDefinition
+		turns        []scriptedTurn
+		wantRoles    []string
+		wantResults  []string
+		wantForward  []string
+		wantRuns     int
+		checkRequest func(t *testing.T, requests []*models.GenerationRequest)
+	}{
+		{
+			name:      "answer without tools",
+			maxSteps:  5,
+			turns:     []scriptedTurn{{text: "Hello"}},
+			wantRoles: []string{"assistant"},
+		},
+		{
+			name:     "server tool result is fed back",
+			maxSteps: 5,
+			turns: []scriptedTurn{
+				{calls: []models.ToolCall{{ID: "call-1", Name: "calculator", Arguments: `{"expression":"2+2"}`}}},
+				{text: "It is 4."},
+			},
+			wantRoles:   []string{"assistant", "tool", "assistant"},
+			wantResults: []string{"4"},
+			wantRuns:    1,
+			checkRequest: func(t *testing.T, requests []*models.GenerationRequest) {
+				last := requests[1].Messages[len(requests[1].Messages)-1]
+				assert.Equal(t, "tool", last.Role)
+				assert.Equal(t, "call-1", last.ToolCallID)
+			},
+		},
+		{
+			name:     "tool errors are reported to the model",
+			maxSteps: 5,
+			turns: []scriptedTurn{
+				{calls: []models.ToolCall{{ID: "call-1", Name: "calculator", Arguments: `{"expression":"1/0"}`}}},
+				{text: "Cannot divide by zero."},
+			},
+			wantRoles:   []string{"assistant", "tool", "assistant"},
+			wantResults: []string{"error: division by zero"},
+			wantRuns:    1,
+		},
+		{
+			name:        "client tool calls end the loop",
+			maxSteps:    5,
+			clientTools: []models.ToolDefinition{clientTool},
+			turns: []scriptedTurn{
+				{calls: []models.ToolCall{
+					{ID: "call-1", Name: "calculator", Arguments: `{"expression":"1+1"}`},
+					{ID: "call-2", Name: "get_location", Arguments: `{}`},
+				}},
+			},
+			wantRoles:   []string{"assistant", "tool"},
+			wantResults: []string{"2"},
+			wantForward: []string{"call-2"},
+			wantRuns:    1,
+			checkRequest: func(t *testing.T, requests []*models.GenerationRequest) {
+				assert.Len(t, requests[0].Tools, 2)
+			},
+		},
+		{
+			name:     "server tools are withdrawn after the last step",
+			maxSteps: 1,
+			turns: []scriptedTurn{
+				{calls: []models.ToolCall{{ID: "call-1", Name: "calculator", Arguments: `{"expression":"1+1"}`}}},
+				{text: "Done.", calls: []models.ToolCall{{ID: "call-2", Name: "calculator", Arguments: `{"expression":"2+2"}`}}},
+			},
+			wantRoles:   []string{"assistant", "tool", "assistant"},
+			wantResults: []string{"2"},
+			wantRuns:    1,
+			checkRequest: func(t *testing.T, requests []*models.GenerationRequest) {
+				assert.Len(t, requests[0].Tools, 1)
+				assert.Empty(t, requests[1].Tools)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+			mockToolRunRepo := mocks.NewMockToolRunRepositoryInterface(ctrl)
+			runner := service.NewToolRunner(registry, mockGenerator, mockToolRunRepo, tt.maxSteps)
+
+			var requests []*models.GenerationRequest
+			scriptedGenerator(t, mockGenerator, tt.turns, &requests)
+			mockToolRunRepo.EXPECT().CreateToolRun(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, run *models.ToolRun) error {
+					assert.Equal(t, "user-123", run.User)
+					assert.Equal(t, "chat-1", run.ChatID)
+					return nil
+				}).Times(tt.wantRuns)
+
+			var forwarded []string
+			var runs []*models.ToolRun
+			produced, err := runner.Generate(context.Background(), "openai", &models.GenerationRequest{
+				Model:    "gpt-4o",
+				Messages: []models.Message{{Role: "user", Text: "Hi"}},
+				Tools:    tt.clientTools,
+				OnToolCall: func(call models.ToolCall) {
+					forwarded = append(forwarded, call.ID)
+				},
+			}, service.ToolScope{UserID: "user-123", ChatID: "chat-1", ServerTools: calculator},
+				func(string) {}, func(run *models.ToolRun) { runs = append(runs, run) })
+			require.NoError(t, err)
+
+			var roles, results []string
+			for _, message := range produced {
+				roles = append(roles, message.Role)
+				if message.Role == "tool" {
+					results = append(results, message.Text)
+				}
+			}
+			assert.Equal(t, tt.wantRoles, roles)
+			assert.Equal(t, tt.wantResults, results)
+			assert.Equal(t, tt.wantForward, forwarded)
+			assert.Len(t, runs, tt.wantRuns)
+			if tt.checkRequest != nil {
+				tt.checkRequest(t, requests)
+			}
+		})
+	}
+}
+
+func TestToolRunner_GenerateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry, err := tools.NewRegistry(tools.NewCalculator())
+	require.NoError(t, err)
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	mockToolRunRepo := mocks.NewMockToolRunRepositoryInterface(ctrl)
+	runner := service.NewToolRunner(registry, mockGenerator, mockToolRunRepo, 5)
+
+	mockGenerator.EXPECT().GenerateChatResponse(gomock.Any(), "openai", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, platform string, req *models.GenerationRequest, callback func(string)) error {
+			req.OnToolCall(models.ToolCall{ID: "call-1", Name: "calculator", Arguments: `{"expression":"3*3"}`})
+			return nil
+		})
+	mockGenerator.EXPECT().GenerateChatResponse(gomock.Any(), "openai", gomock.Any(), gomock.Any()).
+		Return(errors.New("provider unavailable"))
+	mockToolRunRepo.EXPECT().CreateToolRun(gomock.Any(), gomock.Any()).Return(errors.New("write failed"))
+
+	produced, err := runner.Generate(context.Background(), "openai", &models.GenerationRequest{Model: "gpt-4o"},
+		service.ToolScope{UserID: "user-123", ServerTools: registry.Definitions()}, func(string) {}, nil)
+	assert.Error(t, err)
+	require.Len(t, produced, 2, "messages produced before the failure are kept")
+	assert.Equal(t, "9", produced[1].Text)
+}
+
+func TestToolRunner_GenerateSharedChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry, err := tools.NewRegistry(tools.NewCalculator(), tools.NewChatSearch(nil))
+	require.NoError(t, err)
+	mockGenerator := mocks.NewMockGeneratorInterface(ctrl)
+	runner := service.NewToolRunner(registry, mockGenerator, nil, 5)
+
+	var requests []*models.GenerationRequest
+	var forwarded []string
+	scriptedGenerator(t, mockGenerator, []scriptedTurn{
+		{calls: []models.ToolCall{{ID: "call-1", Name: "search_chats", Arguments: `{"query":"salary"}`}}},
+	}, &requests)
+
+	produced, err := runner.Generate(context.Background(), "openai", &models.GenerationRequest{
+		Model:      "gpt-4o",
+		Messages:   []models.Message{{Role: "user", Text: "What did I say about my salary?"}},
+		OnToolCall: func(call models.ToolCall) { forwarded = append(forwarded, call.ID) },
+	}, service.ToolScope{UserID: "user-123", ChatID: "chat-1", Shared: true, ServerTools: registry.Definitions()},
+		func(string) {}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	require.Len(t, requests[0].Tools, 1)
+	assert.Equal(t, "calculator", requests[0].Tools[0].Name, "search_chats is not offered in a shared chat")
+	for _, message := range produced {
+		assert.NotEqual(t, "tool", message.Role, "search_chats is not run in a shared chat")
+	}
+	assert.Equal(t, []string{"call-1"}, forwarded)
+}
+
+func TestToolRunner_ServerTools(t *testing.T) {
+	registry, err := tools.NewRegistry(tools.NewCalculator(), tools.NewDateTime(nil))
+	require.NoError(t, err)
+	runner := service.NewToolRunner(registry, nil, nil, 5)
+
+	definitions, err := runner.ServerTools([]string{"current_datetime"})
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+	assert.Equal(t, "current_datetime", definitions[0].Name)
+
+	_, err = runner.ServerTools([]string{"shell"})
+	assert.Error(t, err)
+}
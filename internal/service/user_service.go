@@ -18,10 +18,10 @@ func NewUserService(userRepo repository.UserRepositoryInterface) *UserService {
 	}
 }
 
-func (s *UserService) GetUsersChatList(ctx context.Context, userID string) ([]*models.UserChat, error) {
+func (s *UserService) GetUsersChatList(ctx context.Context, userID string, filter models.ChatListFilter) ([]*models.UserChat, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("user ID is required")
 	}
 
-	return s.userRepo.GetUsersChatList(ctx, userID)
+	return s.userRepo.GetUsersChatList(ctx, userID, filter)
 }
@@ -45,7 +45,7 @@ func TestUserService_GetUsersChatList(t *testing.T) {
 			userID: "user-123",
 			setup: func() {
 				mockRepo.EXPECT().
-					GetUsersChatList(gomock.Any(), "user-123").
+					GetUsersChatList(gomock.Any(), "user-123", models.ChatListFilter{}).
 					Return(testChats, nil)
 			},
 			want:    testChats,
@@ -63,7 +63,7 @@ func TestUserService_GetUsersChatList(t *testing.T) {
 			userID: "user-123",
 			setup: func() {
 				mockRepo.EXPECT().
-					GetUsersChatList(gomock.Any(), "user-123").
+					GetUsersChatList(gomock.Any(), "user-123", models.ChatListFilter{}).
 					Return(nil, fmt.Errorf("db error"))
 			},
 			want:    nil,
@@ -75,7 +75,7 @@ func TestUserService_GetUsersChatList(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
 
-			got, err := userService.GetUsersChatList(context.Background(), tt.userID)
+			got, err := userService.GetUsersChatList(context.Background(), tt.userID, models.ChatListFilter{})
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/service"
 )
 
@@ -29,3 +30,17 @@ func (s *AIStrategy) GenerateResponse(ctx context.Context, platform string,
 		return fmt.Errorf("unsupported platform: %s", platform)
 	}
 }
+
+func (s *AIStrategy) GenerateChatResponse(ctx context.Context, platform string,
+	req *models.GenerationRequest, callback func(string)) error {
+	switch platform {
+	case "gemini":
+		return s.aiService.GenerateChatResponse(ctx, req, callback)
+	case "openai":
+		return s.aiService.GenerateOpenAIChatResponse(ctx, req, callback)
+	case "deepseek":
+		return s.aiService.GenerateDeepSeekChatResponse(ctx, req, callback)
+	default:
+		return fmt.Errorf("unsupported platform: %s", platform)
+	}
+}
@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/lutefd/ai-router-go/internal/models"
 	"github.com/lutefd/ai-router-go/internal/strategy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 type MockAIService struct {
-	generateFunc         func(ctx context.Context, model string, prompt string, callback func(string)) error
-	generateOpenAIFunc   func(ctx context.Context, model string, prompt string, callback func(string)) error
-	generateDeepSeekFunc func(ctx context.Context, model string, prompt string, callback func(string)) error
+	generateFunc             func(ctx context.Context, model string, prompt string, callback func(string)) error
+	generateOpenAIFunc       func(ctx context.Context, model string, prompt string, callback func(string)) error
+	generateDeepSeekFunc     func(ctx context.Context, model string, prompt string, callback func(string)) error
+	generateChatFunc         func(ctx context.Context, req *models.GenerationRequest, callback func(string)) error
+	generateOpenAIChatFunc   func(ctx context.Context, req *models.GenerationRequest, callback func(string)) error
+	generateDeepSeekChatFunc func(ctx context.Context, req *models.GenerationRequest, callback func(string)) error
 }
 
 func (m *MockAIService) GenerateResponse(ctx context.Context, model string, prompt string, callback func(string)) error {
@@ -37,6 +41,27 @@ func (m *MockAIService) GenerateDeepSeekResponse(ctx context.Context, model stri
 	return nil
 }
 
+func (m *MockAIService) GenerateChatResponse(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	if m.generateChatFunc != nil {
+		return m.generateChatFunc(ctx, req, callback)
+	}
+	return nil
+}
+
+func (m *MockAIService) GenerateOpenAIChatResponse(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	if m.generateOpenAIChatFunc != nil {
+		return m.generateOpenAIChatFunc(ctx, req, callback)
+	}
+	return nil
+}
+
+func (m *MockAIService) GenerateDeepSeekChatResponse(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+	if m.generateDeepSeekChatFunc != nil {
+		return m.generateDeepSeekChatFunc(ctx, req, callback)
+	}
+	return nil
+}
+
 func TestAIStrategy_GenerateResponse(t *testing.T) {
 	mockService := &MockAIService{}
 	aiStrategy := strategy.NewAIStrategy(mockService)
@@ -137,3 +162,52 @@ func TestAIStrategy_GenerateResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestAIStrategy_GenerateChatResponse(t *testing.T) {
+	mockService := &MockAIService{}
+	aiStrategy := strategy.NewAIStrategy(mockService)
+
+	reply := func(text string) func(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+		return func(ctx context.Context, req *models.GenerationRequest, callback func(string)) error {
+			callback(text)
+			return nil
+		}
+	}
+	mockService.generateChatFunc = reply("gemini response")
+	mockService.generateOpenAIChatFunc = reply("openai response")
+	mockService.generateDeepSeekChatFunc = reply("deepseek response")
+
+	tests := []struct {
+		name     string
+		platform string
+		wantResp string
+		wantErr  bool
+	}{
+		{name: "gemini", platform: "gemini", wantResp: "gemini response"},
+		{name: "openai", platform: "openai", wantResp: "openai response"},
+		{name: "deepseek", platform: "deepseek", wantResp: "deepseek response"},
+		{name: "unsupported platform", platform: "unsupported", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response string
+			req := &models.GenerationRequest{
+				Model:    "some-model",
+				Messages: []models.Message{{Role: "user", Text: "hi"}},
+			}
+
+			err := aiStrategy.GenerateChatResponse(context.Background(), tt.platform, req, func(chunk string) {
+				response = chunk
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResp, response)
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package strategy
 
-import "context"
+import (
+	"context"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
 
 type AIStrategyInterface interface {
 	GenerateResponse(ctx context.Context, platform string, model string,
 		prompt string, callback func(string)) error
+	GenerateChatResponse(ctx context.Context, platform string,
+		req *models.GenerationRequest, callback func(string)) error
 }
@@ -0,0 +1,286 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const (
+	maxExpressionLength = 500
+	maxExpressionDepth  = 64
+)
+
+var calculatorFunctions = map[string]func(float64) float64{
+	"sqrt":  math.Sqrt,
+	"abs":   math.Abs,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"round": math.Round,
+	"ln":    math.Log,
+	"log10": math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+}
+
+var calculatorConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// Calculator evaluates arithmetic expressions with its own parser, so nothing
+// the model sends is ever executed as code.
+type Calculator struct{}
+
+func NewCalculator() *Calculator {
+	return &Calculator{}
+}
+
+func (c *Calculator) Definition() models.ToolDefinition {
+	return models.ToolDefinition{
+		Name: "calculator",
+		Description: "Evaluates an arithmetic expression. Supports + - * / % ^, parentheses, " +
+			"the constants pi and e, and the functions sqrt, abs, floor, ceil, round, ln, log10, sin, cos and tan.",
+		Parameters: json.RawMessage(`{"type":"object","properties":{"expression":{"type":"string",` +
+			`"description":"Expression to evaluate, e.g. (2 + 3) * 4 ^ 2"}},"required":["expression"]}`),
+	}
+}
+
+func (c *Calculator) Timeout() time.Duration {
+	return time.Second
+}
+
+func (c *Calculator) Call(ctx context.Context, inv Invocation) (string, error) {
+	var args struct {
+		Expression string `json:"expression"`
+	}
+	if err := decodeArguments(inv, &args); err != nil {
+		return "", err
+	}
+
+	result, err := Evaluate(args.Expression)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(result, 'g', 15, 64), nil
+}
+
+// Evaluate computes an arithmetic expression.
+func Evaluate(expression string) (float64, error) {
+	if strings.TrimSpace(expression) == "" {
+		return 0, fmt.Errorf("expression is empty")
+	}
+	if len(expression) > maxExpressionLength {
+		return 0, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+
+	p := &exprParser{input: expression}
+	value, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos+1)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("result is not a finite number")
+	}
+	return value, nil
+}
+
+// exprParser is a recursive descent parser over the grammar
+//
+//	expression = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/" | "%") unary }
+//	unary      = ("+" | "-") unary | power
+//	power      = primary [ "^" unary ]
+//	primary    = number | constant | function "(" expression ")" | "(" expression ")"
+type exprParser struct {
+	input string
+	pos   int
+	depth int
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) expression() (float64, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionDepth {
+		return 0, fmt.Errorf("expression is nested too deeply")
+	}
+
+	left, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			right, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			left += right
+		case '-':
+			p.pos++
+			right, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			left -= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *exprParser) term() (float64, error) {
+	left, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			left *= right
+		case '/':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			left /= right
+		case '%':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			left = math.Mod(left, right)
+		}
+	}
+}
+
+func (p *exprParser) unary() (float64, error) {
+	switch p.peek() {
+	case '+', '-':
+		op := p.input[p.pos]
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > maxExpressionDepth {
+			return 0, fmt.Errorf("expression is nested too deeply")
+		}
+		p.pos++
+		value, err := p.unary()
+		if op == '-' {
+			value = -value
+		}
+		return value, err
+	}
+	return p.power()
+}
+
+func (p *exprParser) power() (float64, error) {
+	base, err := p.primary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+	exponent, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exponent), nil
+}
+
+func (p *exprParser) primary() (float64, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		value, err := p.expression()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return value, nil
+	case c >= '0' && c <= '9' || c == '.':
+		return p.number()
+	case unicode.IsLetter(rune(c)):
+		return p.identifier()
+	case c == 0:
+		return 0, fmt.Errorf("unexpected end of expression")
+	default:
+		return 0, fmt.Errorf("unexpected %q at position %d", c, p.pos+1)
+	}
+}
+
+func (p *exprParser) number() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", p.input[start:p.pos])
+	}
+	return value, nil
+}
+
+func (p *exprParser) identifier() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+		p.pos++
+	}
+	name := strings.ToLower(p.input[start:p.pos])
+
+	if value, ok := calculatorConstants[name]; ok {
+		return value, nil
+	}
+	fn, ok := calculatorFunctions[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown name %q", name)
+	}
+	if p.peek() != '(' {
+		return 0, fmt.Errorf("function %s needs parentheses", name)
+	}
+	value, err := p.primary()
+	if err != nil {
+		return 0, err
+	}
+	return fn(value), nil
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/repository"
+)
+
+const (
+	defaultSearchResults = 5
+	maxSearchResults     = 20
+	snippetRadius        = 100
+)
+
+// ChatSearch searches the messages of the calling user's own chats. The chat
+// the call is made from is skipped since its messages are already in context.
+type ChatSearch struct {
+	chatRepo repository.ChatRepositoryInterface
+}
+
+func NewChatSearch(chatRepo repository.ChatRepositoryInterface) *ChatSearch {
+	return &ChatSearch{chatRepo: chatRepo}
+}
+
+type chatSearchResult struct {
+	ChatID    string    `json:"chat_id"`
+	Title     string    `json:"title"`
+	MessageID string    `json:"message_id"`
+	Role      string    `json:"role"`
+	SentAt    time.Time `json:"sent_at"`
+	Snippet   string    `json:"snippet"`
+}
+
+func (s *ChatSearch) Definition() models.ToolDefinition {
+	return models.ToolDefinition{
+		Name:        "search_chats",
+		Description: "Searches the user's earlier chats for messages containing all the given words, most recent first.",
+		Parameters: json.RawMessage(`{"type":"object","properties":{` +
+			`"query":{"type":"string","description":"Words to look for"},` +
+			`"limit":{"type":"integer","description":"Maximum number of results, up to 20"}},"required":["query"]}`),
+	}
+}
+
+// Private marks the results as the user's own, so they are never stored in a
+// chat other users read.
+func (s *ChatSearch) Private() bool {
+	return true
+}
+
+func (s *ChatSearch) Timeout() time.Duration {
+	return 5 * time.Second
+}
+
+func (s *ChatSearch) Call(ctx context.Context, inv Invocation) (string, error) {
+	var args struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArguments(inv, &args); err != nil {
+		return "", err
+	}
+	terms := strings.Fields(strings.ToLower(args.Query))
+	if len(terms) == 0 {
+		return "", fmt.Errorf("query is empty")
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultSearchResults
+	}
+	args.Limit = min(args.Limit, maxSearchResults)
+
+	results := []chatSearchResult{}
+	err := s.chatRepo.ForEachUserChat(ctx, inv.UserID, func(chat *models.Chat) error {
+		if chat.ID == inv.ChatID {
+			return nil
+		}
+		for _, message := range chat.Messages {
+			if message.Role != "user" && message.Role != "assistant" {
+				continue
+			}
+			index := matchTerms(message.Text, terms)
+			if index < 0 {
+				continue
+			}
+			results = append(results, chatSearchResult{
+				ChatID:    chat.ID,
+				Title:     chat.Title,
+				MessageID: message.ID,
+				Role:      message.Role,
+				SentAt:    message.SentAt,
+				Snippet:   snippet(message.Text, index),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].SentAt.After(results[j].SentAt)
+	})
+	if len(results) > args.Limit {
+		results = results[:args.Limit]
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// matchTerms returns the byte offset of the first term in text when every
+// term occurs in it, or -1.
+func matchTerms(text string, terms []string) int {
+	lower := strings.ToLower(text)
+	first := -1
+	for _, term := range terms {
+		index := strings.Index(lower, term)
+		if index < 0 {
+			return -1
+		}
+		if first < 0 || index < first {
+			first = index
+		}
+	}
+	return first
+}
+
+func snippet(text string, index int) string {
+	runes := []rune(text)
+	center := len([]rune(strings.ToLower(text)[:index]))
+	start := max(center-snippetRadius, 0)
+	end := min(center+snippetRadius, len(runes))
+
+	result := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
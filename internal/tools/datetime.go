@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+// DateTime tells the model the current date and time, which it cannot know
+// on its own.
+type DateTime struct {
+	now func() time.Time
+}
+
+func NewDateTime(now func() time.Time) *DateTime {
+	if now == nil {
+		now = time.Now
+	}
+	return &DateTime{now: now}
+}
+
+func (d *DateTime) Definition() models.ToolDefinition {
+	return models.ToolDefinition{
+		Name:        "current_datetime",
+		Description: "Returns the current date and time in an IANA timezone such as Europe/Lisbon. Defaults to UTC.",
+		Parameters: json.RawMessage(`{"type":"object","properties":{"timezone":{"type":"string",` +
+			`"description":"IANA timezone name"}}}`),
+	}
+}
+
+func (d *DateTime) Timeout() time.Duration {
+	return time.Second
+}
+
+func (d *DateTime) Call(ctx context.Context, inv Invocation) (string, error) {
+	var args struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := decodeArguments(inv, &args); err != nil {
+		return "", err
+	}
+	if args.Timezone == "" {
+		args.Timezone = "UTC"
+	}
+
+	location, err := time.LoadLocation(args.Timezone)
+	if err != nil {
+		return "", fmt.Errorf("unknown timezone %q", args.Timezone)
+	}
+	now := d.now().In(location)
+
+	result, err := json.Marshal(map[string]string{
+		"datetime": now.Format(time.RFC3339),
+		"timezone": location.String(),
+		"weekday":  now.Weekday().String(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
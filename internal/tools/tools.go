@@ -0,0 +1,120 @@
+// Package tools holds the tools the router runs itself when a model calls
+// them, as opposed to tools the client executes.
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/models"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Invocation is a single call of a tool on behalf of a user.
+type Invocation struct {
+	UserID    string
+	ChatID    string
+	Arguments json.RawMessage
+}
+
+type Tool interface {
+	// Definition returns the name, description and JSON Schema parameters
+	// sent to the model.
+	Definition() models.ToolDefinition
+	// Timeout bounds a single call. Zero uses the default of ten seconds.
+	Timeout() time.Duration
+	Call(ctx context.Context, inv Invocation) (string, error)
+}
+
+// PrivateTool is implemented by tools whose results expose the calling
+// user's own data. They are withheld when other users read the chat.
+type PrivateTool interface {
+	Private() bool
+}
+
+type Registry struct {
+	tools map[string]Tool
+}
+
+func NewRegistry(tools ...Tool) (*Registry, error) {
+	registry := &Registry{tools: make(map[string]Tool, len(tools))}
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+func (r *Registry) Register(tool Tool) error {
+	name := tool.Definition().Name
+	if name == "" {
+		return fmt.Errorf("tool has no name")
+	}
+	if _, ok := r.tools[name]; ok {
+		return fmt.Errorf("tool %s is already registered", name)
+	}
+	r.tools[name] = tool
+	return nil
+}
+
+func (r *Registry) Has(name string) bool {
+	_, ok := r.tools[name]
+	return ok
+}
+
+// Private reports whether the named tool exposes the calling user's data.
+func (r *Registry) Private(name string) bool {
+	tool, ok := r.tools[name].(PrivateTool)
+	return ok && tool.Private()
+}
+
+// Definitions returns the definitions of all registered tools, sorted by
+// name.
+func (r *Registry) Definitions() []models.ToolDefinition {
+	definitions := make([]models.ToolDefinition, 0, len(r.tools))
+	for _, tool := range r.tools {
+		definitions = append(definitions, tool.Definition())
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// Execute calls the named tool within its timeout.
+func (r *Registry) Execute(ctx context.Context, name string, inv Invocation) (string, error) {
+	tool, ok := r.tools[name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool %s", name)
+	}
+
+	timeout := tool.Timeout()
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := tool.Call(ctx, inv)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("tool %s timed out after %s", name, timeout)
+	}
+	return result, err
+}
+
+// decodeArguments reads the call arguments into v. Missing arguments decode
+// as an empty object.
+func decodeArguments(inv Invocation, v any) error {
+	if len(inv.Arguments) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(inv.Arguments, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
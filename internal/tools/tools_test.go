@@ -0,0 +1,178 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/models"
+	"github.com/lutefd/ai-router-go/internal/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type slowTool struct{}
+
+func (slowTool) Definition() models.ToolDefinition { return models.ToolDefinition{Name: "slow"} }
+func (slowTool) Timeout() time.Duration            { return 10 * time.Millisecond }
+func (slowTool) Call(ctx context.Context, inv tools.Invocation) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := tools.NewRegistry(tools.NewCalculator(), tools.NewDateTime(nil), slowTool{})
+	require.NoError(t, err)
+
+	assert.True(t, registry.Has("calculator"))
+	assert.False(t, registry.Has("shell"))
+	assert.Error(t, registry.Register(tools.NewCalculator()), "names are unique")
+
+	definitions := registry.Definitions()
+	require.Len(t, definitions, 3)
+	assert.Equal(t, "calculator", definitions[0].Name)
+	assert.Equal(t, "current_datetime", definitions[1].Name)
+
+	result, err := registry.Execute(context.Background(), "calculator",
+		tools.Invocation{Arguments: json.RawMessage(`{"expression":"6*7"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "42", result)
+
+	_, err = registry.Execute(context.Background(), "slow", tools.Invocation{})
+	assert.ErrorContains(t, err, "timed out")
+
+	_, err = registry.Execute(context.Background(), "shell", tools.Invocation{})
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       float64
+		wantErr    bool
+	}{
+		{expression: "1 + 2 * 3", want: 7},
+		{expression: "(1 + 2) * 3", want: 9},
+		{expression: "10 / 4", want: 2.5},
+		{expression: "10 % 4", want: 2},
+		{expression: "2 ^ 3 ^ 2", want: 512},
+		{expression: "-2 ^ 2", want: -4},
+		{expression: "2 ^ -1", want: 0.5},
+		{expression: "--3", want: 3},
+		{expression: "sqrt(16) + abs(-2)", want: 6},
+		{expression: "round(pi * 100) / 100", want: 3.14},
+		{expression: "1.5e3", wantErr: true},
+		{expression: "", wantErr: true},
+		{expression: "1 / 0", wantErr: true},
+		{expression: "sqrt(-1)", wantErr: true},
+		{expression: "(1 + 2", wantErr: true},
+		{expression: "1 + ", wantErr: true},
+		{expression: "os.Exit(1)", wantErr: true},
+		{expression: "sqrt 4", wantErr: true},
+		{expression: "1 2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := tools.Evaluate(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestEvaluate_DeepNesting(t *testing.T) {
+	expression := ""
+	for i := 0; i < 100; i++ {
+		expression += "("
+	}
+	expression += "1"
+	for i := 0; i < 100; i++ {
+		expression += ")"
+	}
+
+	_, err := tools.Evaluate(expression)
+	assert.ErrorContains(t, err, "nested too deeply")
+}
+
+func TestDateTime(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC)
+	tool := tools.NewDateTime(func() time.Time { return now })
+
+	result, err := tool.Call(context.Background(), tools.Invocation{
+		Arguments: json.RawMessage(`{"timezone":"America/Sao_Paulo"}`),
+	})
+	require.NoError(t, err)
+	var got map[string]string
+	require.NoError(t, json.Unmarshal([]byte(result), &got))
+	assert.Equal(t, "2025-03-10T09:30:00-03:00", got["datetime"])
+	assert.Equal(t, "Monday", got["weekday"])
+
+	result, err = tool.Call(context.Background(), tools.Invocation{})
+	require.NoError(t, err)
+	assert.Contains(t, result, `"timezone":"UTC"`)
+
+	_, err = tool.Call(context.Background(), tools.Invocation{Arguments: json.RawMessage(`{"timezone":"Mars/Base"}`)})
+	assert.Error(t, err)
+}
+
+func TestChatSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepositoryInterface(ctrl)
+	tool := tools.NewChatSearch(mockChatRepo)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	chats := []*models.Chat{
+		{ID: "chat-1", Title: "Trip", Messages: []models.Message{
+			{ID: "msg-1", Role: "user", Text: "Plan a trip to Lisbon in May", SentAt: day(1)},
+			{ID: "msg-2", Role: "assistant", Text: "Lisbon in May is warm.", SentAt: day(2)},
+			{ID: "msg-3", Role: "tool", Text: "Lisbon may", SentAt: day(3)},
+		}},
+		{ID: "chat-2", Title: "Current", Messages: []models.Message{
+			{ID: "msg-4", Role: "user", Text: "lisbon in may again", SentAt: day(4)},
+		}},
+		{ID: "chat-3", Title: "Food", Messages: []models.Message{
+			{ID: "msg-5", Role: "user", Text: "Best pastries in LISBON, not in May", SentAt: day(5)},
+			{ID: "msg-6", Role: "user", Text: "Porto in May", SentAt: day(6)},
+		}},
+	}
+	mockChatRepo.EXPECT().ForEachUserChat(gomock.Any(), "user-123", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, fn func(*models.Chat) error) error {
+			for _, chat := range chats {
+				if err := fn(chat); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	result, err := tool.Call(context.Background(), tools.Invocation{
+		UserID:    "user-123",
+		ChatID:    "chat-2",
+		Arguments: json.RawMessage(`{"query":"lisbon MAY","limit":2}`),
+	})
+	require.NoError(t, err)
+
+	var got []struct {
+		ChatID    string `json:"chat_id"`
+		MessageID string `json:"message_id"`
+		Snippet   string `json:"snippet"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result), &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, "msg-5", got[0].MessageID)
+	assert.Equal(t, "msg-2", got[1].MessageID)
+	assert.Equal(t, "Lisbon in May is warm.", got[1].Snippet)
+
+	_, err = tool.Call(context.Background(), tools.Invocation{UserID: "user-123", Arguments: json.RawMessage(`{}`)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type MemoryExtractor struct {
+	memoryService service.MemoryServiceInterface
+	idle          time.Duration
+	interval      time.Duration
+}
+
+func NewMemoryExtractor(memoryService service.MemoryServiceInterface, idle time.Duration,
+	interval time.Duration) *MemoryExtractor {
+	return &MemoryExtractor{
+		memoryService: memoryService,
+		idle:          idle,
+		interval:      interval,
+	}
+}
+
+// Run extracts memories from chats idle for longer than the configured
+// duration, immediately and then on every interval until ctx is cancelled.
+func (e *MemoryExtractor) Run(ctx context.Context) {
+	Every(ctx, e.interval, e.extract)
+}
+
+func (e *MemoryExtractor) extract(ctx context.Context) {
+	added, err := e.memoryService.ExtractFromIdleChats(ctx, e.idle)
+	if err != nil {
+		log.Printf("Error extracting memories: %v", err)
+		return
+	}
+	if added > 0 {
+		log.Printf("Extracted %d memories from idle chats", added)
+	}
+}
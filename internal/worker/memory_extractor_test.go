@@ -0,0 +1,39 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/worker"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMemoryExtractor_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMemoryService := mocks.NewMockMemoryServiceInterface(ctrl)
+	extractor := worker.NewMemoryExtractor(mockMemoryService, 30*time.Minute, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockMemoryService.EXPECT().
+		ExtractFromIdleChats(gomock.Any(), 30*time.Minute).
+		DoAndReturn(func(ctx context.Context, idle time.Duration) (int, error) {
+			cancel()
+			return 2, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		extractor.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("extractor did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,24 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// Every calls fn immediately and then on every interval until ctx is
+// cancelled. Calls never overlap; a tick that arrives while fn is running is
+// delayed until it returns.
+func Every(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
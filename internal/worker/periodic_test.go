@@ -0,0 +1,49 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/worker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	done := make(chan struct{})
+	go func() {
+		worker.Every(ctx, time.Millisecond, func(ctx context.Context) {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Every did not stop after context cancellation")
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestEvery_RunsImmediately(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	called := make(chan struct{}, 1)
+
+	go worker.Every(ctx, time.Hour, func(ctx context.Context) {
+		called <- struct{}{}
+		cancel()
+	})
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("fn was not called before the first tick")
+	}
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type PromptCacheCleaner struct {
+	promptCache service.PromptCacheManagerInterface
+	interval    time.Duration
+}
+
+func NewPromptCacheCleaner(promptCache service.PromptCacheManagerInterface,
+	interval time.Duration) *PromptCacheCleaner {
+	return &PromptCacheCleaner{
+		promptCache: promptCache,
+		interval:    interval,
+	}
+}
+
+// Run forgets expired prompt caches immediately and then on every interval
+// until ctx is cancelled.
+func (c *PromptCacheCleaner) Run(ctx context.Context) {
+	Every(ctx, c.interval, c.clean)
+}
+
+func (c *PromptCacheCleaner) clean(ctx context.Context) {
+	deleted, err := c.promptCache.DeleteExpired(ctx)
+	if err != nil {
+		log.Printf("Error cleaning up prompt caches: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Cleaned up %d expired prompt caches", deleted)
+	}
+}
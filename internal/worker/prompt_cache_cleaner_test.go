@@ -0,0 +1,39 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/worker"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPromptCacheCleaner_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPromptCache := mocks.NewMockPromptCacheManagerInterface(ctrl)
+	cleaner := worker.NewPromptCacheCleaner(mockPromptCache, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockPromptCache.EXPECT().
+		DeleteExpired(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) (int, error) {
+			cancel()
+			return 2, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		cleaner.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cleaner did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/service"
+)
+
+type TrashPurger struct {
+	chatService service.ChatServiceInterface
+	retention   time.Duration
+	interval    time.Duration
+}
+
+func NewTrashPurger(chatService service.ChatServiceInterface, retention time.Duration,
+	interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		chatService: chatService,
+		retention:   retention,
+		interval:    interval,
+	}
+}
+
+// Run purges expired trash immediately and then on every interval until ctx
+// is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	Every(ctx, p.interval, p.purge)
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	purged, err := p.chatService.PurgeTrash(ctx, p.retention)
+	if err != nil {
+		log.Printf("Error purging trashed chats: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("Purged %d trashed chats older than %s", purged, p.retention)
+	}
+}
@@ -0,0 +1,39 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lutefd/ai-router-go/internal/mocks"
+	"github.com/lutefd/ai-router-go/internal/worker"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTrashPurger_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatService := mocks.NewMockChatServiceInterface(ctrl)
+	purger := worker.NewTrashPurger(mockChatService, 30*24*time.Hour, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockChatService.EXPECT().
+		PurgeTrash(gomock.Any(), 30*24*time.Hour).
+		DoAndReturn(func(ctx context.Context, retention time.Duration) (int64, error) {
+			cancel()
+			return 3, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		purger.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop after context cancellation")
+	}
+}
@@ -15,5 +15,9 @@ func Init(workerID int64) error {
 }
 
 func Generate() string {
-	return fmt.Sprintf("chat_%d", node.Generate())
+	return GenerateWithPrefix("chat")
+}
+
+func GenerateWithPrefix(prefix string) string {
+	return fmt.Sprintf("%s_%d", prefix, node.Generate())
 }
@@ -16,6 +16,13 @@ func TestGenerateID(t *testing.T) {
 	assert.Contains(t, id1, "chat")
 }
 
+func TestGenerateWithPrefix(t *testing.T) {
+	_ = idgen.Init(1)
+	id := idgen.GenerateWithPrefix("folder")
+
+	assert.Regexp(t, `^folder_\d+$`, id)
+}
+
 func TestConcurrency(t *testing.T) {
 	_ = idgen.Init(1)
 	ids := make(chan string, 1000)